@@ -0,0 +1,98 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/sqve/kamaji/internal/domain"
+	"github.com/sqve/kamaji/internal/output"
+	"github.com/sqve/kamaji/internal/statemachine"
+)
+
+func gotoCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "goto <ticket>[/<task>]",
+		Short: "Move a ticket to a specific task",
+		Long: "Set where a ticket resumes, rewinding or skipping ahead, with fresh attempts.\n\n" +
+			"The task is a 1-based number, a task id or an exact task description and defaults\n" +
+			"to the first task. Git branches are left as they are. The move is recorded in the\n" +
+			"ticket history. Refuses to run while a sprint is running.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			return editState(func(e *stateEdit) error {
+				ticket, taskIndex, err := e.target(args[0])
+				if err != nil {
+					return err
+				}
+
+				from := statemachine.Progress(e.state, ticket).CurrentTask
+				task := &ticket.Tasks[taskIndex]
+				action := domain.ManualAction{
+					Action: "goto",
+					Task:   task.Description,
+					Detail: "moved from " + taskPosition(ticket, from),
+				}
+				if err := e.apply(ticket, action, func() { statemachine.Goto(e.state, ticket, taskIndex) }); err != nil {
+					return err
+				}
+
+				output.PrintSuccess(fmt.Sprintf("Moved %s to task %d: %s", ticket.Name, taskIndex+1, task.Description))
+				if next := statemachine.NextTask(e.state, e.sprint); next == nil || next.Ticket.Name != ticket.Name {
+					output.PrintWarning(ticket.Name + " is not next: earlier tickets or dependencies run first")
+				}
+				return nil
+			})
+		},
+	}
+
+	cmd.SilenceUsage = true
+
+	return cmd
+}
+
+// target resolves "<ticket>[/<task>]" to a ticket and task index. Ticket names
+// may contain slashes, so the whole argument is tried as a ticket name first.
+func (e *stateEdit) target(arg string) (*domain.Ticket, int, error) {
+	ticket, spec := statemachine.FindTicket(e.sprint, arg), ""
+	if ticket == nil {
+		i := strings.LastIndex(arg, "/")
+		if i < 0 {
+			return nil, 0, fmt.Errorf("unknown ticket: %s", arg)
+		}
+		if ticket = statemachine.FindTicket(e.sprint, arg[:i]); ticket == nil {
+			return nil, 0, fmt.Errorf("unknown ticket: %s", arg[:i])
+		}
+		spec = arg[i+1:]
+	}
+
+	if len(ticket.Tasks) == 0 {
+		return nil, 0, fmt.Errorf("ticket %q has no tasks", ticket.Name)
+	}
+	if spec == "" {
+		return ticket, 0, nil
+	}
+
+	if n, err := strconv.Atoi(spec); err == nil {
+		if n < 1 || n > len(ticket.Tasks) {
+			return nil, 0, fmt.Errorf("task %d out of range: ticket %q has %d tasks", n, ticket.Name, len(ticket.Tasks))
+		}
+		return ticket, n - 1, nil
+	}
+	for i := range ticket.Tasks {
+		if ticket.Tasks[i].Key() == spec || ticket.Tasks[i].Description == spec {
+			return ticket, i, nil
+		}
+	}
+	return nil, 0, fmt.Errorf("no task %q in ticket %q", spec, ticket.Name)
+}
+
+// taskPosition describes a ticket position for the history, such as "task 2".
+func taskPosition(ticket *domain.Ticket, index int) string {
+	if index >= len(ticket.Tasks) {
+		return "complete"
+	}
+	return fmt.Sprintf("task %d", index+1)
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/spf13/cobra"
+
+	"github.com/sqve/kamaji/internal/config"
+	"github.com/sqve/kamaji/internal/domain"
+	"github.com/sqve/kamaji/internal/output"
+)
+
+func historyCmd() *cobra.Command {
+	var (
+		kind       string
+		jsonOutput bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "history [ticket]",
+		Short: "Show completed tasks, failed attempts, insights, plans, questions and manual actions per ticket",
+		Long: "Show the ticket history recorded under .kamaji/history, with aggregate totals.\n\n" +
+			"Pass a ticket name to show a single ticket, and --type to show only completed tasks,\n" +
+			"failed attempts, insights, plans, questions or manual actions.",
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			workDir, err := os.Getwd()
+			if err != nil {
+				return err
+			}
+
+			histories, err := config.ListTicketHistories(workDir)
+			if err != nil {
+				return err
+			}
+			sortBySprint(histories, filepath.Join(workDir, configFile))
+
+			if len(args) == 1 {
+				histories = selectTicket(histories, args[0])
+				if len(histories) == 0 {
+					return fmt.Errorf("no history for ticket %q", args[0])
+				}
+			}
+
+			for i, h := range histories {
+				if histories[i], err = config.FilterHistory(h, kind); err != nil {
+					return err
+				}
+			}
+			totals := config.GetAllHistoriesSummary(histories)
+
+			if jsonOutput {
+				return output.PrintJSON(output.NewHistoryReport(histories, totals))
+			}
+			output.PrintHistory(histories, totals)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&kind, "type", "", "Only show entries of this type: completed, failed, insights, plans, questions or manual")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output history as JSON")
+
+	cmd.SilenceUsage = true
+
+	return cmd
+}
+
+// sortBySprint orders histories as their tickets appear in the sprint, keeping
+// file order when the sprint cannot be loaded. Unknown tickets sort last.
+func sortBySprint(histories []*domain.TicketHistory, sprintPath string) {
+	sprint, err := config.LoadSprint(sprintPath)
+	if err != nil {
+		return
+	}
+
+	order := make(map[string]int, len(sprint.Tickets))
+	for i, t := range sprint.Tickets {
+		order[t.Name] = i
+	}
+	rank := func(h *domain.TicketHistory) int {
+		if i, ok := order[h.Ticket]; ok {
+			return i
+		}
+		return len(order)
+	}
+
+	sort.SliceStable(histories, func(i, j int) bool {
+		return rank(histories[i]) < rank(histories[j])
+	})
+}
+
+func selectTicket(histories []*domain.TicketHistory, name string) []*domain.TicketHistory {
+	for _, h := range histories {
+		if h.Ticket == name {
+			return []*domain.TicketHistory{h}
+		}
+	}
+	return nil
+}
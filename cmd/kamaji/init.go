@@ -19,11 +19,59 @@ name: my-sprint
 # Typically "main" or "develop"
 base_branch: main
 
+# Optional coding agent: claude (default), codex, aider or custom
+# A custom agent runs any command and gets the prompt on stdin, as its last
+# argument (prompt: arg) or in a file (prompt: file)
+# Aider cannot report task_complete, so every task needs a verify_cmd with it
+# agent: claude
+# agent:
+#   name: custom
+#   command: [my-agent, --prompt, "{prompt_file}"]
+#   prompt: file
+
+# Optional limits per agent session, overridable on tickets and tasks
+# timeout stops a session after a wall-clock duration; idle_timeout stops it
+# after a period without output or progress reports. Timed out sessions count as failures.
+# timeout: 45m
+# idle_timeout: 10m
+
+# Optional wait for an answer to an agent's ask_human question, after which
+# the agent decides itself
+# question_timeout: 10m
+
+# Optional retry policy, overridable on tickets and tasks
+# max_attempts is how many times a task may fail before the sprint is stuck
+# retry_delay waits between attempts and retry_backoff multiplies that wait
+# for each further retry
+# max_attempts: 3
+# retry_delay: 30s
+# retry_backoff: 2
+
+# Optional model and reasoning effort (low, medium or high), overridable on
+# tickets and tasks. escalation picks a model per attempt instead, so retries
+# after a failure move to a stronger model; the last one repeats.
+# model: sonnet
+# effort: medium
+# escalation: [haiku, sonnet, opus]
+
+# Optional read-only planning session before each attempt, overridable on
+# tickets and tasks. Its plan is stored in the ticket history and handed to
+# the session that makes the change.
+# plan: true
+
+# Optional read-only review of each pass before it is committed, overridable on
+# tickets and tasks. A rejection counts as a failed attempt and its reasons are
+# given to the next one.
+# review: true
+
 # Rules for the AI agent to follow during this sprint
 # These guidelines help maintain code quality and consistency
+# Tickets and tasks may add their own rules; give a rule an id to let them
+# override it ({id: tests, text: ...}) or drop it ({id: tests, remove: true})
 rules:
   - Follow existing code patterns
-  - Write tests for new functionality
+  - id: tests
+    text: Write tests for new functionality
   - Keep commits atomic and well-documented
 
 # Tickets define the work items in this sprint
@@ -31,6 +79,10 @@ tickets:
   # Ticket name (required) - used as identifier
   - name: example-ticket
 
+    # Optional stable id for saved progress (defaults to the name)
+    # Set it before renaming a ticket mid-sprint
+    # id: example
+
     # Git branch name for this ticket
     # Created from base_branch when the ticket starts
     branch: feature/example-ticket
@@ -38,11 +90,29 @@ tickets:
     # What this ticket accomplishes
     description: Example ticket demonstrating the configuration format
 
+    # Optional tickets that must complete before this one starts
+    # Their branches are merged into this ticket's branch
+    # depends_on: [other-ticket]
+
+    # Optional rules that apply only to this ticket's tasks
+    # rules:
+    #   - Keep changes inside the example package
+
+    # Optional reference files embedded in every task prompt
+    # Accepts paths, globs (** for any depth) and @file references
+    # context:
+    #   - "@README.md"
+    #   - "docs/**/*.md"
+
     # Tasks break down the ticket into smaller units of work
     tasks:
       # Task description (required) - what this task does
       - description: Implement the example feature
 
+        # Optional stable id for saved progress (defaults to the description)
+        # Set it before rewording a task mid-sprint
+        # id: implement
+
         # Optional step-by-step guidance for the agent
         steps:
           - Create the necessary files
@@ -51,6 +121,27 @@ tickets:
 
         # How to verify the task is complete
         verify: Run tests and confirm feature works as expected
+
+        # Optional command kamaji runs after the agent reports a pass
+        # A failing command turns the pass into a failed attempt, as does one
+        # still running after verify_timeout (defaults to the session timeout,
+        # or 30m without one)
+        # verify_cmd: go test ./...
+        # verify_timeout: 10m
+
+        # Optional pass condition for verify_cmd (defaults to exit code 0)
+        # done:
+        #   exit_code: 0
+        #   output: "ok"
+
+        # Optional reference files for this task, added after the ticket's
+        # context:
+        #   - "internal/example/*.go"
+
+        # Optional rules for this task only, e.g. dropping an inherited rule
+        # rules:
+        #   - id: tests
+        #     remove: true
 `
 
 func initCmd() *cobra.Command {
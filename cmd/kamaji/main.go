@@ -27,8 +27,17 @@ func rootCmd() *cobra.Command {
 		SilenceErrors: true,
 	}
 
+	cmd.AddCommand(gotoCmd())
+	cmd.AddCommand(historyCmd())
 	cmd.AddCommand(initCmd())
+	cmd.AddCommand(proposalsCmd())
+	cmd.AddCommand(replayCmd())
+	cmd.AddCommand(resetCmd())
+	cmd.AddCommand(retryCmd())
+	cmd.AddCommand(skipCmd())
 	cmd.AddCommand(startCmd())
+	cmd.AddCommand(statsCmd())
+	cmd.AddCommand(statusCmd())
 	cmd.AddCommand(validateCmd())
 
 	return cmd
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/sqve/kamaji/internal/config"
+	"github.com/sqve/kamaji/internal/domain"
+	"github.com/sqve/kamaji/internal/statemachine"
+)
+
+// stateEdit is the sprint and state a manual command works on while it holds
+// the run lock.
+type stateEdit struct {
+	workDir string
+	sprint  *domain.Sprint
+	state   *domain.State
+}
+
+// editState loads the sprint and state under the run lock and runs fn, so
+// manual changes never race a running sprint.
+func editState(fn func(e *stateEdit) error) error {
+	workDir, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	sprint, err := config.LoadSprint(filepath.Join(workDir, configFile))
+	if err != nil {
+		return err
+	}
+
+	unlock, err := config.AcquireRunLock(workDir)
+	if err != nil {
+		if errors.Is(err, config.ErrSprintActive) {
+			return fmt.Errorf("%w; wait for it to finish or stop it before changing state", err)
+		}
+		return err
+	}
+	defer unlock()
+
+	state, err := config.LoadState(workDir)
+	if err != nil {
+		return err
+	}
+	statemachine.MigrateState(state, sprint)
+	statemachine.AdoptIDs(state, sprint)
+
+	return fn(&stateEdit{workDir: workDir, sprint: sprint, state: state})
+}
+
+// apply runs mutate, saves the state and records action in the ticket history
+// and the event journal.
+func (e *stateEdit) apply(ticket *domain.Ticket, action domain.ManualAction, mutate func()) error {
+	mutate()
+	if err := config.SaveState(e.workDir, e.state); err != nil {
+		return err
+	}
+	if err := config.AppendProgress(e.workDir, e.state, ticket.Key(), ticket.Name); err != nil {
+		return err
+	}
+	if err := config.RecordManualAction(e.workDir, ticket.Name, action); err != nil {
+		return err
+	}
+	return config.AppendEvent(e.workDir, domain.Event{
+		Type: domain.EventManualAction, Ticket: ticket.Name, Task: action.Task, Action: &action,
+	})
+}
+
+// ticket returns the named ticket, or the current one when args is empty. The
+// current ticket is the first ready ticket that is stuck, falling back to the
+// ticket of the next task.
+func (e *stateEdit) ticket(args []string) (*domain.Ticket, error) {
+	if len(args) > 0 {
+		ticket := statemachine.FindTicket(e.sprint, args[0])
+		if ticket == nil {
+			return nil, fmt.Errorf("unknown ticket: %s", args[0])
+		}
+		return ticket, nil
+	}
+
+	ready := statemachine.ReadyTickets(e.state, e.sprint)
+	for _, i := range ready {
+		if statemachine.IsStuck(e.state, e.sprint, &e.sprint.Tickets[i]) {
+			return &e.sprint.Tickets[i], nil
+		}
+	}
+	if len(ready) == 0 {
+		return nil, errors.New("no current task: the sprint is complete or blocked")
+	}
+	return &e.sprint.Tickets[ready[0]], nil
+}
+
+// currentTask returns the ticket's current task, failing once the ticket is complete.
+func (e *stateEdit) currentTask(ticket *domain.Ticket) (*domain.Task, error) {
+	progress := statemachine.Progress(e.state, ticket)
+	if progress.CurrentTask >= len(ticket.Tasks) {
+		return nil, fmt.Errorf("ticket %q is already complete", ticket.Name)
+	}
+	return &ticket.Tasks[progress.CurrentTask], nil
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/sqve/kamaji/internal/config"
+	"github.com/sqve/kamaji/internal/domain"
+	"github.com/sqve/kamaji/internal/output"
+)
+
+func proposalsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "proposals",
+		Short: "Review follow-up tasks proposed by agents",
+		Long: "Agents propose follow-up tasks with the propose_task tool. Proposals wait in\n" +
+			".kamaji/proposals.yaml until they are accepted into kamaji.yaml or rejected.",
+	}
+
+	cmd.AddCommand(proposalsListCmd())
+	cmd.AddCommand(proposalsAcceptCmd())
+	cmd.AddCommand(proposalsRejectCmd())
+
+	return cmd
+}
+
+func proposalsListCmd() *cobra.Command {
+	var all bool
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "Show pending proposals",
+		Args:  cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			workDir, err := os.Getwd()
+			if err != nil {
+				return err
+			}
+
+			proposals, err := config.LoadProposals(workDir)
+			if err != nil {
+				return err
+			}
+			if !all {
+				pending := proposals[:0]
+				for _, p := range proposals {
+					if p.Status == domain.ProposalPending {
+						pending = append(pending, p)
+					}
+				}
+				proposals = pending
+			}
+
+			output.PrintProposals(proposals)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&all, "all", false, "Include accepted and rejected proposals")
+
+	cmd.SilenceUsage = true
+
+	return cmd
+}
+
+func proposalsAcceptCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "accept <id>",
+		Short: "Append a proposed task to its ticket in kamaji.yaml",
+		Long: "Append the proposed task to the end of its ticket's tasks in kamaji.yaml, leaving\n" +
+			"the rest of the file as it is. The sprint picks it up on the next start. Refuses\n" +
+			"to run while a sprint is running.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			id, err := parseProposalID(args[0])
+			if err != nil {
+				return err
+			}
+
+			return editState(func(e *stateEdit) error {
+				p, err := config.PendingProposal(e.workDir, id)
+				if err != nil {
+					return err
+				}
+				if err := config.AppendTask(filepath.Join(e.workDir, configFile), p.Ticket, p.NewTask()); err != nil {
+					return err
+				}
+				if err := resolveProposal(e.workDir, p, domain.ProposalAccepted); err != nil {
+					return err
+				}
+
+				output.PrintSuccess(fmt.Sprintf("Added task to %s: %s", p.Ticket, p.Description))
+				return nil
+			})
+		},
+	}
+
+	cmd.SilenceUsage = true
+
+	return cmd
+}
+
+func proposalsRejectCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "reject <id>",
+		Short: "Drop a proposed task",
+		Long:  "Mark the proposal rejected so it is no longer listed. Refuses to run while a\nsprint is running.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			id, err := parseProposalID(args[0])
+			if err != nil {
+				return err
+			}
+
+			return editState(func(e *stateEdit) error {
+				p, err := config.PendingProposal(e.workDir, id)
+				if err != nil {
+					return err
+				}
+				if err := resolveProposal(e.workDir, p, domain.ProposalRejected); err != nil {
+					return err
+				}
+
+				output.PrintSuccess(fmt.Sprintf("Rejected proposal #%d: %s", p.ID, p.Description))
+				return nil
+			})
+		},
+	}
+
+	cmd.SilenceUsage = true
+
+	return cmd
+}
+
+// parseProposalID accepts a proposal id with or without its leading #.
+func parseProposalID(arg string) (int, error) {
+	id, err := strconv.Atoi(strings.TrimPrefix(arg, "#"))
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("invalid proposal id: %s", arg)
+	}
+	return id, nil
+}
+
+// resolveProposal sets the proposal's status and journals the decision.
+func resolveProposal(workDir string, p domain.Proposal, status string) error {
+	if err := config.ResolveProposal(workDir, p.ID, status); err != nil {
+		return err
+	}
+	return config.AppendEvent(workDir, domain.Event{
+		Type: domain.EventProposal, Ticket: p.Ticket, Status: status, Summary: p.Description,
+	})
+}
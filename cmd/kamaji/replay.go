@@ -0,0 +1,108 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/spf13/cobra"
+
+	"github.com/sqve/kamaji/internal/config"
+	"github.com/sqve/kamaji/internal/domain"
+	"github.com/sqve/kamaji/internal/output"
+)
+
+func replayCmd() *cobra.Command {
+	var write bool
+
+	cmd := &cobra.Command{
+		Use:   "replay",
+		Short: "Rebuild state and ticket history from the event journal",
+		Long: "Print the event journal recorded in .kamaji/events.jsonl as a timeline, rebuild the\n" +
+			"sprint state and ticket histories from it, and compare the state with .kamaji/state.yaml.\n\n" +
+			"With --write, the rebuilt state and histories replace the saved ones. History recorded\n" +
+			"before the journal existed is lost for the tickets that are rewritten. Refuses to write\n" +
+			"while a sprint is running.",
+		Args: cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			workDir, err := os.Getwd()
+			if err != nil {
+				return err
+			}
+
+			events, err := config.LoadEvents(workDir)
+			if err != nil {
+				return err
+			}
+			if len(events) == 0 {
+				output.PrintInfo("No events recorded")
+				return nil
+			}
+
+			output.PrintEvents(events)
+			state, histories := config.Replay(events)
+
+			if !write {
+				saved, err := config.LoadState(workDir)
+				if err != nil {
+					return err
+				}
+				if sameState(saved, state) {
+					output.PrintSuccess(fmt.Sprintf("Replayed %d events: state matches .kamaji/state.yaml", len(events)))
+				} else {
+					output.PrintWarning(fmt.Sprintf("Replayed %d events: state differs from .kamaji/state.yaml; run with --write to restore it", len(events)))
+				}
+				return nil
+			}
+
+			return restoreReplay(workDir, state, histories, len(events))
+		},
+	}
+
+	cmd.Flags().BoolVar(&write, "write", false, "Replace saved state and ticket histories with the replayed ones")
+
+	cmd.SilenceUsage = true
+
+	return cmd
+}
+
+// restoreReplay saves the replayed state and histories under the run lock.
+func restoreReplay(workDir string, state *domain.State, histories []*domain.TicketHistory, events int) error {
+	unlock, err := config.AcquireRunLock(workDir)
+	if err != nil {
+		if errors.Is(err, config.ErrSprintActive) {
+			return fmt.Errorf("%w; wait for it to finish or stop it before restoring state", err)
+		}
+		return err
+	}
+	defer unlock()
+
+	if err := config.SaveState(workDir, state); err != nil {
+		return err
+	}
+	for _, h := range histories {
+		if err := config.SaveTicketHistory(workDir, h); err != nil {
+			return err
+		}
+	}
+
+	output.PrintSuccess(fmt.Sprintf("Restored state and %d ticket histories from %d events", len(histories), events))
+	return nil
+}
+
+// sameState reports whether two states hold the same progress, treating
+// empty and missing done and skipped lists alike.
+func sameState(a, b *domain.State) bool {
+	if len(a.Tickets) != len(b.Tickets) {
+		return false
+	}
+	for key, ta := range a.Tickets {
+		tb, ok := b.Tickets[key]
+		if !ok || !slices.Equal(ta.Done, tb.Done) || !slices.Equal(ta.Skipped, tb.Skipped) ||
+			ta.FailingTask != tb.FailingTask || ta.FailureCount != tb.FailureCount {
+			return false
+		}
+	}
+	return true
+}
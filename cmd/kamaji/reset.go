@@ -0,0 +1,59 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/sqve/kamaji/internal/domain"
+	"github.com/sqve/kamaji/internal/output"
+	"github.com/sqve/kamaji/internal/statemachine"
+)
+
+func resetCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "reset [ticket]",
+		Short: "Clear sprint progress so tickets start over",
+		Long: "Forget the progress of every ticket, or of a single ticket, so they start over from\n" +
+			"their first task. Git branches and ticket history are kept. The reset is recorded in\n" +
+			"the ticket history. Refuses to run while a sprint is running.",
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			return editState(func(e *stateEdit) error {
+				var tickets []*domain.Ticket
+				if len(args) > 0 {
+					ticket, err := e.ticket(args)
+					if err != nil {
+						return err
+					}
+					tickets = append(tickets, ticket)
+				} else {
+					for i := range e.sprint.Tickets {
+						tickets = append(tickets, &e.sprint.Tickets[i])
+					}
+				}
+
+				reset := 0
+				for _, ticket := range tickets {
+					if _, ok := e.state.Tickets[ticket.Key()]; !ok {
+						continue
+					}
+					from := statemachine.Progress(e.state, ticket).CurrentTask
+					action := domain.ManualAction{Action: "reset", Detail: "from " + taskPosition(ticket, from)}
+					if err := e.apply(ticket, action, func() { statemachine.Reset(e.state, ticket) }); err != nil {
+						return err
+					}
+					output.PrintSuccess("Reset ticket: " + ticket.Name)
+					reset++
+				}
+
+				if reset == 0 {
+					output.PrintInfo("No progress to reset")
+				}
+				return nil
+			})
+		},
+	}
+
+	cmd.SilenceUsage = true
+
+	return cmd
+}
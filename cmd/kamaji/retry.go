@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/sqve/kamaji/internal/domain"
+	"github.com/sqve/kamaji/internal/output"
+	"github.com/sqve/kamaji/internal/statemachine"
+)
+
+func retryCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "retry [ticket]",
+		Short: "Clear the current task's failures so it gets fresh attempts",
+		Long: "Reset the failure count of the current task so a stuck sprint can run it again.\n\n" +
+			"Without a ticket name the stuck ticket is used, or else the ticket of the next task.\n" +
+			"The retry is recorded in the ticket history. Refuses to run while a sprint is running.",
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			return editState(func(e *stateEdit) error {
+				ticket, err := e.ticket(args)
+				if err != nil {
+					return err
+				}
+				task, err := e.currentTask(ticket)
+				if err != nil {
+					return err
+				}
+
+				failures := statemachine.Progress(e.state, ticket).FailureCount
+				if failures == 0 {
+					output.PrintInfo("No failures to clear for task: " + task.Description)
+					return nil
+				}
+
+				action := domain.ManualAction{
+					Action: "retry",
+					Task:   task.Description,
+					Detail: fmt.Sprintf("cleared %d failed attempts", failures),
+				}
+				if err := e.apply(ticket, action, func() { statemachine.ResetFailures(e.state, ticket) }); err != nil {
+					return err
+				}
+
+				output.PrintSuccess(fmt.Sprintf("Cleared %d failed attempts for task: %s", failures, task.Description))
+				return nil
+			})
+		},
+	}
+
+	cmd.SilenceUsage = true
+
+	return cmd
+}
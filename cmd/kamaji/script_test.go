@@ -3,16 +3,20 @@
 package main
 
 import (
+	"cmp"
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/client/transport"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/rogpeppe/go-internal/testscript"
 )
@@ -69,18 +73,28 @@ func mockAgentMain() {
 	}
 
 	script := os.Getenv("KAMAJI_AGENT_SCRIPT")
+	switch os.Getenv("KAMAJI_SESSION") {
+	case "plan":
+		script = os.Getenv("KAMAJI_AGENT_PLAN_SCRIPT")
+	case "review":
+		script = os.Getenv("KAMAJI_AGENT_REVIEW_SCRIPT")
+	}
 	if script == "" {
 		return
 	}
 
-	if err := runMockAgent(port, script); err != nil {
+	// KAMAJI_AGENT_TOKEN stands in for a client that does not hold the
+	// session's token.
+	token := cmp.Or(os.Getenv("KAMAJI_AGENT_TOKEN"), os.Getenv("KAMAJI_MCP_TOKEN"))
+	if err := runMockAgent(port, token, script); err != nil {
 		fmt.Fprintln(os.Stderr, "mock-agent:", err)
 		os.Exit(1)
 	}
 }
 
-func runMockAgent(port int, script string) error {
-	c, err := client.NewStreamableHttpClient(fmt.Sprintf("http://localhost:%d/mcp", port))
+func runMockAgent(port int, token, script string) error {
+	c, err := client.NewStreamableHttpClient(fmt.Sprintf("http://127.0.0.1:%d/mcp", port),
+		transport.WithHTTPHeaders(map[string]string{"Authorization": "Bearer " + token}))
 	if err != nil {
 		return err
 	}
@@ -99,12 +113,75 @@ func runMockAgent(port int, script string) error {
 		return err
 	}
 
+	// {ticket} and {task} stand for the session's task, so one script can
+	// leave different work behind for each task.
+	if strings.Contains(script, "{ticket}") || strings.Contains(script, "{task}") {
+		result, err := c.CallTool(ctx, mcp.CallToolRequest{Params: mcp.CallToolParams{Name: "get_task"}})
+		if err != nil {
+			return err
+		}
+		var task struct{ Ticket, Task string }
+		if err := json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &task); err != nil {
+			return err
+		}
+		script = strings.NewReplacer("{ticket}", task.Ticket, "{task}", task.Task).Replace(script)
+	}
+
 	for _, line := range parseScriptLines(script) {
 		tool, args, ok := parseScriptCommand(line)
 		if !ok {
 			continue
 		}
 
+		if tool == "sleep" {
+			// Simulates a stalled agent for timeout tests.
+			d, err := time.ParseDuration(args["duration"].(string))
+			if err != nil {
+				return err
+			}
+			time.Sleep(d)
+			continue
+		}
+
+		if tool == "append" {
+			// Changes the work tree so the task has something to commit.
+			path := filepath.Join(os.Getenv("KAMAJI_WORK_DIR"), args["path"].(string))
+			f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600) // #nosec G304 -- test script path
+			if err != nil {
+				return err
+			}
+			_, err = fmt.Fprintln(f, args["text"])
+			if cerr := f.Close(); err == nil {
+				err = cerr
+			}
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		if tool == "print" {
+			// Emits agent output, such as stream-json lines.
+			fmt.Println(args["text"])
+			continue
+		}
+
+		if tool == "call" || tool == "ask_human" {
+			// Calls a tool that answers, and prints its result.
+			name, callArgs := tool, any(args)
+			if tool == "call" {
+				name, callArgs = args["tool"].(string), args["args"]
+			}
+			result, err := c.CallTool(ctx, mcp.CallToolRequest{
+				Params: mcp.CallToolParams{Name: name, Arguments: callArgs},
+			})
+			if err != nil {
+				return err
+			}
+			fmt.Println(name + ": " + result.Content[0].(mcp.TextContent).Text)
+			continue
+		}
+
 		if tool == "note_insight" {
 			// Small delay between note_insight and subsequent tool calls ensures
 			// the HTTP response is fully processed. The MCP server uses a buffered
@@ -156,6 +233,46 @@ func parseScriptCommand(line string) (tool string, args map[string]any, ok bool)
 		return tool, map[string]any{"status": parts[0], "summary": strings.Trim(parts[1], "\"")}, true
 	case "note_insight":
 		return tool, map[string]any{"text": strings.Trim(rest, "\"")}, true
+	case "submit_plan":
+		return tool, map[string]any{"plan": strings.Trim(rest, "\"")}, true
+	case "submit_review":
+		parts := strings.SplitN(rest, " ", 2)
+		if len(parts) < 2 {
+			fmt.Fprintf(os.Stderr, "mock-agent: ignoring malformed submit_review: %q\n", line)
+			return "", nil, false
+		}
+		return tool, map[string]any{"verdict": parts[0], "reasons": strings.Trim(parts[1], "\"")}, true
+	case "sleep":
+		return tool, map[string]any{"duration": rest}, true
+	case "print":
+		return tool, map[string]any{"text": rest}, true
+	case "append":
+		parts := strings.SplitN(rest, " ", 2)
+		if len(parts) < 2 {
+			fmt.Fprintf(os.Stderr, "mock-agent: ignoring malformed append: %q\n", line)
+			return "", nil, false
+		}
+		return tool, map[string]any{"path": parts[0], "text": strings.Trim(parts[1], "\"")}, true
+	case "report_progress":
+		parts := strings.SplitN(rest, " ", 2)
+		percent, err := strconv.Atoi(parts[0])
+		if len(parts) < 2 || err != nil {
+			fmt.Fprintf(os.Stderr, "mock-agent: ignoring malformed report_progress: %q\n", line)
+			return "", nil, false
+		}
+		return tool, map[string]any{"message": strings.Trim(parts[1], "\""), "percent": percent}, true
+	case "ask_human":
+		return tool, map[string]any{"question": strings.Trim(rest, "\"")}, true
+	case "call":
+		parts := strings.SplitN(rest, " ", 2)
+		callArgs := map[string]any{}
+		if len(parts) == 2 {
+			if err := json.Unmarshal([]byte(parts[1]), &callArgs); err != nil {
+				fmt.Fprintf(os.Stderr, "mock-agent: ignoring malformed call: %q\n", line)
+				return "", nil, false
+			}
+		}
+		return tool, map[string]any{"tool": parts[0], "args": callArgs}, true
 	default:
 		fmt.Fprintf(os.Stderr, "mock-agent: ignoring unknown command: %q\n", tool)
 		return "", nil, false
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/sqve/kamaji/internal/domain"
+	"github.com/sqve/kamaji/internal/output"
+	"github.com/sqve/kamaji/internal/statemachine"
+)
+
+func skipCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "skip [ticket]",
+		Short: "Skip the current task and move on to the next",
+		Long: "Mark the current task as skipped and advance to the next task, clearing its failures.\n\n" +
+			"Without a ticket name the stuck ticket is used, or else the ticket of the next task.\n" +
+			"The skip is recorded in the ticket history. Refuses to run while a sprint is running.",
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			return editState(func(e *stateEdit) error {
+				ticket, err := e.ticket(args)
+				if err != nil {
+					return err
+				}
+				task, err := e.currentTask(ticket)
+				if err != nil {
+					return err
+				}
+
+				action := domain.ManualAction{Action: "skip", Task: task.Description}
+				if n := statemachine.Progress(e.state, ticket).FailureCount; n > 0 {
+					action.Detail = fmt.Sprintf("after %d failed attempts", n)
+				}
+				if err := e.apply(ticket, action, func() { statemachine.Skip(e.state, ticket) }); err != nil {
+					return err
+				}
+
+				output.PrintSuccess("Skipped task: " + task.Description)
+				return nil
+			})
+		},
+	}
+
+	cmd.SilenceUsage = true
+
+	return cmd
+}
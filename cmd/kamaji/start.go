@@ -2,7 +2,9 @@ package main
 
 import (
 	"os"
+	"os/signal"
 	"path/filepath"
+	"syscall"
 
 	"github.com/spf13/cobra"
 
@@ -10,23 +12,51 @@ import (
 )
 
 func startCmd() *cobra.Command {
-	var spawnerCmd string
+	var (
+		spawnerCmd string
+		parallel   int
+		dryRun     bool
+		accept     bool
+	)
 
 	cmd := &cobra.Command{
 		Use:   "start",
 		Short: "Run sprint until complete or stuck",
-		Long:  "Execute tasks sequentially from kamaji.yaml until the sprint completes or a task fails 3 consecutive times.",
+		Long: "Execute tasks sequentially from kamaji.yaml until the sprint completes or a task fails max_attempts\n" +
+			"consecutive times (3 by default).\n\n" +
+			"Tickets run in dependency order. With --parallel, independent tickets run concurrently,\n" +
+			"each in its own git worktree under .kamaji/worktrees.\n\n" +
+			"With --dry-run, print the branch and prompt for each remaining task without running agents\n" +
+			"or changing git or state. Context files are read from the current checkout, or from the\n" +
+			"ticket's worktree when a parallel run already created it.\n\n" +
+			"Progress is saved per ticket and task id, falling back to the ticket name and task\n" +
+			"description, so tasks can be added or reordered mid-sprint. If kamaji.yaml no longer has a\n" +
+			"ticket or task with saved progress, start refuses to run unless --accept-changes is set.",
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			workDir, err := os.Getwd()
 			if err != nil {
 				return err
 			}
 
-			result, err := orchestrator.Run(cmd.Context(), orchestrator.RunConfig{
-				WorkDir:    workDir,
-				SprintPath: filepath.Join(workDir, configFile),
-				SpawnerCmd: spawnerCmd,
-			})
+			cfg := orchestrator.RunConfig{
+				WorkDir:       workDir,
+				SprintPath:    filepath.Join(workDir, configFile),
+				SpawnerCmd:    spawnerCmd,
+				Parallel:      parallel,
+				AcceptChanges: accept,
+			}
+
+			if dryRun {
+				_, err := orchestrator.DryRun(cfg)
+				return err
+			}
+
+			// Stop on interrupt through the context so the run is journaled
+			// and the run lock released.
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			result, err := orchestrator.Run(ctx, cfg)
 			if err != nil {
 				return err
 			}
@@ -38,6 +68,9 @@ func startCmd() *cobra.Command {
 		},
 	}
 
+	cmd.Flags().BoolVar(&accept, "accept-changes", false, "Drop saved progress on tickets and tasks removed from kamaji.yaml")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print remaining tasks and their prompts without running them")
+	cmd.Flags().IntVarP(&parallel, "parallel", "j", 1, "Maximum number of tickets to run concurrently")
 	cmd.Flags().StringVar(&spawnerCmd, "spawner-cmd", "", "Override spawner command (for testing)")
 	_ = cmd.Flags().MarkHidden("spawner-cmd")
 
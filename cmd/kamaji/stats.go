@@ -0,0 +1,58 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/sqve/kamaji/internal/config"
+	"github.com/sqve/kamaji/internal/output"
+)
+
+func statsCmd() *cobra.Command {
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:   "stats [ticket]",
+		Short: "Show token usage and cost per task, ticket and sprint",
+		Long: "Show the attempts, tokens and reported cost recorded in the ticket history, summed\n" +
+			"per task, ticket and sprint. Each ticket's share of the sprint cost is shown alongside.\n\n" +
+			"Usage comes from the agent's final stream-json result, so attempts by agents that\n" +
+			"report none count as attempts without tokens or cost.",
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			workDir, err := os.Getwd()
+			if err != nil {
+				return err
+			}
+
+			histories, err := config.ListTicketHistories(workDir)
+			if err != nil {
+				return err
+			}
+			sortBySprint(histories, filepath.Join(workDir, configFile))
+
+			if len(args) == 1 {
+				histories = selectTicket(histories, args[0])
+				if len(histories) == 0 {
+					return fmt.Errorf("no history for ticket %q", args[0])
+				}
+			}
+
+			stats := config.GetSprintStats(histories)
+			if jsonOutput {
+				return output.PrintJSON(stats)
+			}
+			output.PrintSprintStats(stats)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output stats as JSON")
+
+	cmd.SilenceUsage = true
+
+	return cmd
+}
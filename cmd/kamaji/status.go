@@ -0,0 +1,61 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/sqve/kamaji/internal/config"
+	"github.com/sqve/kamaji/internal/domain"
+	"github.com/sqve/kamaji/internal/output"
+	"github.com/sqve/kamaji/internal/statemachine"
+)
+
+func statusCmd() *cobra.Command {
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show sprint progress and the current task",
+		RunE: func(_ *cobra.Command, _ []string) error {
+			workDir, err := os.Getwd()
+			if err != nil {
+				return err
+			}
+
+			sprint, err := config.LoadSprint(filepath.Join(workDir, configFile))
+			if err != nil {
+				return err
+			}
+
+			state, err := config.LoadState(workDir)
+			if err != nil {
+				return err
+			}
+			statemachine.MigrateState(state, sprint)
+			statemachine.AdoptIDs(state, sprint)
+
+			var history *domain.TicketHistory
+			if info := statemachine.NextTask(state, sprint); info != nil {
+				history, err = config.LoadTicketHistory(workDir, info.Ticket.Name)
+				if err != nil {
+					return err
+				}
+			}
+
+			report := output.NewStatusReport(sprint, state, history)
+			if jsonOutput {
+				return output.PrintJSON(report)
+			}
+			output.PrintStatusReport(report)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output status as JSON")
+
+	cmd.SilenceUsage = true
+
+	return cmd
+}
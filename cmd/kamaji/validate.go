@@ -30,6 +30,7 @@ func validateCmd() *cobra.Command {
 			}
 
 			validationErrors := config.ValidateSprint(sprint)
+			validationErrors = append(validationErrors, config.ValidateContext(workDir, sprint)...)
 			if len(validationErrors) > 0 {
 				output.PrintError("Configuration validation failed")
 				for _, ve := range validationErrors {
@@ -0,0 +1,186 @@
+// Package agent launches coding agents. Each adapter owns how its agent is
+// invoked, how it is pointed at the kamaji MCP server, how it receives the
+// prompt and how its output is parsed into transcript events.
+package agent
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/sqve/kamaji/internal/domain"
+	"github.com/sqve/kamaji/internal/process"
+)
+
+// Adapter launches one kind of coding agent.
+type Adapter interface {
+	// Name is the agent name used in kamaji.yaml.
+	Name() string
+	// Command builds the invocation for a session, writing any files it
+	// needs, such as MCP config or a prompt file.
+	Command(s Session) (*Command, error)
+	// ParseLine converts one line of agent stdout into transcript events.
+	ParseLine(line []byte) []domain.TranscriptEvent
+	// MCP reports whether the agent can call kamaji's MCP tools. Agents that
+	// cannot are judged by their exit status and skip planning and review
+	// sessions.
+	MCP() bool
+}
+
+// Session kinds. Planning and review sessions only read the work tree and
+// report through submit_plan and submit_review.
+const (
+	SessionTask   = "task"
+	SessionPlan   = "plan"
+	SessionReview = "review"
+)
+
+// Session describes one agent session.
+type Session struct {
+	Prompt   string    // Task context from prompt.AssembleTaskContext
+	MCPPort  int       // Required: port of the kamaji MCP server
+	MCPToken string    // Bearer token the MCP server accepts from this session
+	WorkDir  string    // Required: directory the agent runs in
+	Model    string    // Optional: model for this attempt, empty for the agent's default
+	Effort   string    // Optional: reasoning effort, low, medium or high
+	Kind     string    // Optional: SessionTask (default), SessionPlan or SessionReview
+	Stdout   io.Writer // Optional: defaults to os.Stdout
+	Stderr   io.Writer // Optional: defaults to os.Stderr
+}
+
+// ReadOnly reports whether the agent should be kept from changing files.
+func (s Session) ReadOnly() bool {
+	return s.Kind == SessionPlan || s.Kind == SessionReview
+}
+
+// MCPURL returns the URL of the kamaji MCP server.
+func (s Session) MCPURL() string {
+	return fmt.Sprintf("http://127.0.0.1:%d/mcp", s.MCPPort)
+}
+
+// Command is an agent invocation built by an adapter.
+type Command struct {
+	Path  string   // Program to run
+	Args  []string // Arguments
+	Env   []string // Extra environment variables
+	Stdin string   // Written to stdin when set
+	Files []string // Removed once the session ends
+}
+
+// Process is a running agent that can be waited on or killed.
+type Process interface {
+	Wait() error
+	Kill() error
+}
+
+// Spawned is a started agent session.
+type Spawned struct {
+	Process Process
+	files   []string
+}
+
+// Cleanup removes the files the adapter wrote for the session. Call it after
+// the process exits.
+func (s *Spawned) Cleanup() {
+	removeFiles(s.files)
+}
+
+// New returns the adapter for the configured agent.
+func New(cfg domain.Agent) (Adapter, error) {
+	switch cfg.Kind() {
+	case domain.AgentClaude:
+		return claude{args: cfg.Args}, nil
+	case domain.AgentCodex:
+		return codex{args: cfg.Args}, nil
+	case domain.AgentAider:
+		return aider{args: cfg.Args}, nil
+	case domain.AgentCustom:
+		if len(cfg.Command) == 0 || cfg.Command[0] == "" {
+			return nil, errors.New("custom agent requires a command")
+		}
+		return custom{cfg: cfg}, nil
+	default:
+		return nil, fmt.Errorf("unknown agent %q", cfg.Name)
+	}
+}
+
+// Spawn builds the adapter's command for the session and starts it. The
+// caller owns the process lifecycle and calls Cleanup after it exits. Every
+// agent gets KAMAJI_MCP_PORT, KAMAJI_MCP_URL, KAMAJI_MCP_TOKEN and
+// KAMAJI_WORK_DIR, plus KAMAJI_SESSION with the session kind, plus
+// KAMAJI_MODEL and KAMAJI_EFFORT when they are set.
+func Spawn(a Adapter, s Session) (*Spawned, error) {
+	if s.MCPPort <= 0 || s.MCPPort > 65535 {
+		return nil, errors.New("MCPPort must be between 1 and 65535")
+	}
+	if s.WorkDir == "" {
+		return nil, errors.New("WorkDir is required")
+	}
+	info, err := os.Stat(s.WorkDir)
+	if err != nil || !info.IsDir() {
+		return nil, errors.New("WorkDir must be an existing directory")
+	}
+
+	cmd, err := a.Command(s)
+	if err != nil {
+		return nil, err
+	}
+
+	env := append(os.Environ(),
+		"KAMAJI_MCP_PORT="+strconv.Itoa(s.MCPPort),
+		"KAMAJI_MCP_URL="+s.MCPURL(),
+		"KAMAJI_MCP_TOKEN="+s.MCPToken,
+		"KAMAJI_WORK_DIR="+s.WorkDir,
+		"KAMAJI_SESSION="+cmp.Or(s.Kind, SessionTask),
+	)
+	if s.Model != "" {
+		env = append(env, "KAMAJI_MODEL="+s.Model)
+	}
+	if s.Effort != "" {
+		env = append(env, "KAMAJI_EFFORT="+s.Effort)
+	}
+	p := process.NewProcess(cmd.Path, cmd.Args...)
+	p.Apply(process.WithDir(s.WorkDir), process.WithEnv(append(env, cmd.Env...)))
+	if cmd.Stdin != "" {
+		p.Apply(process.WithStdin(strings.NewReader(cmd.Stdin)))
+	}
+	if s.Stdout != nil {
+		p.Apply(process.WithStdout(s.Stdout))
+	}
+	if s.Stderr != nil {
+		p.Apply(process.WithStderr(s.Stderr))
+	}
+
+	if err := p.Start(); err != nil {
+		removeFiles(cmd.Files)
+		return nil, err
+	}
+
+	return &Spawned{Process: p, files: cmd.Files}, nil
+}
+
+// writePromptFile writes the prompt to a temporary file for agents that read
+// it from disk.
+func writePromptFile(prompt string) (string, error) {
+	f, err := os.CreateTemp("", "kamaji-prompt-*.md")
+	if err != nil {
+		return "", fmt.Errorf("creating prompt file: %w", err)
+	}
+	_, werr := f.WriteString(prompt)
+	cerr := f.Close()
+	if err := errors.Join(werr, cerr); err != nil {
+		_ = os.Remove(f.Name())
+		return "", fmt.Errorf("writing prompt file: %w", err)
+	}
+	return f.Name(), nil
+}
+
+func removeFiles(files []string) {
+	for _, f := range files {
+		_ = os.Remove(f)
+	}
+}
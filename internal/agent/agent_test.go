@@ -0,0 +1,348 @@
+package agent
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/sqve/kamaji/internal/domain"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		cfg     domain.Agent
+		want    string
+		wantMCP bool
+		wantErr bool
+	}{
+		{cfg: domain.Agent{}, want: domain.AgentClaude, wantMCP: true},
+		{cfg: domain.Agent{Name: "codex"}, want: domain.AgentCodex, wantMCP: true},
+		{cfg: domain.Agent{Name: "aider"}, want: domain.AgentAider},
+		{cfg: domain.Agent{Name: "custom", Command: []string{"my-agent"}}, want: domain.AgentCustom, wantMCP: true},
+		{cfg: domain.Agent{Name: "custom"}, wantErr: true},
+		{cfg: domain.Agent{Name: "gpt"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.cfg.Kind(), func(t *testing.T) {
+			a, err := New(tt.cfg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("New() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if a.Name() != tt.want {
+				t.Errorf("Name() = %q, want %q", a.Name(), tt.want)
+			}
+			if a.MCP() != tt.wantMCP {
+				t.Errorf("MCP() = %v, want %v", a.MCP(), tt.wantMCP)
+			}
+		})
+	}
+}
+
+func TestSpawn_ValidatesSession(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name string
+		s    Session
+	}{
+		{name: "empty prompt", s: Session{MCPPort: 8080, WorkDir: dir}},
+		{name: "zero port", s: Session{Prompt: "test", WorkDir: dir}},
+		{name: "negative port", s: Session{Prompt: "test", MCPPort: -1, WorkDir: dir}},
+		{name: "port above 65535", s: Session{Prompt: "test", MCPPort: 65536, WorkDir: dir}},
+		{name: "empty work dir", s: Session{Prompt: "test", MCPPort: 8080}},
+		{name: "missing work dir", s: Session{Prompt: "test", MCPPort: 8080, WorkDir: "/nonexistent/path/that/does/not/exist"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Spawn(claude{}, tt.s); err == nil {
+				t.Error("Spawn() error = nil, want error")
+			}
+		})
+	}
+}
+
+func TestSpawn_CleansUpFilesOnStartFailure(t *testing.T) {
+	// Set empty PATH to ensure claude binary not found
+	t.Setenv("PATH", "")
+	tmp := t.TempDir()
+	t.Setenv("TMPDIR", tmp)
+
+	dir := t.TempDir()
+
+	_, err := Spawn(claude{}, Session{Prompt: "test prompt", MCPPort: 9999, WorkDir: dir})
+	if err == nil {
+		t.Fatal("Spawn() error = nil, want error when claude not in PATH")
+	}
+	if !errors.Is(err, exec.ErrNotFound) {
+		t.Errorf("Spawn() error = %v, want exec.ErrNotFound", err)
+	}
+
+	if entries, _ := os.ReadDir(tmp); len(entries) != 0 {
+		t.Errorf("temp files left after Start() failure: %v", entries)
+	}
+}
+
+func TestSpawn_CustomReceivesPromptAndEnv(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+	dir := t.TempDir()
+	out := filepath.Join(dir, "out")
+
+	tests := []struct {
+		name   string
+		prompt string
+		script string
+	}{
+		{name: "stdin", prompt: domain.PromptStdin, script: `cat > out`},
+		{name: "arg", prompt: domain.PromptArg, script: `printf %s "$0" > out`},
+		{name: "file", prompt: domain.PromptFile, script: `cat "$KAMAJI_PROMPT_FILE" > out`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := custom{cfg: domain.Agent{Name: domain.AgentCustom, Command: []string{"sh", "-c", tt.script}, Prompt: tt.prompt}}
+			spawned, err := Spawn(a, Session{Prompt: "do the task", MCPPort: 9999, WorkDir: dir})
+			if err != nil {
+				t.Fatalf("Spawn() error = %v", err)
+			}
+			if err := spawned.Process.Wait(); err != nil {
+				t.Fatalf("Wait() error = %v", err)
+			}
+			spawned.Cleanup()
+
+			data, err := os.ReadFile(out)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != "do the task" {
+				t.Errorf("prompt = %q, want %q", data, "do the task")
+			}
+			for _, f := range spawned.files {
+				if _, err := os.Stat(f); !os.IsNotExist(err) {
+					t.Errorf("%s should be removed by Cleanup", f)
+				}
+			}
+		})
+	}
+}
+
+func TestSpawn_SetsMCPEnv(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+	dir := t.TempDir()
+	a := custom{cfg: domain.Agent{Command: []string{"sh", "-c", `echo "$KAMAJI_MCP_PORT $KAMAJI_MCP_URL $KAMAJI_MCP_TOKEN $KAMAJI_SESSION" > env`}}}
+
+	spawned, err := Spawn(a, Session{Prompt: "x", MCPPort: 4242, MCPToken: "secret", WorkDir: dir})
+	if err != nil {
+		t.Fatalf("Spawn() error = %v", err)
+	}
+	_ = spawned.Process.Wait()
+
+	data, err := os.ReadFile(filepath.Join(dir, "env"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "4242 http://127.0.0.1:4242/mcp secret task\n"
+	if string(data) != want {
+		t.Errorf("env = %q, want %q", data, want)
+	}
+}
+
+func TestCommand(t *testing.T) {
+	dir := t.TempDir()
+	s := Session{Prompt: "do it", MCPPort: 4242, WorkDir: dir}
+
+	tests := []struct {
+		name      string
+		adapter   Adapter
+		wantPath  string
+		wantArgs  []string // Expected to appear in order
+		wantStdin string
+	}{
+		{
+			name:     "claude",
+			adapter:  claude{args: []string{"--model", "opus"}},
+			wantPath: "claude",
+			wantArgs: []string{"--print", "do it", "--output-format", "stream-json", "--model", "opus"},
+		},
+		{
+			name:      "codex",
+			adapter:   codex{args: []string{"-m", "o3"}},
+			wantPath:  "codex",
+			wantArgs:  []string{"exec", "--json", `mcp_servers.kamaji.url="http://127.0.0.1:4242/mcp"`, `mcp_servers.kamaji.bearer_token_env_var="KAMAJI_MCP_TOKEN"`, "-m", "o3", "-"},
+			wantStdin: "do it",
+		},
+		{
+			name:     "aider",
+			adapter:  aider{args: []string{"--model", "sonnet"}},
+			wantPath: "aider",
+			wantArgs: []string{"--yes-always", "--message-file", "--model", "sonnet"},
+		},
+		{
+			name:     "custom",
+			adapter:  custom{cfg: domain.Agent{Command: []string{"run-agent", "--fast"}, Args: []string{"-v"}, Prompt: domain.PromptArg}},
+			wantPath: "run-agent",
+			wantArgs: []string{"--fast", "-v", "do it"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd, err := tt.adapter.Command(s)
+			if err != nil {
+				t.Fatalf("Command() error = %v", err)
+			}
+			defer removeFiles(cmd.Files)
+
+			if cmd.Path != tt.wantPath {
+				t.Errorf("Path = %q, want %q", cmd.Path, tt.wantPath)
+			}
+			if !inOrder(cmd.Args, tt.wantArgs) {
+				t.Errorf("Args = %q, want %q in order", cmd.Args, tt.wantArgs)
+			}
+			if cmd.Stdin != tt.wantStdin {
+				t.Errorf("Stdin = %q, want %q", cmd.Stdin, tt.wantStdin)
+			}
+		})
+	}
+}
+
+func TestCommand_ModelAndEffort(t *testing.T) {
+	s := Session{Prompt: "do it", MCPPort: 4242, WorkDir: t.TempDir(), Model: "big", Effort: domain.EffortHigh}
+
+	tests := []struct {
+		name     string
+		adapter  Adapter
+		wantArgs []string
+		wantEnv  []string
+	}{
+		{name: "claude", adapter: claude{}, wantArgs: []string{"--model", "big"}, wantEnv: []string{"MAX_THINKING_TOKENS=31999"}},
+		{name: "codex", adapter: codex{}, wantArgs: []string{"--model", "big", "-c", `model_reasoning_effort="high"`, "-"}},
+		{name: "aider", adapter: aider{}, wantArgs: []string{"--model", "big", "--reasoning-effort", "high"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd, err := tt.adapter.Command(s)
+			if err != nil {
+				t.Fatalf("Command() error = %v", err)
+			}
+			defer removeFiles(cmd.Files)
+
+			if !inOrder(cmd.Args, tt.wantArgs) {
+				t.Errorf("Args = %q, want %q in order", cmd.Args, tt.wantArgs)
+			}
+			if !reflect.DeepEqual(cmd.Env, tt.wantEnv) {
+				t.Errorf("Env = %q, want %q", cmd.Env, tt.wantEnv)
+			}
+		})
+	}
+}
+
+func TestCommand_ReadOnlySession(t *testing.T) {
+	s := Session{Prompt: "plan it", MCPPort: 4242, WorkDir: t.TempDir(), Kind: SessionPlan}
+
+	tests := []struct {
+		name     string
+		adapter  Adapter
+		wantArgs []string
+		skipArg  string
+	}{
+		{name: "claude", adapter: claude{}, wantArgs: []string{"--disallowedTools", claudeEditTools}},
+		{name: "codex", adapter: codex{}, wantArgs: []string{"--sandbox", "read-only"}, skipArg: "--dangerously-bypass-approvals-and-sandbox"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd, err := tt.adapter.Command(s)
+			if err != nil {
+				t.Fatalf("Command() error = %v", err)
+			}
+			defer removeFiles(cmd.Files)
+
+			if !inOrder(cmd.Args, tt.wantArgs) {
+				t.Errorf("Args = %q, want %q in order", cmd.Args, tt.wantArgs)
+			}
+			if tt.skipArg != "" && slices.Contains(cmd.Args, tt.skipArg) {
+				t.Errorf("Args = %q, want no %q", cmd.Args, tt.skipArg)
+			}
+		})
+	}
+}
+
+func TestCommand_ClaudePassesMCPConfig(t *testing.T) {
+	dir := t.TempDir()
+	cmd, err := claude{}.Command(Session{Prompt: "x", MCPPort: 4242, WorkDir: dir})
+	if err != nil {
+		t.Fatalf("Command() error = %v", err)
+	}
+	defer removeFiles(cmd.Files)
+
+	if len(cmd.Files) != 1 || strings.HasPrefix(cmd.Files[0], dir) {
+		t.Fatalf("Files = %q, want one config outside the work dir", cmd.Files)
+	}
+	if !inOrder(cmd.Args, []string{"--mcp-config", cmd.Files[0]}) {
+		t.Errorf("Args = %q, want --mcp-config %s", cmd.Args, cmd.Files[0])
+	}
+	if !slices.Contains(cmd.Args, "--strict-mcp-config") {
+		t.Errorf("Args = %q, want --strict-mcp-config", cmd.Args)
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".mcp.json")); !os.IsNotExist(err) {
+		t.Error(".mcp.json should not be written to the work dir")
+	}
+}
+
+func TestCommand_AiderPromptFile(t *testing.T) {
+	cmd, err := aider{}.Command(Session{Prompt: "do it", MCPPort: 4242, WorkDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("Command() error = %v", err)
+	}
+	defer removeFiles(cmd.Files)
+
+	data, err := os.ReadFile(cmd.Files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(data), "do it") || !strings.Contains(string(data), "cannot call task_complete") {
+		t.Errorf("prompt file = %q, want prompt and finishing note", data)
+	}
+}
+
+func TestCommand_CustomPromptFilePlaceholder(t *testing.T) {
+	a := custom{cfg: domain.Agent{Command: []string{"run-agent", "--prompt={prompt_file}"}, Prompt: domain.PromptFile}}
+	cmd, err := a.Command(Session{Prompt: "do it", MCPPort: 4242, WorkDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("Command() error = %v", err)
+	}
+	defer removeFiles(cmd.Files)
+
+	want := []string{"--prompt=" + cmd.Files[0]}
+	if !reflect.DeepEqual(cmd.Args, want) {
+		t.Errorf("Args = %q, want %q", cmd.Args, want)
+	}
+	if cmd.Stdin != "" {
+		t.Errorf("Stdin = %q, want empty", cmd.Stdin)
+	}
+}
+
+// inOrder reports whether want appears in got as a subsequence.
+func inOrder(got, want []string) bool {
+	i := 0
+	for _, g := range got {
+		if i < len(want) && g == want[i] {
+			i++
+		}
+	}
+	return i == len(want)
+}
@@ -0,0 +1,52 @@
+package agent
+
+import (
+	"github.com/sqve/kamaji/internal/domain"
+)
+
+// aiderNote replaces the task_complete instructions for aider, which has no
+// MCP client.
+const aiderNote = `
+
+## Finishing
+
+You cannot call task_complete, note_insight or the other kamaji tools. Make
+the change, then exit.
+Your work is checked by the task's verification command instead.`
+
+// aider runs Aider with the prompt in a message file. Aider has no MCP client,
+// so it cannot report task_complete; kamaji judges the session by its exit
+// status and the task's verify_cmd, which every task must set.
+type aider struct {
+	args []string
+}
+
+func (aider) Name() string { return domain.AgentAider }
+
+func (aider) MCP() bool { return false }
+
+func (a aider) Command(s Session) (*Command, error) {
+	promptFile, err := writePromptFile(s.Prompt + aiderNote)
+	if err != nil {
+		return nil, err
+	}
+	args := []string{
+		"--yes-always",      // accept all edits and commands without confirmation
+		"--no-auto-commits", // kamaji commits passing tasks itself
+		"--no-check-update",
+		"--no-pretty", // plain output for the transcript
+		"--message-file", promptFile,
+	}
+	if s.Model != "" {
+		args = append(args, "--model", s.Model)
+	}
+	if s.Effort != "" {
+		args = append(args, "--reasoning-effort", s.Effort)
+	}
+	return &Command{Path: "aider", Args: append(args, a.args...), Files: []string{promptFile}}, nil
+}
+
+// ParseLine keeps each line of aider's plain text output.
+func (aider) ParseLine(line []byte) []domain.TranscriptEvent {
+	return outputLine(line)
+}
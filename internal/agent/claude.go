@@ -0,0 +1,193 @@
+package agent
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/sqve/kamaji/internal/config"
+	"github.com/sqve/kamaji/internal/domain"
+)
+
+// claude runs Claude Code, the default agent. The kamaji MCP server, merged
+// with the project's own .mcp.json servers, is passed with --mcp-config from
+// a temp file so nothing is written to the work tree. --strict-mcp-config
+// keeps Claude Code from loading other MCP configs on top of it.
+type claude struct {
+	args []string
+}
+
+func (claude) Name() string { return domain.AgentClaude }
+
+func (claude) MCP() bool { return true }
+
+func (c claude) Command(s Session) (*Command, error) {
+	if s.Prompt == "" {
+		return nil, errors.New("prompt is required")
+	}
+	configPath, err := config.WriteMCPConfig(s.WorkDir, s.MCPPort, s.MCPToken)
+	if err != nil {
+		return nil, err
+	}
+	args := []string{
+		"--print", s.Prompt, // non-interactive mode with initial prompt
+		"--dangerously-skip-permissions", // auto-accept all tool calls without user confirmation
+		"--output-format", "stream-json", // structured output for programmatic parsing
+		"--mcp-config", configPath, // kamaji and project MCP servers
+		"--strict-mcp-config", // only the servers in configPath
+	}
+	if s.Model != "" {
+		args = append(args, "--model", s.Model)
+	}
+	if s.ReadOnly() {
+		args = append(args, "--disallowedTools", claudeEditTools)
+	}
+	cmd := &Command{Path: "claude", Args: append(args, c.args...), Files: []string{configPath}}
+	if budget, ok := claudeThinkingTokens[s.Effort]; ok {
+		cmd.Env = []string{"MAX_THINKING_TOKENS=" + budget}
+	}
+	return cmd, nil
+}
+
+// claudeEditTools are the Claude Code tools that change files.
+const claudeEditTools = "Edit,MultiEdit,Write,NotebookEdit"
+
+// claudeThinkingTokens maps reasoning effort onto Claude Code's extended
+// thinking budget.
+var claudeThinkingTokens = map[string]string{
+	domain.EffortLow:    "4000",
+	domain.EffortMedium: "10000",
+	domain.EffortHigh:   "31999",
+}
+
+func (claude) ParseLine(line []byte) []domain.TranscriptEvent {
+	return parseClaudeLine(line)
+}
+
+// streamLine is the subset of a Claude Code stream-json line kamaji reads.
+type streamLine struct {
+	Type       string         `json:"type"`
+	Subtype    string         `json:"subtype"`
+	SessionID  string         `json:"session_id"`
+	Model      string         `json:"model"`
+	Message    *streamMessage `json:"message"`
+	Result     string         `json:"result"`
+	IsError    bool           `json:"is_error"`
+	NumTurns   int            `json:"num_turns"`
+	DurationMS int64          `json:"duration_ms"`
+	Usage      *streamUsage   `json:"usage"`
+	CostUSD    float64        `json:"total_cost_usd"`
+}
+
+type streamUsage struct {
+	InputTokens              int64 `json:"input_tokens"`
+	OutputTokens             int64 `json:"output_tokens"`
+	CacheCreationInputTokens int64 `json:"cache_creation_input_tokens"`
+	CacheReadInputTokens     int64 `json:"cache_read_input_tokens"`
+}
+
+type streamMessage struct {
+	Content []streamContent `json:"content"`
+}
+
+type streamContent struct {
+	Type      string          `json:"type"`
+	Text      string          `json:"text"`
+	ID        string          `json:"id"`
+	Name      string          `json:"name"`
+	Input     json.RawMessage `json:"input"`
+	ToolUseID string          `json:"tool_use_id"`
+	Content   json.RawMessage `json:"content"`
+	IsError   bool            `json:"is_error"`
+}
+
+// parseClaudeLine converts one line of Claude Code stream-json output into
+// transcript events. A line can hold several content blocks, so it may yield
+// several events. Lines that are not stream-json are kept as a single output
+// event.
+func parseClaudeLine(line []byte) []domain.TranscriptEvent {
+	now := time.Now().UTC()
+	trimmed := bytes.TrimSpace(line)
+	if len(trimmed) == 0 || trimmed[0] != '{' {
+		return outputLine(line)
+	}
+	var sl streamLine
+	if err := json.Unmarshal(trimmed, &sl); err != nil || sl.Type == "" {
+		return outputLine(line)
+	}
+
+	switch sl.Type {
+	case "system":
+		return []domain.TranscriptEvent{{
+			Time: now, Kind: domain.TranscriptSystem, Text: sl.Subtype, SessionID: sl.SessionID, Model: sl.Model,
+		}}
+	case "result":
+		return []domain.TranscriptEvent{{
+			Time: now, Kind: domain.TranscriptResult, Text: sl.Result, IsError: sl.IsError,
+			SessionID: sl.SessionID, NumTurns: sl.NumTurns, DurationMS: sl.DurationMS, Usage: sl.usage(),
+		}}
+	case "assistant", "user":
+		if sl.Message == nil {
+			return nil
+		}
+		var events []domain.TranscriptEvent
+		for _, c := range sl.Message.Content {
+			switch c.Type {
+			case "text":
+				events = append(events, domain.TranscriptEvent{Time: now, Kind: domain.TranscriptText, Text: c.Text})
+			case "tool_use":
+				events = append(events, domain.TranscriptEvent{
+					Time: now, Kind: domain.TranscriptToolUse, Tool: c.Name, ToolID: c.ID, Input: c.Input,
+				})
+			case "tool_result":
+				events = append(events, domain.TranscriptEvent{
+					Time: now, Kind: domain.TranscriptToolResult, ToolID: c.ToolUseID, Text: toolResultText(c.Content), IsError: c.IsError,
+				})
+			}
+		}
+		return events
+	default:
+		return nil
+	}
+}
+
+// usage returns the token usage and cost of a result line, or nil when it
+// reports neither.
+func (sl *streamLine) usage() *domain.Usage {
+	u := domain.Usage{CostUSD: sl.CostUSD}
+	if sl.Usage != nil {
+		u.InputTokens = sl.Usage.InputTokens
+		u.OutputTokens = sl.Usage.OutputTokens
+		u.CacheCreationTokens = sl.Usage.CacheCreationInputTokens
+		u.CacheReadTokens = sl.Usage.CacheReadInputTokens
+	}
+	if u.IsZero() {
+		return nil
+	}
+	return &u
+}
+
+// toolResultText flattens tool result content, which is either a string or a
+// list of content blocks.
+func toolResultText(content json.RawMessage) string {
+	if len(content) == 0 {
+		return ""
+	}
+	var s string
+	if err := json.Unmarshal(content, &s); err == nil {
+		return s
+	}
+	var blocks []streamContent
+	if err := json.Unmarshal(content, &blocks); err != nil {
+		return string(content)
+	}
+	var parts []string
+	for _, b := range blocks {
+		if b.Type == "text" {
+			parts = append(parts, b.Text)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
@@ -0,0 +1,85 @@
+package agent
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sqve/kamaji/internal/domain"
+)
+
+func TestClaudeParseLine(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		want []domain.TranscriptEvent
+	}{
+		{
+			name: "init",
+			line: `{"type":"system","subtype":"init","session_id":"s1","model":"m"}`,
+			want: []domain.TranscriptEvent{{Kind: domain.TranscriptSystem, Text: "init", SessionID: "s1", Model: "m"}},
+		},
+		{
+			name: "assistant text and tool use",
+			line: `{"type":"assistant","message":{"content":[{"type":"text","text":"hi"},{"type":"tool_use","id":"t1","name":"Read","input":{"file_path":"a.go"}}]}}`,
+			want: []domain.TranscriptEvent{
+				{Kind: domain.TranscriptText, Text: "hi"},
+				{Kind: domain.TranscriptToolUse, Tool: "Read", ToolID: "t1", Input: []byte(`{"file_path":"a.go"}`)},
+			},
+		},
+		{
+			name: "tool result with string content",
+			line: `{"type":"user","message":{"content":[{"type":"tool_result","tool_use_id":"t1","content":"ok"}]}}`,
+			want: []domain.TranscriptEvent{{Kind: domain.TranscriptToolResult, ToolID: "t1", Text: "ok"}},
+		},
+		{
+			name: "tool result with block content",
+			line: `{"type":"user","message":{"content":[{"type":"tool_result","tool_use_id":"t1","is_error":true,"content":[{"type":"text","text":"a"},{"type":"text","text":"b"}]}]}}`,
+			want: []domain.TranscriptEvent{{Kind: domain.TranscriptToolResult, ToolID: "t1", Text: "a\nb", IsError: true}},
+		},
+		{
+			name: "result",
+			line: `{"type":"result","subtype":"success","result":"done","num_turns":2,"duration_ms":1500,"session_id":"s1"}`,
+			want: []domain.TranscriptEvent{{Kind: domain.TranscriptResult, Text: "done", SessionID: "s1", NumTurns: 2, DurationMS: 1500}},
+		},
+		{
+			name: "result with usage",
+			line: `{"type":"result","result":"done","total_cost_usd":0.25,"usage":{"input_tokens":10,"output_tokens":20,"cache_creation_input_tokens":30,"cache_read_input_tokens":40}}`,
+			want: []domain.TranscriptEvent{{Kind: domain.TranscriptResult, Text: "done", Usage: &domain.Usage{
+				InputTokens: 10, OutputTokens: 20, CacheCreationTokens: 30, CacheReadTokens: 40, CostUSD: 0.25,
+			}}},
+		},
+		{
+			name: "plain text",
+			line: "building...",
+			want: []domain.TranscriptEvent{{Kind: domain.TranscriptOutput, Text: "building..."}},
+		},
+		{
+			name: "json without type",
+			line: `{"foo":1}`,
+			want: []domain.TranscriptEvent{{Kind: domain.TranscriptOutput, Text: `{"foo":1}`}},
+		},
+		{
+			name: "unknown type is dropped",
+			line: `{"type":"stream_event"}`,
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseClaudeLine([]byte(tt.line))
+			if len(got) != len(tt.want) {
+				t.Fatalf("parseClaudeLine returned %d events, want %d: %+v", len(got), len(tt.want), got)
+			}
+			for i := range got {
+				if got[i].Time.IsZero() {
+					t.Errorf("event %d: Time is zero", i)
+				}
+				got[i].Time = tt.want[i].Time
+				if !reflect.DeepEqual(got[i], tt.want[i]) {
+					t.Errorf("event %d = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package agent
+
+import (
+	"bytes"
+	"encoding/json"
+	"time"
+
+	"github.com/sqve/kamaji/internal/domain"
+)
+
+// codex runs the OpenAI Codex CLI in exec mode. The kamaji MCP server is
+// passed as a config override, with its token read from KAMAJI_MCP_TOKEN, and
+// the prompt is read from stdin.
+type codex struct {
+	args []string
+}
+
+func (codex) Name() string { return domain.AgentCodex }
+
+func (codex) MCP() bool { return true }
+
+func (c codex) Command(s Session) (*Command, error) {
+	args := []string{"exec", "--json"} // JSONL events for programmatic parsing
+	if s.ReadOnly() {
+		args = append(args, "--sandbox", "read-only")
+	} else {
+		args = append(args, "--dangerously-bypass-approvals-and-sandbox") // run commands without confirmation
+	}
+	args = append(args,
+		"--skip-git-repo-check", // worktrees and fresh repos are fine
+		"-c", `mcp_servers.kamaji.url="`+s.MCPURL()+`"`,
+		"-c", `mcp_servers.kamaji.bearer_token_env_var="KAMAJI_MCP_TOKEN"`,
+	)
+	if s.Model != "" {
+		args = append(args, "--model", s.Model)
+	}
+	if s.Effort != "" {
+		args = append(args, "-c", `model_reasoning_effort="`+s.Effort+`"`)
+	}
+	args = append(args, c.args...)
+	return &Command{Path: "codex", Args: append(args, "-"), Stdin: s.Prompt}, nil
+}
+
+// codexLine is the subset of a codex exec --json line kamaji reads.
+type codexLine struct {
+	Type     string      `json:"type"`
+	ThreadID string      `json:"thread_id"`
+	Item     *codexItem  `json:"item"`
+	Usage    *codexUsage `json:"usage"`
+	Error    *codexError `json:"error"`
+	Message  string      `json:"message"`
+}
+
+type codexItem struct {
+	ID               string          `json:"id"`
+	Type             string          `json:"type"`
+	Text             string          `json:"text"`
+	Command          string          `json:"command"`
+	AggregatedOutput string          `json:"aggregated_output"`
+	ExitCode         *int            `json:"exit_code"`
+	Server           string          `json:"server"`
+	Tool             string          `json:"tool"`
+	Arguments        json.RawMessage `json:"arguments"`
+	Changes          json.RawMessage `json:"changes"`
+}
+
+type codexUsage struct {
+	InputTokens       int64 `json:"input_tokens"`
+	CachedInputTokens int64 `json:"cached_input_tokens"`
+	OutputTokens      int64 `json:"output_tokens"`
+}
+
+type codexError struct {
+	Message string `json:"message"`
+}
+
+// ParseLine converts one line of codex exec --json output into transcript
+// events. Lines that are not JSON are kept as output events.
+func (codex) ParseLine(line []byte) []domain.TranscriptEvent {
+	now := time.Now().UTC()
+	trimmed := bytes.TrimSpace(line)
+	if len(trimmed) == 0 || trimmed[0] != '{' {
+		return outputLine(line)
+	}
+	var cl codexLine
+	if err := json.Unmarshal(trimmed, &cl); err != nil || cl.Type == "" {
+		return outputLine(line)
+	}
+
+	switch cl.Type {
+	case "thread.started":
+		return []domain.TranscriptEvent{{Time: now, Kind: domain.TranscriptSystem, Text: "init", SessionID: cl.ThreadID}}
+	case "item.started", "item.completed":
+		if cl.Item == nil {
+			return nil
+		}
+		return cl.Item.events(now, cl.Type == "item.completed")
+	case "turn.completed":
+		e := domain.TranscriptEvent{Time: now, Kind: domain.TranscriptResult, NumTurns: 1}
+		if cl.Usage != nil {
+			e.Usage = &domain.Usage{
+				InputTokens:     cl.Usage.InputTokens - cl.Usage.CachedInputTokens,
+				OutputTokens:    cl.Usage.OutputTokens,
+				CacheReadTokens: cl.Usage.CachedInputTokens,
+			}
+		}
+		return []domain.TranscriptEvent{e}
+	case "turn.failed":
+		msg := ""
+		if cl.Error != nil {
+			msg = cl.Error.Message
+		}
+		return []domain.TranscriptEvent{{Time: now, Kind: domain.TranscriptResult, Text: msg, IsError: true}}
+	case "error":
+		return []domain.TranscriptEvent{{Time: now, Kind: domain.TranscriptResult, Text: cl.Message, IsError: true}}
+	default:
+		return nil
+	}
+}
+
+// events maps a codex item onto transcript events. Commands and MCP calls are
+// logged as a tool use when they start and a tool result when they complete;
+// messages and file changes are only logged once completed.
+func (it *codexItem) events(now time.Time, completed bool) []domain.TranscriptEvent {
+	switch it.Type {
+	case "agent_message":
+		if !completed {
+			return nil
+		}
+		return []domain.TranscriptEvent{{Time: now, Kind: domain.TranscriptText, Text: it.Text}}
+	case "command_execution":
+		if !completed {
+			input, _ := json.Marshal(map[string]string{"command": it.Command})
+			return []domain.TranscriptEvent{{Time: now, Kind: domain.TranscriptToolUse, Tool: "shell", ToolID: it.ID, Input: input}}
+		}
+		return []domain.TranscriptEvent{{
+			Time: now, Kind: domain.TranscriptToolResult, ToolID: it.ID, Text: it.AggregatedOutput,
+			IsError: it.ExitCode != nil && *it.ExitCode != 0,
+		}}
+	case "mcp_tool_call":
+		if completed {
+			return nil
+		}
+		return []domain.TranscriptEvent{{Time: now, Kind: domain.TranscriptToolUse, Tool: it.Server + "." + it.Tool, ToolID: it.ID, Input: it.Arguments}}
+	case "file_change":
+		if !completed {
+			return nil
+		}
+		return []domain.TranscriptEvent{{Time: now, Kind: domain.TranscriptToolUse, Tool: "file_change", ToolID: it.ID, Input: it.Changes}}
+	default:
+		return nil
+	}
+}
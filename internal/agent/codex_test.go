@@ -0,0 +1,79 @@
+package agent
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sqve/kamaji/internal/domain"
+)
+
+func TestCodexParseLine(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		want []domain.TranscriptEvent
+	}{
+		{
+			name: "thread started",
+			line: `{"type":"thread.started","thread_id":"th1"}`,
+			want: []domain.TranscriptEvent{{Kind: domain.TranscriptSystem, Text: "init", SessionID: "th1"}},
+		},
+		{
+			name: "command started",
+			line: `{"type":"item.started","item":{"id":"i1","type":"command_execution","command":"go test"}}`,
+			want: []domain.TranscriptEvent{{Kind: domain.TranscriptToolUse, Tool: "shell", ToolID: "i1", Input: []byte(`{"command":"go test"}`)}},
+		},
+		{
+			name: "command failed",
+			line: `{"type":"item.completed","item":{"id":"i1","type":"command_execution","aggregated_output":"FAIL","exit_code":1}}`,
+			want: []domain.TranscriptEvent{{Kind: domain.TranscriptToolResult, ToolID: "i1", Text: "FAIL", IsError: true}},
+		},
+		{
+			name: "agent message",
+			line: `{"type":"item.completed","item":{"id":"i2","type":"agent_message","text":"done"}}`,
+			want: []domain.TranscriptEvent{{Kind: domain.TranscriptText, Text: "done"}},
+		},
+		{
+			name: "mcp tool call",
+			line: `{"type":"item.started","item":{"id":"i3","type":"mcp_tool_call","server":"kamaji","tool":"task_complete","arguments":{"status":"pass"}}}`,
+			want: []domain.TranscriptEvent{{Kind: domain.TranscriptToolUse, Tool: "kamaji.task_complete", ToolID: "i3", Input: []byte(`{"status":"pass"}`)}},
+		},
+		{
+			name: "turn completed with usage",
+			line: `{"type":"turn.completed","usage":{"input_tokens":100,"cached_input_tokens":40,"output_tokens":20}}`,
+			want: []domain.TranscriptEvent{{Kind: domain.TranscriptResult, NumTurns: 1, Usage: &domain.Usage{
+				InputTokens: 60, OutputTokens: 20, CacheReadTokens: 40,
+			}}},
+		},
+		{
+			name: "turn failed",
+			line: `{"type":"turn.failed","error":{"message":"rate limited"}}`,
+			want: []domain.TranscriptEvent{{Kind: domain.TranscriptResult, Text: "rate limited", IsError: true}},
+		},
+		{
+			name: "plain text",
+			line: "Reading prompt from stdin...",
+			want: []domain.TranscriptEvent{{Kind: domain.TranscriptOutput, Text: "Reading prompt from stdin..."}},
+		},
+		{
+			name: "unknown type is dropped",
+			line: `{"type":"turn.started"}`,
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := codex{}.ParseLine([]byte(tt.line))
+			if len(got) != len(tt.want) {
+				t.Fatalf("ParseLine returned %d events, want %d: %+v", len(got), len(tt.want), got)
+			}
+			for i := range got {
+				got[i].Time = tt.want[i].Time
+				if !reflect.DeepEqual(got[i], tt.want[i]) {
+					t.Errorf("event %d = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
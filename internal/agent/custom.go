@@ -0,0 +1,53 @@
+package agent
+
+import (
+	"strings"
+
+	"github.com/sqve/kamaji/internal/domain"
+)
+
+// promptFilePlaceholder in a custom command is replaced with the prompt file.
+const promptFilePlaceholder = "{prompt_file}"
+
+// custom runs any command. It reaches the kamaji MCP server through
+// KAMAJI_MCP_URL, sending KAMAJI_MCP_TOKEN as a bearer token, and gets the
+// prompt on stdin, as its last argument or in the file named by
+// KAMAJI_PROMPT_FILE, and the attempt's model and effort in KAMAJI_MODEL and
+// KAMAJI_EFFORT. Its output is parsed as Claude Code stream-json, with other
+// lines kept verbatim.
+type custom struct {
+	cfg domain.Agent
+}
+
+func (custom) Name() string { return domain.AgentCustom }
+
+func (custom) MCP() bool { return true }
+
+func (c custom) Command(s Session) (*Command, error) {
+	cmd := &Command{Path: c.cfg.Command[0]}
+	args := append(append([]string{}, c.cfg.Command[1:]...), c.cfg.Args...)
+
+	switch c.cfg.Prompt {
+	case domain.PromptArg:
+		args = append(args, s.Prompt)
+	case domain.PromptFile:
+		promptFile, err := writePromptFile(s.Prompt)
+		if err != nil {
+			return nil, err
+		}
+		for i, arg := range args {
+			args[i] = strings.ReplaceAll(arg, promptFilePlaceholder, promptFile)
+		}
+		cmd.Env = []string{"KAMAJI_PROMPT_FILE=" + promptFile}
+		cmd.Files = []string{promptFile}
+	default:
+		cmd.Stdin = s.Prompt
+	}
+
+	cmd.Args = args
+	return cmd, nil
+}
+
+func (custom) ParseLine(line []byte) []domain.TranscriptEvent {
+	return parseClaudeLine(line)
+}
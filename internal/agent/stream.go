@@ -0,0 +1,61 @@
+package agent
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/sqve/kamaji/internal/domain"
+)
+
+// StreamParser is an io.Writer that splits agent output into lines, parses
+// them with the adapter and hands the transcript events to a callback. It is
+// not safe for concurrent use.
+type StreamParser struct {
+	adapter Adapter
+	onEvent func(domain.TranscriptEvent)
+	buf     []byte
+}
+
+// NewStreamParser creates a parser that calls onEvent for every event the
+// adapter parses from the agent's output.
+func NewStreamParser(a Adapter, onEvent func(domain.TranscriptEvent)) *StreamParser {
+	return &StreamParser{adapter: a, onEvent: onEvent}
+}
+
+// Write implements io.Writer, parsing each complete line.
+func (p *StreamParser) Write(b []byte) (int, error) {
+	p.buf = append(p.buf, b...)
+	for {
+		idx := bytes.IndexByte(p.buf, '\n')
+		if idx < 0 {
+			break
+		}
+		line := p.buf[:idx]
+		p.buf = p.buf[idx+1:]
+		p.emit(line)
+	}
+	return len(b), nil
+}
+
+// Flush parses any buffered partial line.
+func (p *StreamParser) Flush() {
+	if len(p.buf) > 0 {
+		p.emit(p.buf)
+		p.buf = nil
+	}
+}
+
+func (p *StreamParser) emit(line []byte) {
+	line = bytes.TrimSuffix(line, []byte{'\r'})
+	if len(bytes.TrimSpace(line)) == 0 {
+		return
+	}
+	for _, e := range p.adapter.ParseLine(line) {
+		p.onEvent(e)
+	}
+}
+
+// outputLine keeps a line the adapter cannot parse as a single output event.
+func outputLine(line []byte) []domain.TranscriptEvent {
+	return []domain.TranscriptEvent{{Time: time.Now().UTC(), Kind: domain.TranscriptOutput, Text: string(line)}}
+}
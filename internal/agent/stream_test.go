@@ -0,0 +1,24 @@
+package agent
+
+import (
+	"testing"
+
+	"github.com/sqve/kamaji/internal/domain"
+)
+
+func TestStreamParser_SplitsLinesAcrossWrites(t *testing.T) {
+	var got []string
+	p := NewStreamParser(claude{}, func(e domain.TranscriptEvent) { got = append(got, e.Kind+":"+e.Text) })
+
+	_, _ = p.Write([]byte(`{"type":"assistant","message":{"content":[{"type":"te`))
+	_, _ = p.Write([]byte("xt\",\"text\":\"hi\"}]}}\r\n\nplain"))
+	if len(got) != 1 {
+		t.Fatalf("events before Flush = %v, want 1", got)
+	}
+	p.Flush()
+
+	want := []string{"text:hi", "output:plain"}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("events = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,227 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/sqve/kamaji/internal/domain"
+)
+
+// Context size limits keep injected files from crowding out the task itself.
+const (
+	MaxContextFileBytes  = 32 * 1024
+	MaxContextTotalBytes = 128 * 1024
+)
+
+// ResolveContext expands context entries into file paths relative to workDir.
+// Entries are paths, globs (with ** matching any number of directories) or
+// @file references. Matches keep entry order and duplicates are dropped.
+func ResolveContext(workDir string, entries []string) ([]string, error) {
+	var paths []string
+	seen := make(map[string]bool)
+
+	for _, entry := range entries {
+		matches, err := resolveContextEntry(workDir, entry)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range matches {
+			if !seen[m] {
+				seen[m] = true
+				paths = append(paths, m)
+			}
+		}
+	}
+
+	return paths, nil
+}
+
+// LoadContext resolves and reads context files. Content beyond
+// MaxContextFileBytes per file or MaxContextTotalBytes overall is cut and the
+// file marked truncated, so the agent knows to read the rest itself.
+func LoadContext(workDir string, entries []string) ([]domain.ContextFile, error) {
+	paths, err := ResolveContext(workDir, entries)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]domain.ContextFile, 0, len(paths))
+	remaining := MaxContextTotalBytes
+
+	for _, p := range paths {
+		data, err := os.ReadFile(filepath.Join(workDir, filepath.FromSlash(p))) // #nosec G304 -- paths are confined to the work dir
+		if err != nil {
+			return nil, fmt.Errorf("reading context file: %w", err)
+		}
+
+		limit := min(MaxContextFileBytes, remaining)
+		file := domain.ContextFile{Path: p, Content: string(data)}
+		if len(data) > limit {
+			file.Content = truncateUTF8(file.Content, limit)
+			file.Truncated = true
+		}
+		remaining -= len(file.Content)
+		files = append(files, file)
+	}
+
+	return files, nil
+}
+
+// ValidateContext checks that every context entry in the sprint resolves to
+// at least one file inside workDir.
+func ValidateContext(workDir string, s *domain.Sprint) []ValidationError {
+	var errors []ValidationError
+
+	check := func(prefix string, entries []string) {
+		for i, entry := range entries {
+			if _, err := resolveContextEntry(workDir, entry); err != nil {
+				errors = append(errors, ValidationError{
+					Field:   fmt.Sprintf("%s.context[%d]", prefix, i),
+					Message: err.Error(),
+				})
+			}
+		}
+	}
+
+	for i, ticket := range s.Tickets {
+		ticketPrefix := fmt.Sprintf("tickets[%d]", i)
+		check(ticketPrefix, ticket.Context)
+		for j, task := range ticket.Tasks {
+			check(fmt.Sprintf("%s.tasks[%d]", ticketPrefix, j), task.Context)
+		}
+	}
+
+	return errors
+}
+
+func resolveContextEntry(workDir, entry string) ([]string, error) {
+	p := strings.TrimPrefix(strings.TrimSpace(entry), "@")
+	if p == "" {
+		return nil, errors.New("path is empty")
+	}
+	if filepath.IsAbs(p) {
+		return nil, fmt.Errorf("path must be inside the project: %s", p)
+	}
+	p = path.Clean(filepath.ToSlash(p))
+	if p == ".." || strings.HasPrefix(p, "../") {
+		return nil, fmt.Errorf("path must be inside the project: %s", p)
+	}
+
+	if !hasGlobMeta(p) {
+		info, err := os.Stat(filepath.Join(workDir, filepath.FromSlash(p)))
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return nil, fmt.Errorf("path does not exist: %s", p)
+			}
+			return nil, fmt.Errorf("checking context path: %w", err)
+		}
+		if info.IsDir() {
+			return nil, fmt.Errorf("path is a directory: %s (use a glob such as %s/*)", p, p)
+		}
+		return []string{p}, nil
+	}
+
+	if _, err := path.Match(p, ""); err != nil {
+		return nil, fmt.Errorf("invalid glob: %s", p)
+	}
+
+	matches, err := globFiles(workDir, p)
+	if err != nil {
+		return nil, err
+	}
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("no files match: %s", p)
+	}
+	return matches, nil
+}
+
+// globFiles walks the static prefix of pattern and returns matching files in
+// lexical order. Version control and kamaji directories are skipped.
+func globFiles(workDir, pattern string) ([]string, error) {
+	segments := strings.Split(pattern, "/")
+
+	var base []string
+	for _, seg := range segments {
+		if hasGlobMeta(seg) {
+			break
+		}
+		base = append(base, seg)
+	}
+
+	root := filepath.Join(workDir, filepath.FromSlash(path.Join(base...)))
+	if _, err := os.Stat(root); errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+
+	var matches []string
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if name := d.Name(); p != root && (name == ".git" || name == ".kamaji") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		rel, err := filepath.Rel(workDir, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if matchSegments(segments, strings.Split(rel, "/")) {
+			matches = append(matches, rel)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("matching context glob: %w", err)
+	}
+
+	return matches, nil
+}
+
+// matchSegments matches a slash-split path against pattern segments, where a
+// ** segment matches zero or more path segments.
+func matchSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(name); i++ {
+				if matchSegments(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(name) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], name[0]); !ok {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(name) == 0
+}
+
+func hasGlobMeta(p string) bool {
+	return strings.ContainsAny(p, "*?[")
+}
+
+// truncateUTF8 cuts s to at most n bytes without splitting a rune.
+func truncateUTF8(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
@@ -0,0 +1,158 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/sqve/kamaji/internal/domain"
+	"github.com/sqve/kamaji/internal/testutil"
+)
+
+func writeContextFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestResolveContext_PathsGlobsAndReferences(t *testing.T) {
+	dir := t.TempDir()
+	writeContextFiles(t, dir, map[string]string{
+		"README.md":            "readme",
+		"src/auth/login.ts":    "login",
+		"src/auth/logout.ts":   "logout",
+		"src/auth/nested/a.ts": "nested",
+		"src/auth/style.css":   "css",
+		".git/config":          "git",
+	})
+
+	got, err := ResolveContext(dir, []string{"@README.md", "src/auth/*.ts", "src/**/*.ts", "./README.md"})
+	if err != nil {
+		t.Fatalf("ResolveContext() error = %v", err)
+	}
+
+	want := []string{"README.md", "src/auth/login.ts", "src/auth/logout.ts", "src/auth/nested/a.ts"}
+	if !slices.Equal(got, want) {
+		t.Errorf("ResolveContext() = %v, want %v", got, want)
+	}
+}
+
+func TestResolveContext_Errors(t *testing.T) {
+	dir := t.TempDir()
+	writeContextFiles(t, dir, map[string]string{"src/main.go": "package main"})
+
+	tests := []struct {
+		entry   string
+		wantMsg string
+	}{
+		{"docs/missing.md", "path does not exist: docs/missing.md"},
+		{"src/*.ts", "no files match: src/*.ts"},
+		{"src", "path is a directory: src"},
+		{"../outside.md", "path must be inside the project"},
+		{"src/[", "invalid glob"},
+		{"@", "path is empty"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.entry, func(t *testing.T) {
+			_, err := ResolveContext(dir, []string{tt.entry})
+			if err == nil {
+				t.Fatal("ResolveContext() error = nil, want error")
+			}
+			testutil.AssertContains(t, err.Error(), tt.wantMsg)
+		})
+	}
+}
+
+func TestLoadContext_ReadsFiles(t *testing.T) {
+	dir := t.TempDir()
+	writeContextFiles(t, dir, map[string]string{"docs/api.md": "# API\n"})
+
+	files, err := LoadContext(dir, []string{"@docs/api.md"})
+	if err != nil {
+		t.Fatalf("LoadContext() error = %v", err)
+	}
+
+	want := []domain.ContextFile{{Path: "docs/api.md", Content: "# API\n"}}
+	if !slices.Equal(files, want) {
+		t.Errorf("LoadContext() = %+v, want %+v", files, want)
+	}
+}
+
+func TestLoadContext_EnforcesSizeLimits(t *testing.T) {
+	dir := t.TempDir()
+	big := strings.Repeat("x", MaxContextFileBytes+10)
+	contents := map[string]string{}
+	names := []string{"a.txt", "b.txt", "c.txt", "d.txt", "e.txt"}
+	for _, name := range names {
+		contents[name] = big
+	}
+	writeContextFiles(t, dir, contents)
+
+	files, err := LoadContext(dir, []string{"*.txt"})
+	if err != nil {
+		t.Fatalf("LoadContext() error = %v", err)
+	}
+	if len(files) != len(names) {
+		t.Fatalf("LoadContext() returned %d files, want %d", len(files), len(names))
+	}
+
+	total := 0
+	for _, f := range files {
+		if !f.Truncated {
+			t.Errorf("%s: Truncated = false, want true", f.Path)
+		}
+		if len(f.Content) > MaxContextFileBytes {
+			t.Errorf("%s: content is %d bytes, want at most %d", f.Path, len(f.Content), MaxContextFileBytes)
+		}
+		total += len(f.Content)
+	}
+	if total > MaxContextTotalBytes {
+		t.Errorf("total content = %d bytes, want at most %d", total, MaxContextTotalBytes)
+	}
+	if files[4].Content != "" {
+		t.Errorf("last file content = %d bytes, want 0 once the total limit is reached", len(files[4].Content))
+	}
+}
+
+func TestValidateContext_ReportsFields(t *testing.T) {
+	dir := t.TempDir()
+	writeContextFiles(t, dir, map[string]string{"docs/api.md": "# API"})
+
+	sprint := &domain.Sprint{
+		Name: "test",
+		Tickets: []domain.Ticket{{
+			Name:    "ticket-1",
+			Context: []string{"docs/api.md", "docs/missing.md"},
+			Tasks: []domain.Task{
+				{Description: "task", Context: []string{"src/**/*.go"}},
+			},
+		}},
+	}
+
+	errs := ValidateContext(dir, sprint)
+	if len(errs) != 2 {
+		t.Fatalf("expected 2 errors, got %d: %v", len(errs), errs)
+	}
+	if errs[0].Field != "tickets[0].context[1]" {
+		t.Errorf("Field: got %q, want %q", errs[0].Field, "tickets[0].context[1]")
+	}
+	if errs[1].Field != "tickets[0].tasks[0].context[0]" {
+		t.Errorf("Field: got %q, want %q", errs[1].Field, "tickets[0].tasks[0].context[0]")
+	}
+}
+
+func TestTruncateUTF8_KeepsRunesWhole(t *testing.T) {
+	if got := truncateUTF8("héllo", 2); got != "h" {
+		t.Errorf("truncateUTF8() = %q, want %q", got, "h")
+	}
+}
@@ -56,9 +56,10 @@ func SaveTicketHistory(dir string, history *domain.TicketHistory) error {
 	return nil
 }
 
-// RecordCompleted loads the ticket history, appends a completed task, and saves.
+// RecordCompleted loads the ticket history, appends a completed task with the
+// usage of its passing attempt, and saves. Usage may be nil when unknown.
 // Uses file locking to prevent concurrent write races.
-func RecordCompleted(dir, ticketName, taskDesc, summary string) error {
+func RecordCompleted(dir, ticketName, taskDesc, summary string, usage *domain.Usage) error {
 	unlock, err := acquireHistoryLock(dir, ticketName)
 	if err != nil {
 		return err
@@ -73,14 +74,16 @@ func RecordCompleted(dir, ticketName, taskDesc, summary string) error {
 	history.Completed = append(history.Completed, domain.CompletedTask{
 		Task:    taskDesc,
 		Summary: summary,
+		Usage:   usage,
 	})
 
 	return SaveTicketHistory(dir, history)
 }
 
 // RecordFailed loads the ticket history, appends a failed attempt, and saves.
+// The attempt's usage may be nil when unknown.
 // Uses file locking to prevent concurrent write races.
-func RecordFailed(dir, ticketName, taskDesc, summary string) error {
+func RecordFailed(dir, ticketName string, attempt domain.FailedAttempt) error {
 	unlock, err := acquireHistoryLock(dir, ticketName)
 	if err != nil {
 		return err
@@ -92,10 +95,7 @@ func RecordFailed(dir, ticketName, taskDesc, summary string) error {
 		return err
 	}
 
-	history.FailedAttempts = append(history.FailedAttempts, domain.FailedAttempt{
-		Task:    taskDesc,
-		Summary: summary,
-	})
+	history.FailedAttempts = append(history.FailedAttempts, attempt)
 
 	return SaveTicketHistory(dir, history)
 }
@@ -119,6 +119,63 @@ func RecordInsight(dir, ticketName, text string) error {
 	return SaveTicketHistory(dir, history)
 }
 
+// RecordQuestion loads the ticket history, appends a question an agent asked
+// while working on a task with its answer, and saves it.
+func RecordQuestion(dir, ticketName, taskDesc, question, answer string) error {
+	unlock, err := acquireHistoryLock(dir, ticketName)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	history, err := LoadTicketHistory(dir, ticketName)
+	if err != nil {
+		return err
+	}
+
+	history.Questions = append(history.Questions, domain.Question{Task: taskDesc, Question: question, Answer: answer})
+
+	return SaveTicketHistory(dir, history)
+}
+
+// RecordPlan loads the ticket history, appends the plan made for a task, and
+// saves. Uses file locking to prevent concurrent write races.
+func RecordPlan(dir, ticketName, taskDesc, plan string) error {
+	unlock, err := acquireHistoryLock(dir, ticketName)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	history, err := LoadTicketHistory(dir, ticketName)
+	if err != nil {
+		return err
+	}
+
+	history.Plans = append(history.Plans, domain.TaskPlan{Task: taskDesc, Plan: plan})
+
+	return SaveTicketHistory(dir, history)
+}
+
+// RecordManualAction loads the ticket history, appends a manual action, and saves.
+// Uses file locking to prevent concurrent write races.
+func RecordManualAction(dir, ticketName string, action domain.ManualAction) error {
+	unlock, err := acquireHistoryLock(dir, ticketName)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	history, err := LoadTicketHistory(dir, ticketName)
+	if err != nil {
+		return err
+	}
+
+	history.ManualActions = append(history.ManualActions, action)
+
+	return SaveTicketHistory(dir, history)
+}
+
 // acquireHistoryLock creates a lock file for the given ticket to prevent concurrent writes.
 // Returns an unlock function that must be called when done.
 func acquireHistoryLock(dir, ticketName string) (func(), error) {
@@ -128,8 +185,12 @@ func acquireHistoryLock(dir, ticketName string) (func(), error) {
 	}
 
 	filename := sanitizeFilename(ticketName)
-	lockPath := filepath.Join(lockDir, filename+".lock")
+	return acquireFileLock(filepath.Join(lockDir, filename+".lock"), "history lock for "+ticketName)
+}
 
+// acquireFileLock waits up to two seconds to create lockPath exclusively.
+// Returns an unlock function that must be called when done.
+func acquireFileLock(lockPath, name string) (func(), error) {
 	var lockFile *os.File
 	for range 200 {
 		f, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600) // #nosec G304 -- path derived from user working directory
@@ -138,13 +199,13 @@ func acquireHistoryLock(dir, ticketName string) (func(), error) {
 			break
 		}
 		if !errors.Is(err, os.ErrExist) && !errors.Is(err, os.ErrPermission) {
-			return nil, fmt.Errorf("acquiring history lock: %w", err)
+			return nil, fmt.Errorf("acquiring %s: %w", name, err)
 		}
 		time.Sleep(10 * time.Millisecond)
 	}
 
 	if lockFile == nil {
-		return nil, fmt.Errorf("timeout acquiring history lock for %s", ticketName)
+		return nil, fmt.Errorf("timeout acquiring %s", name)
 	}
 
 	return func() {
@@ -194,7 +255,11 @@ func GetHistorySummary(history *domain.TicketHistory) domain.HistorySummary {
 		TotalCompleted: len(history.Completed),
 		TotalFailed:    len(history.FailedAttempts),
 		TotalInsights:  len(history.Insights),
+		TotalPlans:     len(history.Plans),
+		TotalQuestions: len(history.Questions),
+		TotalManual:    len(history.ManualActions),
 		TicketCount:    1,
+		Usage:          historyUsage(history),
 	}
 }
 
@@ -208,11 +273,115 @@ func GetAllHistoriesSummary(histories []*domain.TicketHistory) domain.HistorySum
 		summary.TotalCompleted += len(h.Completed)
 		summary.TotalFailed += len(h.FailedAttempts)
 		summary.TotalInsights += len(h.Insights)
+		summary.TotalPlans += len(h.Plans)
+		summary.TotalQuestions += len(h.Questions)
+		summary.TotalManual += len(h.ManualActions)
 		summary.TicketCount++
+		summary.Usage.Add(historyUsage(h))
 	}
 	return summary
 }
 
+// historyUsage sums the usage of every attempt in a history.
+func historyUsage(history *domain.TicketHistory) domain.Usage {
+	var usage domain.Usage
+	for _, c := range history.Completed {
+		if c.Usage != nil {
+			usage.Add(*c.Usage)
+		}
+	}
+	for _, f := range history.FailedAttempts {
+		if f.Usage != nil {
+			usage.Add(*f.Usage)
+		}
+	}
+	return usage
+}
+
+// GetSprintStats returns the attempts and usage per task, ticket and sprint.
+// Every completed task and failed attempt counts as one attempt. Tickets and
+// tasks keep the order of the histories and of their first attempt.
+func GetSprintStats(histories []*domain.TicketHistory) domain.SprintStats {
+	var stats domain.SprintStats
+	for _, h := range histories {
+		if h == nil {
+			continue
+		}
+
+		ticket := domain.TicketStats{Ticket: h.Ticket, Tasks: []domain.TaskStats{}}
+		tasks := make(map[string]int)
+		record := func(task string, usage *domain.Usage) {
+			i, ok := tasks[task]
+			if !ok {
+				i = len(ticket.Tasks)
+				tasks[task] = i
+				ticket.Tasks = append(ticket.Tasks, domain.TaskStats{Task: task})
+			}
+			ticket.Tasks[i].Attempts++
+			ticket.Attempts++
+			if usage != nil {
+				ticket.Tasks[i].Usage.Add(*usage)
+				ticket.Usage.Add(*usage)
+			}
+		}
+		for _, f := range h.FailedAttempts {
+			record(f.Task, f.Usage)
+		}
+		for _, c := range h.Completed {
+			record(c.Task, c.Usage)
+		}
+
+		stats.Attempts += ticket.Attempts
+		stats.Usage.Add(ticket.Usage)
+		stats.Tickets = append(stats.Tickets, ticket)
+	}
+	if stats.Tickets == nil {
+		stats.Tickets = []domain.TicketStats{}
+	}
+	return stats
+}
+
+// History entry kinds accepted by FilterHistory.
+const (
+	HistoryCompleted = "completed"
+	HistoryFailed    = "failed"
+	HistoryInsights  = "insights"
+	HistoryPlans     = "plans"
+	HistoryQuestions = "questions"
+	HistoryManual    = "manual"
+)
+
+// FilterHistory returns a copy of history holding only the given entry kind.
+// An empty kind keeps everything.
+func FilterHistory(history *domain.TicketHistory, kind string) (*domain.TicketHistory, error) {
+	filtered := &domain.TicketHistory{Ticket: history.Ticket}
+	switch kind {
+	case "":
+		*filtered = *history
+	case HistoryCompleted:
+		filtered.Completed = history.Completed
+	case HistoryFailed:
+		filtered.FailedAttempts = history.FailedAttempts
+	case HistoryInsights:
+		filtered.Insights = history.Insights
+	case HistoryPlans:
+		filtered.Plans = history.Plans
+	case HistoryQuestions:
+		filtered.Questions = history.Questions
+	case HistoryManual:
+		filtered.ManualActions = history.ManualActions
+	default:
+		return nil, fmt.Errorf("unknown history type %q: want %s, %s, %s, %s, %s or %s",
+			kind, HistoryCompleted, HistoryFailed, HistoryInsights, HistoryPlans, HistoryQuestions, HistoryManual)
+	}
+	return filtered, nil
+}
+
+// WorktreeDir returns the worktree path used for a ticket during parallel runs.
+func WorktreeDir(dir, ticketName string) string {
+	return filepath.Join(dir, ".kamaji", "worktrees", sanitizeFilename(ticketName))
+}
+
 // sanitizeFilename replaces characters that are invalid in filenames across platforms.
 func sanitizeFilename(name string) string {
 	replacer := strings.NewReplacer(
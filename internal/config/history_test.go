@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"sync"
 	"testing"
@@ -242,7 +243,7 @@ func TestSaveTicketHistory_SanitizesAllPlatformCharacters(t *testing.T) {
 func TestRecordCompleted_EmptyHistory(t *testing.T) {
 	dir := t.TempDir()
 
-	if err := RecordCompleted(dir, "new-ticket", "Task 1", "Completed task one"); err != nil {
+	if err := RecordCompleted(dir, "new-ticket", "Task 1", "Completed task one", nil); err != nil {
 		t.Fatalf("RecordCompleted error: %v", err)
 	}
 
@@ -275,7 +276,7 @@ func TestRecordCompleted_ExistingHistory(t *testing.T) {
 		t.Fatalf("SaveTicketHistory error: %v", err)
 	}
 
-	if err := RecordCompleted(dir, "existing-ticket", "Task 2", "Second task"); err != nil {
+	if err := RecordCompleted(dir, "existing-ticket", "Task 2", "Second task", nil); err != nil {
 		t.Fatalf("RecordCompleted error: %v", err)
 	}
 
@@ -298,7 +299,7 @@ func TestRecordCompleted_ExistingHistory(t *testing.T) {
 func TestRecordFailed_EmptyHistory(t *testing.T) {
 	dir := t.TempDir()
 
-	if err := RecordFailed(dir, "new-ticket", "Failed task", "Something went wrong"); err != nil {
+	if err := RecordFailed(dir, "new-ticket", domain.FailedAttempt{Task: "Failed task", Summary: "Something went wrong"}); err != nil {
 		t.Fatalf("RecordFailed error: %v", err)
 	}
 
@@ -331,7 +332,7 @@ func TestRecordFailed_ExistingHistory(t *testing.T) {
 		t.Fatalf("SaveTicketHistory error: %v", err)
 	}
 
-	if err := RecordFailed(dir, "existing-ticket", "Second failure", "Reason 2"); err != nil {
+	if err := RecordFailed(dir, "existing-ticket", domain.FailedAttempt{Task: "Second failure", Summary: "Reason 2"}); err != nil {
 		t.Fatalf("RecordFailed error: %v", err)
 	}
 
@@ -419,6 +420,74 @@ func TestRecordInsight_DuplicateAppended(t *testing.T) {
 	}
 }
 
+func TestRecordPlan_Appends(t *testing.T) {
+	dir := t.TempDir()
+
+	if err := RecordPlan(dir, "ticket", "Add API", "1. Add handler\n2. Add route"); err != nil {
+		t.Fatalf("RecordPlan error: %v", err)
+	}
+
+	history, err := LoadTicketHistory(dir, "ticket")
+	if err != nil {
+		t.Fatalf("LoadTicketHistory error: %v", err)
+	}
+
+	want := []domain.TaskPlan{{Task: "Add API", Plan: "1. Add handler\n2. Add route"}}
+	if !reflect.DeepEqual(history.Plans, want) {
+		t.Errorf("Plans: got %+v, want %+v", history.Plans, want)
+	}
+}
+
+func TestRecordQuestion_Appends(t *testing.T) {
+	dir := t.TempDir()
+
+	if err := RecordQuestion(dir, "ticket", "Add API", "REST or gRPC?", "REST"); err != nil {
+		t.Fatalf("RecordQuestion error: %v", err)
+	}
+
+	history, err := LoadTicketHistory(dir, "ticket")
+	if err != nil {
+		t.Fatalf("LoadTicketHistory error: %v", err)
+	}
+
+	want := []domain.Question{{Task: "Add API", Question: "REST or gRPC?", Answer: "REST"}}
+	if !reflect.DeepEqual(history.Questions, want) {
+		t.Errorf("Questions: got %+v, want %+v", history.Questions, want)
+	}
+}
+
+func TestRecordManualAction_Appends(t *testing.T) {
+	dir := t.TempDir()
+
+	if err := RecordCompleted(dir, "ticket", "Task 1", "Done", nil); err != nil {
+		t.Fatalf("RecordCompleted error: %v", err)
+	}
+	if err := RecordManualAction(dir, "ticket", domain.ManualAction{Action: "skip", Task: "Task 2", Detail: "after 3 failed attempts"}); err != nil {
+		t.Fatalf("RecordManualAction error: %v", err)
+	}
+	if err := RecordManualAction(dir, "ticket", domain.ManualAction{Action: "reset"}); err != nil {
+		t.Fatalf("RecordManualAction error: %v", err)
+	}
+
+	history, err := LoadTicketHistory(dir, "ticket")
+	if err != nil {
+		t.Fatalf("LoadTicketHistory error: %v", err)
+	}
+
+	if len(history.Completed) != 1 {
+		t.Errorf("Completed length: got %d, want 1", len(history.Completed))
+	}
+	if len(history.ManualActions) != 2 {
+		t.Fatalf("ManualActions length: got %d, want 2", len(history.ManualActions))
+	}
+	if got := history.ManualActions[0]; got.Action != "skip" || got.Task != "Task 2" || got.Detail != "after 3 failed attempts" {
+		t.Errorf("ManualActions[0]: got %+v", got)
+	}
+	if got := history.ManualActions[1].Action; got != "reset" {
+		t.Errorf("ManualActions[1].Action: got %q, want %q", got, "reset")
+	}
+}
+
 func TestListTicketHistories_EmptyDirectory(t *testing.T) {
 	dir := t.TempDir()
 	historyDir := filepath.Join(dir, ".kamaji", "history")
@@ -697,7 +766,7 @@ func TestConcurrentWrites_NoCorruption(t *testing.T) {
 		go func(id int) {
 			defer wg.Done()
 			for j := range writesPerWriter {
-				if err := RecordCompleted(dir, ticket, fmt.Sprintf("task-%d-%d", id, j), "done"); err != nil {
+				if err := RecordCompleted(dir, ticket, fmt.Sprintf("task-%d-%d", id, j), "done", nil); err != nil {
 					t.Errorf("RecordCompleted failed: %v", err)
 				}
 			}
@@ -706,7 +775,7 @@ func TestConcurrentWrites_NoCorruption(t *testing.T) {
 		go func(id int) {
 			defer wg.Done()
 			for j := range writesPerWriter {
-				if err := RecordFailed(dir, ticket, fmt.Sprintf("fail-%d-%d", id, j), "error"); err != nil {
+				if err := RecordFailed(dir, ticket, domain.FailedAttempt{Task: fmt.Sprintf("fail-%d-%d", id, j), Summary: "error"}); err != nil {
 					t.Errorf("RecordFailed failed: %v", err)
 				}
 			}
@@ -743,3 +812,109 @@ func TestConcurrentWrites_NoCorruption(t *testing.T) {
 		t.Errorf("Insights: got %d, want %d", len(history.Insights), expectedInsights)
 	}
 }
+
+func TestFilterHistory(t *testing.T) {
+	history := &domain.TicketHistory{
+		Ticket:         "ticket-1",
+		Completed:      []domain.CompletedTask{{Task: "a", Summary: "done"}},
+		FailedAttempts: []domain.FailedAttempt{{Task: "b", Summary: "broken"}},
+		Insights:       []string{"insight"},
+		Plans:          []domain.TaskPlan{{Task: "b", Plan: "steps"}},
+		Questions:      []domain.Question{{Task: "b", Question: "which?", Answer: "this"}},
+		ManualActions:  []domain.ManualAction{{Action: "skip", Task: "b"}},
+	}
+
+	tests := []struct {
+		kind                                               string
+		completed, failed, insight, plan, question, manual int
+	}{
+		{"", 1, 1, 1, 1, 1, 1},
+		{HistoryCompleted, 1, 0, 0, 0, 0, 0},
+		{HistoryFailed, 0, 1, 0, 0, 0, 0},
+		{HistoryInsights, 0, 0, 1, 0, 0, 0},
+		{HistoryPlans, 0, 0, 0, 1, 0, 0},
+		{HistoryQuestions, 0, 0, 0, 0, 1, 0},
+		{HistoryManual, 0, 0, 0, 0, 0, 1},
+	}
+
+	for _, tt := range tests {
+		got, err := FilterHistory(history, tt.kind)
+		if err != nil {
+			t.Fatalf("FilterHistory(%q) error = %v", tt.kind, err)
+		}
+		if got.Ticket != "ticket-1" {
+			t.Errorf("FilterHistory(%q).Ticket = %q, want %q", tt.kind, got.Ticket, "ticket-1")
+		}
+		if len(got.Completed) != tt.completed || len(got.FailedAttempts) != tt.failed ||
+			len(got.Insights) != tt.insight || len(got.Plans) != tt.plan || len(got.Questions) != tt.question ||
+			len(got.ManualActions) != tt.manual {
+			t.Errorf("FilterHistory(%q) = %d/%d/%d/%d/%d/%d entries, want %d/%d/%d/%d/%d/%d", tt.kind,
+				len(got.Completed), len(got.FailedAttempts), len(got.Insights), len(got.Plans), len(got.Questions), len(got.ManualActions),
+				tt.completed, tt.failed, tt.insight, tt.plan, tt.question, tt.manual)
+		}
+	}
+
+	if _, err := FilterHistory(history, "bogus"); err == nil {
+		t.Error("FilterHistory(bogus) error = nil, want error")
+	}
+}
+
+func TestGetSprintStats(t *testing.T) {
+	histories := []*domain.TicketHistory{
+		{
+			Ticket: "schema",
+			FailedAttempts: []domain.FailedAttempt{
+				{Task: "Create tables", Usage: &domain.Usage{InputTokens: 100, CostUSD: 0.1}},
+				{Task: "Create tables"},
+			},
+			Completed: []domain.CompletedTask{
+				{Task: "Create tables", Usage: &domain.Usage{InputTokens: 50, CostUSD: 0.2}},
+				{Task: "Add index", Usage: &domain.Usage{OutputTokens: 10, CostUSD: 0.3}},
+			},
+			Insights: []string{"not an attempt"},
+		},
+		nil,
+		{Ticket: "api"},
+	}
+
+	stats := GetSprintStats(histories)
+
+	if stats.Attempts != 4 {
+		t.Errorf("Attempts: got %d, want 4", stats.Attempts)
+	}
+	if stats.Usage.Tokens() != 160 {
+		t.Errorf("Tokens: got %d, want 160", stats.Usage.Tokens())
+	}
+	if len(stats.Tickets) != 2 {
+		t.Fatalf("Tickets: got %d, want 2", len(stats.Tickets))
+	}
+
+	schema := stats.Tickets[0]
+	if len(schema.Tasks) != 2 {
+		t.Fatalf("schema tasks: got %d, want 2", len(schema.Tasks))
+	}
+	if got := schema.Tasks[0]; got.Task != "Create tables" || got.Attempts != 3 || got.Usage.InputTokens != 150 {
+		t.Errorf("Create tables stats: got %+v", got)
+	}
+	if got := schema.Tasks[1]; got.Task != "Add index" || got.Attempts != 1 || got.Usage.OutputTokens != 10 {
+		t.Errorf("Add index stats: got %+v", got)
+	}
+
+	if api := stats.Tickets[1]; api.Attempts != 0 || api.Tasks == nil {
+		t.Errorf("api stats: got %+v, want no attempts and empty tasks", api)
+	}
+}
+
+func TestGetAllHistoriesSummary_SumsUsage(t *testing.T) {
+	histories := []*domain.TicketHistory{
+		{Ticket: "a", Completed: []domain.CompletedTask{{Usage: &domain.Usage{InputTokens: 5, CostUSD: 1}}}},
+		{Ticket: "b", FailedAttempts: []domain.FailedAttempt{{Usage: &domain.Usage{OutputTokens: 7, CostUSD: 2}}}},
+	}
+
+	summary := GetAllHistoriesSummary(histories)
+
+	want := domain.Usage{InputTokens: 5, OutputTokens: 7, CostUSD: 3}
+	if summary.Usage != want {
+		t.Errorf("Usage: got %+v, want %+v", summary.Usage, want)
+	}
+}
@@ -0,0 +1,172 @@
+package config
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/sqve/kamaji/internal/domain"
+)
+
+// maxEventLine bounds a single journal line; sprint_start events embed the state.
+const maxEventLine = 16 << 20
+
+// journalMu serializes appends from concurrent ticket workers.
+var journalMu sync.Mutex
+
+// AppendEvent adds an event to .kamaji/events.jsonl, stamping the current time
+// when unset. Creates the .kamaji directory if it doesn't exist.
+func AppendEvent(dir string, event domain.Event) error {
+	if event.Time.IsZero() {
+		event.Time = time.Now().UTC()
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("marshaling event: %w", err)
+	}
+
+	journalMu.Lock()
+	defer journalMu.Unlock()
+
+	kamajiDir := filepath.Join(dir, ".kamaji")
+	if err := os.MkdirAll(kamajiDir, 0o750); err != nil {
+		return fmt.Errorf("creating .kamaji directory: %w", err)
+	}
+
+	path := filepath.Join(kamajiDir, "events.jsonl")
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600) // #nosec G304 -- path derived from user working directory
+	if err != nil {
+		return fmt.Errorf("opening event journal: %w", err)
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("writing event journal: %w", err)
+	}
+	return f.Close()
+}
+
+// AppendProgress journals the ticket's saved progress under key, so replay
+// can rebuild the state. A ticket without progress is journaled as removed.
+func AppendProgress(dir string, state *domain.State, key, ticketName string) error {
+	event := domain.Event{Type: domain.EventStateSaved, Ticket: ticketName, Key: key}
+	if progress, ok := state.Tickets[key]; ok {
+		event.Progress = &progress
+	}
+	return AppendEvent(dir, event)
+}
+
+// LoadEvents reads .kamaji/events.jsonl in order. Returns nil if the journal
+// doesn't exist. A malformed final line, left by a crash mid-write, is skipped.
+func LoadEvents(dir string) ([]domain.Event, error) {
+	path := filepath.Join(dir, ".kamaji", "events.jsonl")
+
+	f, err := os.Open(path) // #nosec G304 -- path derived from user working directory
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading event journal: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	var (
+		events  []domain.Event
+		badLine int
+	)
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxEventLine)
+	for line := 1; scanner.Scan(); line++ {
+		if badLine != 0 {
+			return nil, fmt.Errorf("parsing event journal line %d", badLine)
+		}
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var event domain.Event
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			badLine = line
+			continue
+		}
+		events = append(events, event)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading event journal: %w", err)
+	}
+
+	return events, nil
+}
+
+// Replay rebuilds the state and ticket histories from journal events. A
+// sprint_start snapshot replaces the state rebuilt so far, which recovers
+// progress saved before the journal existed. Histories are returned in the
+// order their tickets first appear.
+func Replay(events []domain.Event) (*domain.State, []*domain.TicketHistory) {
+	state := &domain.State{}
+	var histories []*domain.TicketHistory
+	byTicket := make(map[string]*domain.TicketHistory)
+	history := func(ticket string) *domain.TicketHistory {
+		h, ok := byTicket[ticket]
+		if !ok {
+			h = &domain.TicketHistory{Ticket: ticket}
+			byTicket[ticket] = h
+			histories = append(histories, h)
+		}
+		return h
+	}
+
+	for _, e := range events {
+		switch e.Type {
+		case domain.EventSprintStart:
+			if e.State != nil {
+				state = &domain.State{}
+				for key, progress := range e.State.Tickets {
+					setReplayedProgress(state, key, progress)
+				}
+			}
+		case domain.EventStateSaved:
+			if e.Progress == nil {
+				delete(state.Tickets, e.Key)
+			} else {
+				setReplayedProgress(state, e.Key, *e.Progress)
+			}
+		case domain.EventCompleted:
+			h := history(e.Ticket)
+			h.Completed = append(h.Completed, domain.CompletedTask{Task: e.Task, Summary: e.Summary, Usage: e.Usage})
+		case domain.EventFailed:
+			h := history(e.Ticket)
+			h.FailedAttempts = append(h.FailedAttempts, domain.FailedAttempt{Task: e.Task, Summary: e.Summary, TimedOut: e.TimedOut, Usage: e.Usage})
+		case domain.EventInsight:
+			h := history(e.Ticket)
+			h.Insights = append(h.Insights, e.Summary)
+		case domain.EventPlan:
+			h := history(e.Ticket)
+			h.Plans = append(h.Plans, domain.TaskPlan{Task: e.Task, Plan: e.Summary})
+		case domain.EventQuestion:
+			h := history(e.Ticket)
+			h.Questions = append(h.Questions, domain.Question{Task: e.Task, Question: e.Summary, Answer: e.Answer})
+		case domain.EventManualAction:
+			if e.Action != nil {
+				h := history(e.Ticket)
+				h.ManualActions = append(h.ManualActions, *e.Action)
+			}
+		}
+	}
+
+	return state, histories
+}
+
+func setReplayedProgress(state *domain.State, key string, progress domain.TicketState) {
+	if state.Tickets == nil {
+		state.Tickets = make(map[string]domain.TicketState)
+	}
+	progress.Done = slices.Clone(progress.Done)
+	progress.Skipped = slices.Clone(progress.Skipped)
+	state.Tickets[key] = progress
+}
@@ -0,0 +1,219 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/sqve/kamaji/internal/domain"
+)
+
+func TestAppendEvent_RoundTrips(t *testing.T) {
+	dir := t.TempDir()
+
+	events := []domain.Event{
+		{Type: domain.EventBranchCreated, Ticket: "login", Branch: "feat/login"},
+		{Type: domain.EventSignal, Ticket: "login", Task: "Add form", Tool: "task_complete", Status: "pass", Summary: "Done"},
+	}
+	for _, e := range events {
+		if err := AppendEvent(dir, e); err != nil {
+			t.Fatalf("AppendEvent error: %v", err)
+		}
+	}
+
+	loaded, err := LoadEvents(dir)
+	if err != nil {
+		t.Fatalf("LoadEvents error: %v", err)
+	}
+	if len(loaded) != 2 {
+		t.Fatalf("events: got %d, want 2", len(loaded))
+	}
+	if loaded[0].Time.IsZero() {
+		t.Error("Time: got zero, want stamped time")
+	}
+	if loaded[1].Tool != "task_complete" || loaded[1].Summary != "Done" {
+		t.Errorf("event: got %+v", loaded[1])
+	}
+}
+
+func TestAppendEvent_Concurrent(t *testing.T) {
+	dir := t.TempDir()
+
+	var wg sync.WaitGroup
+	for range 20 {
+		wg.Go(func() {
+			if err := AppendEvent(dir, domain.Event{Type: domain.EventSpawn, Summary: strings.Repeat("x", 4096)}); err != nil {
+				t.Errorf("AppendEvent error: %v", err)
+			}
+		})
+	}
+	wg.Wait()
+
+	events, err := LoadEvents(dir)
+	if err != nil {
+		t.Fatalf("LoadEvents error: %v", err)
+	}
+	if len(events) != 20 {
+		t.Errorf("events: got %d, want 20", len(events))
+	}
+}
+
+func TestLoadEvents_MissingFile(t *testing.T) {
+	events, err := LoadEvents(t.TempDir())
+	if err != nil {
+		t.Fatalf("LoadEvents error: %v", err)
+	}
+	if events != nil {
+		t.Errorf("events: got %v, want nil", events)
+	}
+}
+
+func TestLoadEvents_MalformedLines(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    int
+		wantErr string
+	}{
+		{
+			name:    "truncated final line is skipped",
+			content: `{"type":"spawn"}` + "\n" + `{"type":"sig`,
+			want:    1,
+		},
+		{
+			name:    "malformed line before others fails",
+			content: `{"type":"spawn"}` + "\n" + `not json` + "\n" + `{"type":"spawn"}` + "\n",
+			wantErr: "parsing event journal line 2",
+		},
+		{
+			name:    "blank lines are ignored",
+			content: `{"type":"spawn"}` + "\n\n" + `{"type":"reset"}` + "\n",
+			want:    2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeJournal(t, dir, tt.content)
+
+			events, err := LoadEvents(dir)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("LoadEvents error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadEvents error: %v", err)
+			}
+			if len(events) != tt.want {
+				t.Errorf("events: got %d, want %d", len(events), tt.want)
+			}
+		})
+	}
+}
+
+func TestAppendProgress_RecordsRemoval(t *testing.T) {
+	dir := t.TempDir()
+	state := &domain.State{Tickets: map[string]domain.TicketState{"login": {Done: []string{"a"}}}}
+
+	if err := AppendProgress(dir, state, "login", "Login"); err != nil {
+		t.Fatalf("AppendProgress error: %v", err)
+	}
+	delete(state.Tickets, "login")
+	if err := AppendProgress(dir, state, "login", "Login"); err != nil {
+		t.Fatalf("AppendProgress error: %v", err)
+	}
+
+	events, err := LoadEvents(dir)
+	if err != nil {
+		t.Fatalf("LoadEvents error: %v", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("events: got %d, want 2", len(events))
+	}
+	if events[0].Progress == nil || !reflect.DeepEqual(events[0].Progress.Done, []string{"a"}) {
+		t.Errorf("first progress: got %+v, want done [a]", events[0].Progress)
+	}
+	if events[1].Progress != nil {
+		t.Errorf("second progress: got %+v, want nil", events[1].Progress)
+	}
+}
+
+func TestReplay(t *testing.T) {
+	events := []domain.Event{
+		{Type: domain.EventSprintStart, State: &domain.State{Tickets: map[string]domain.TicketState{
+			"old":  {Done: []string{"x"}},
+			"kept": {Done: []string{"y"}},
+		}}},
+		{Type: domain.EventFailed, Ticket: "login", Task: "Add form", Summary: "timed out after 5m", TimedOut: true},
+		{Type: domain.EventStateSaved, Key: "login", Progress: &domain.TicketState{FailingTask: "Add form", FailureCount: 1}},
+		{Type: domain.EventInsight, Ticket: "login", Summary: "uses zod"},
+		{Type: domain.EventPlan, Ticket: "login", Task: "Add form", Summary: "1. Add form"},
+		{Type: domain.EventQuestion, Ticket: "login", Task: "Add form", Summary: "Which schema?", Answer: "zod"},
+		{Type: domain.EventCompleted, Ticket: "login", Task: "Add form", Summary: "added form"},
+		{Type: domain.EventStateSaved, Key: "login", Progress: &domain.TicketState{Done: []string{"Add form"}}},
+		{Type: domain.EventManualAction, Ticket: "old", Action: &domain.ManualAction{Action: "reset"}},
+		{Type: domain.EventStateSaved, Key: "old"},
+		{Type: domain.EventCommit, Ticket: "login", Commit: "abc123"},
+	}
+
+	state, histories := Replay(events)
+
+	wantState := map[string]domain.TicketState{
+		"kept":  {Done: []string{"y"}},
+		"login": {Done: []string{"Add form"}},
+	}
+	if !reflect.DeepEqual(state.Tickets, wantState) {
+		t.Errorf("state: got %+v, want %+v", state.Tickets, wantState)
+	}
+
+	if len(histories) != 2 {
+		t.Fatalf("histories: got %d, want 2", len(histories))
+	}
+	login := histories[0]
+	if login.Ticket != "login" || len(login.Completed) != 1 || len(login.FailedAttempts) != 1 || len(login.Insights) != 1 || len(login.Plans) != 1 {
+		t.Errorf("login history: got %+v", login)
+	}
+	if len(login.FailedAttempts) == 1 && !login.FailedAttempts[0].TimedOut {
+		t.Error("login failed attempt: TimedOut = false, want true")
+	}
+	wantQuestions := []domain.Question{{Task: "Add form", Question: "Which schema?", Answer: "zod"}}
+	if !reflect.DeepEqual(login.Questions, wantQuestions) {
+		t.Errorf("login questions: got %+v, want %+v", login.Questions, wantQuestions)
+	}
+	if old := histories[1]; old.Ticket != "old" || len(old.ManualActions) != 1 {
+		t.Errorf("old history: got %+v", old)
+	}
+}
+
+func TestReplay_SprintStartReplacesState(t *testing.T) {
+	events := []domain.Event{
+		{Type: domain.EventStateSaved, Key: "a", Progress: &domain.TicketState{Done: []string{"1"}}},
+		{Type: domain.EventSprintStart, State: &domain.State{Tickets: map[string]domain.TicketState{"b": {FailureCount: 2, FailingTask: "t"}}}},
+	}
+
+	state, _ := Replay(events)
+
+	if _, ok := state.Tickets["a"]; ok {
+		t.Error("ticket a: got progress, want replaced by snapshot")
+	}
+	if got := state.Tickets["b"].FailureCount; got != 2 {
+		t.Errorf("ticket b FailureCount: got %d, want 2", got)
+	}
+}
+
+func writeJournal(t *testing.T, dir, content string) {
+	t.Helper()
+	kamajiDir := filepath.Join(dir, ".kamaji")
+	if err := os.MkdirAll(kamajiDir, 0o750); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(kamajiDir, "events.jsonl"), []byte(content), 0o600); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+}
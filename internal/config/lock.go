@@ -0,0 +1,81 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// ErrSprintActive is returned when another kamaji process holds the run lock.
+var ErrSprintActive = errors.New("a sprint is running")
+
+// AcquireRunLock marks the sprint in dir as active by writing
+// .kamaji/run.lock with the current PID. A lock left behind by a process that
+// no longer exists is taken over. Returns an unlock function that must be
+// called when done.
+func AcquireRunLock(dir string) (func(), error) {
+	kamajiDir := filepath.Join(dir, ".kamaji")
+	if err := os.MkdirAll(kamajiDir, 0o750); err != nil {
+		return nil, fmt.Errorf("creating .kamaji directory: %w", err)
+	}
+
+	lockPath := filepath.Join(kamajiDir, "run.lock")
+	for range 2 {
+		f, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600) // #nosec G304 -- path derived from user working directory
+		if err == nil {
+			_, werr := f.WriteString(strconv.Itoa(os.Getpid()))
+			cerr := f.Close()
+			if werr = errors.Join(werr, cerr); werr != nil {
+				_ = os.Remove(lockPath)
+				return nil, fmt.Errorf("writing run lock: %w", werr)
+			}
+			return func() { _ = os.Remove(lockPath) }, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, fmt.Errorf("acquiring run lock: %w", err)
+		}
+
+		if pid, ok := lockOwner(lockPath); ok {
+			return nil, fmt.Errorf("%w (pid %d)", ErrSprintActive, pid)
+		}
+		if err := os.Remove(lockPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("removing stale run lock: %w", err)
+		}
+	}
+
+	return nil, ErrSprintActive
+}
+
+// lockOwner returns the PID recorded in the lock file and whether that process
+// is still running.
+func lockOwner(lockPath string) (int, bool) {
+	data, err := os.ReadFile(lockPath) // #nosec G304 -- path derived from user working directory
+	if err != nil {
+		return 0, false
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil || pid <= 0 {
+		return 0, false
+	}
+	return pid, processAlive(pid)
+}
+
+func processAlive(pid int) bool {
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	// FindProcess only succeeds for live processes on Windows, and signal 0
+	// is not supported there.
+	if runtime.GOOS == "windows" {
+		return true
+	}
+	// EPERM means the process exists but belongs to another user.
+	err = p.Signal(syscall.Signal(0))
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
@@ -0,0 +1,76 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+)
+
+func TestAcquireRunLock_WritesPIDAndUnlocks(t *testing.T) {
+	dir := t.TempDir()
+	lockPath := filepath.Join(dir, ".kamaji", "run.lock")
+
+	unlock, err := AcquireRunLock(dir)
+	if err != nil {
+		t.Fatalf("AcquireRunLock error: %v", err)
+	}
+
+	data, err := os.ReadFile(lockPath)
+	if err != nil {
+		t.Fatalf("reading lock: %v", err)
+	}
+	if got, want := string(data), strconv.Itoa(os.Getpid()); got != want {
+		t.Errorf("lock PID: got %q, want %q", got, want)
+	}
+
+	unlock()
+	if _, err := os.Stat(lockPath); !os.IsNotExist(err) {
+		t.Errorf("lock file should be removed after unlock, stat err = %v", err)
+	}
+}
+
+func TestAcquireRunLock_HeldByLiveProcess(t *testing.T) {
+	dir := t.TempDir()
+
+	unlock, err := AcquireRunLock(dir)
+	if err != nil {
+		t.Fatalf("AcquireRunLock error: %v", err)
+	}
+	defer unlock()
+
+	if _, err := AcquireRunLock(dir); !errors.Is(err, ErrSprintActive) {
+		t.Errorf("second AcquireRunLock error = %v, want ErrSprintActive", err)
+	}
+}
+
+func TestAcquireRunLock_TakesOverStaleLock(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"dead process", "999999999"},
+		{"garbage", "not a pid"},
+		{"empty", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			kamajiDir := filepath.Join(dir, ".kamaji")
+			if err := os.MkdirAll(kamajiDir, 0o750); err != nil {
+				t.Fatalf("mkdir: %v", err)
+			}
+			if err := os.WriteFile(filepath.Join(kamajiDir, "run.lock"), []byte(tt.content), 0o600); err != nil {
+				t.Fatalf("write lock: %v", err)
+			}
+
+			unlock, err := AcquireRunLock(dir)
+			if err != nil {
+				t.Fatalf("AcquireRunLock error = %v, want stale lock taken over", err)
+			}
+			unlock()
+		})
+	}
+}
@@ -8,43 +8,90 @@ import (
 	"path/filepath"
 )
 
+// ProjectMCPConfig is the project's own MCP config file, which kamaji merges
+// into the config it hands the agent but never writes.
+const ProjectMCPConfig = ".mcp.json"
+
 type mcpConfig struct {
-	MCPServers map[string]mcpServerConfig `json:"mcpServers"`
+	MCPServers map[string]json.RawMessage `json:"mcpServers"`
 }
 
 type mcpServerConfig struct {
-	Type string `json:"type"`
-	URL  string `json:"url"`
+	Type    string            `json:"type"`
+	URL     string            `json:"url"`
+	Headers map[string]string `json:"headers,omitempty"`
 }
 
-// WriteMCPConfig creates a .mcp.json file for Claude Code to connect to the kamaji MCP server.
-// If dir is empty, os.TempDir() is used. Returns the path to the created file.
-func WriteMCPConfig(dir string, port int) (string, error) {
+// WriteMCPConfig writes an MCP config for Claude Code that adds the kamaji
+// server to the servers in projectDir's .mcp.json, if there is one. The file
+// is created in a private temp location so it never lands in the work tree or
+// a commit; the caller passes it with --mcp-config and removes it afterwards.
+// The kamaji entry sends token as a bearer token when it is set. Returns the
+// path to the created file.
+func WriteMCPConfig(projectDir string, port int, token string) (string, error) {
 	if port <= 0 || port > 65535 {
 		return "", errors.New("port must be between 1 and 65535")
 	}
-	if dir == "" {
-		dir = os.TempDir()
-	}
 
-	cfg := mcpConfig{
-		MCPServers: map[string]mcpServerConfig{
-			"kamaji": {
-				Type: "http",
-				URL:  fmt.Sprintf("http://localhost:%d/mcp", port),
-			},
-		},
+	servers, err := loadProjectMCPServers(projectDir)
+	if err != nil {
+		return "", err
+	}
+	server := mcpServerConfig{
+		Type: "http",
+		URL:  fmt.Sprintf("http://127.0.0.1:%d/mcp", port),
+	}
+	if token != "" {
+		server.Headers = map[string]string{"Authorization": "Bearer " + token}
 	}
+	kamaji, err := json.Marshal(server)
+	if err != nil {
+		return "", fmt.Errorf("marshal mcp config: %w", err)
+	}
+	servers["kamaji"] = kamaji
 
-	data, err := json.MarshalIndent(cfg, "", "  ")
+	data, err := json.MarshalIndent(mcpConfig{MCPServers: servers}, "", "  ")
 	if err != nil {
 		return "", fmt.Errorf("marshal mcp config: %w", err)
 	}
 
-	path := filepath.Join(dir, ".mcp.json")
-	if err := os.WriteFile(path, data, 0o600); err != nil {
+	f, err := os.CreateTemp("", "kamaji-mcp-*.json")
+	if err != nil {
+		return "", fmt.Errorf("write mcp config: %w", err)
+	}
+	_, werr := f.Write(data)
+	cerr := f.Close()
+	if err := errors.Join(werr, cerr); err != nil {
+		_ = os.Remove(f.Name())
 		return "", fmt.Errorf("write mcp config: %w", err)
 	}
 
-	return path, nil
+	return f.Name(), nil
+}
+
+// loadProjectMCPServers returns the servers in dir's .mcp.json, or an empty
+// map when dir is empty or has none. Server entries are kept verbatim.
+func loadProjectMCPServers(dir string) (map[string]json.RawMessage, error) {
+	servers := map[string]json.RawMessage{}
+	if dir == "" {
+		return servers, nil
+	}
+
+	path := filepath.Join(dir, ProjectMCPConfig)
+	data, err := os.ReadFile(path) //nolint:gosec // path is the project's own config
+	if errors.Is(err, os.ErrNotExist) {
+		return servers, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", ProjectMCPConfig, err)
+	}
+
+	var cfg mcpConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", ProjectMCPConfig, err)
+	}
+	for name, server := range cfg.MCPServers {
+		servers[name] = server
+	}
+	return servers, nil
 }
@@ -7,106 +7,144 @@ import (
 	"testing"
 )
 
-func TestWriteMCPConfig_CreatesFile(t *testing.T) {
-	dir := t.TempDir()
-	path, err := WriteMCPConfig(dir, 8080)
+// readMCPConfig writes an MCP config for dir, port and token and decodes it.
+func readMCPConfig(t *testing.T, dir string, port int, token string) mcpConfig {
+	t.Helper()
+	path, err := WriteMCPConfig(dir, port, token)
 	if err != nil {
 		t.Fatalf("WriteMCPConfig() error = %v", err)
 	}
+	t.Cleanup(func() { _ = os.Remove(path) })
 
-	expectedPath := filepath.Join(dir, ".mcp.json")
-	if path != expectedPath {
-		t.Errorf("path = %q, want %q", path, expectedPath)
+	data, err := os.ReadFile(path) //nolint:gosec // path from WriteMCPConfig is safe
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	var cfg mcpConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
 	}
+	return cfg
+}
 
-	if _, err := os.Stat(path); os.IsNotExist(err) {
-		t.Error("File was not created")
+func kamajiServer(t *testing.T, cfg mcpConfig) mcpServerConfig {
+	t.Helper()
+	raw, ok := cfg.MCPServers["kamaji"]
+	if !ok {
+		t.Fatal("mcpServers.kamaji not found")
 	}
+	var server mcpServerConfig
+	if err := json.Unmarshal(raw, &server); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	return server
 }
 
-func TestWriteMCPConfig_CorrectJSON(t *testing.T) {
+func TestWriteMCPConfig_WritesOutsideProject(t *testing.T) {
 	dir := t.TempDir()
-	path, err := WriteMCPConfig(dir, 9000)
+	path, err := WriteMCPConfig(dir, 8080, "")
 	if err != nil {
 		t.Fatalf("WriteMCPConfig() error = %v", err)
 	}
+	defer func() { _ = os.Remove(path) }()
 
-	data, err := os.ReadFile(path) //nolint:gosec // path from WriteMCPConfig is safe
+	// Use filepath.Clean to normalize paths since os.TempDir() may include
+	// a trailing slash on some platforms (e.g., macOS).
+	if got, want := filepath.Dir(path), filepath.Clean(os.TempDir()); got != want {
+		t.Errorf("dir = %q, want %q", got, want)
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".mcp.json")); !os.IsNotExist(err) {
+		t.Error(".mcp.json should not be written to the project")
+	}
+	info, err := os.Stat(path)
 	if err != nil {
-		t.Fatalf("ReadFile() error = %v", err)
+		t.Fatalf("Stat() error = %v", err)
 	}
-
-	var cfg mcpConfig
-	if err := json.Unmarshal(data, &cfg); err != nil {
-		t.Fatalf("Unmarshal() error = %v", err)
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Errorf("mode = %v, want 0600", perm)
 	}
+}
 
-	kamaji, ok := cfg.MCPServers["kamaji"]
-	if !ok {
-		t.Fatal("mcpServers.kamaji not found")
-	}
+func TestWriteMCPConfig_CorrectJSON(t *testing.T) {
+	kamaji := kamajiServer(t, readMCPConfig(t, t.TempDir(), 9000, "secret"))
 
 	if kamaji.Type != "http" {
 		t.Errorf("type = %q, want %q", kamaji.Type, "http")
 	}
-
-	expectedURL := "http://localhost:9000/mcp"
+	expectedURL := "http://127.0.0.1:9000/mcp"
 	if kamaji.URL != expectedURL {
 		t.Errorf("url = %q, want %q", kamaji.URL, expectedURL)
 	}
+	if got := kamaji.Headers["Authorization"]; got != "Bearer secret" {
+		t.Errorf("Authorization header = %q, want %q", got, "Bearer secret")
+	}
 }
 
-func TestWriteMCPConfig_PortInterpolation(t *testing.T) {
+func TestWriteMCPConfig_MergesProjectServers(t *testing.T) {
 	dir := t.TempDir()
-	path, err := WriteMCPConfig(dir, 12345)
-	if err != nil {
-		t.Fatalf("WriteMCPConfig() error = %v", err)
+	project := `{"mcpServers": {
+		"db": {"command": "db-mcp", "args": ["--ro"], "env": {"DB": "x"}},
+		"kamaji": {"type": "http", "url": "http://example.com/mcp"}
+	}}`
+	if err := os.WriteFile(filepath.Join(dir, ".mcp.json"), []byte(project), 0o600); err != nil {
+		t.Fatal(err)
 	}
 
-	data, err := os.ReadFile(path) //nolint:gosec // path from WriteMCPConfig is safe
-	if err != nil {
-		t.Fatalf("ReadFile() error = %v", err)
-	}
+	cfg := readMCPConfig(t, dir, 9000, "")
 
-	var cfg mcpConfig
-	if err := json.Unmarshal(data, &cfg); err != nil {
-		t.Fatalf("Unmarshal() error = %v", err)
+	var db struct {
+		Command string            `json:"command"`
+		Args    []string          `json:"args"`
+		Env     map[string]string `json:"env"`
+	}
+	if err := json.Unmarshal(cfg.MCPServers["db"], &db); err != nil {
+		t.Fatalf("mcpServers.db: %v", err)
+	}
+	if db.Command != "db-mcp" || len(db.Args) != 1 || db.Env["DB"] != "x" {
+		t.Errorf("mcpServers.db = %+v, want project entry kept verbatim", db)
+	}
+	if got := kamajiServer(t, cfg).URL; got != "http://127.0.0.1:9000/mcp" {
+		t.Errorf("kamaji url = %q, want the kamaji server to replace the project entry", got)
 	}
 
-	expectedURL := "http://localhost:12345/mcp"
-	if cfg.MCPServers["kamaji"].URL != expectedURL {
-		t.Errorf("url = %q, want %q", cfg.MCPServers["kamaji"].URL, expectedURL)
+	data, err := os.ReadFile(filepath.Join(dir, ".mcp.json")) //nolint:gosec // test file
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != project {
+		t.Error("project .mcp.json should be left untouched")
 	}
 }
 
-func TestWriteMCPConfig_EmptyDirUsesTempDir(t *testing.T) {
-	path, err := WriteMCPConfig("", 8080)
-	if err != nil {
-		t.Fatalf("WriteMCPConfig() error = %v", err)
+func TestWriteMCPConfig_InvalidProjectConfig(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, ".mcp.json"), []byte("{"), 0o600); err != nil {
+		t.Fatal(err)
 	}
-	defer func() { _ = os.Remove(path) }()
+	if _, err := WriteMCPConfig(dir, 8080, ""); err == nil {
+		t.Error("WriteMCPConfig() error = nil, want error for invalid .mcp.json")
+	}
+}
 
-	// Use filepath.Clean to normalize paths since os.TempDir() may include
-	// a trailing slash on some platforms (e.g., macOS).
-	expectedDir := filepath.Clean(os.TempDir())
-	actualDir := filepath.Dir(path)
-	if actualDir != expectedDir {
-		t.Errorf("dir = %q, want %q", actualDir, expectedDir)
+func TestWriteMCPConfig_EmptyDir(t *testing.T) {
+	cfg := readMCPConfig(t, "", 8080, "")
+	if len(cfg.MCPServers) != 1 {
+		t.Errorf("mcpServers = %v, want only kamaji", cfg.MCPServers)
 	}
 }
 
 func TestWriteMCPConfig_ValidatesPort(t *testing.T) {
 	dir := t.TempDir()
 
-	if _, err := WriteMCPConfig(dir, 0); err == nil {
+	if _, err := WriteMCPConfig(dir, 0, ""); err == nil {
 		t.Error("WriteMCPConfig() error = nil, want error for zero port")
 	}
 
-	if _, err := WriteMCPConfig(dir, -1); err == nil {
+	if _, err := WriteMCPConfig(dir, -1, ""); err == nil {
 		t.Error("WriteMCPConfig() error = nil, want error for negative port")
 	}
 
-	if _, err := WriteMCPConfig(dir, 65536); err == nil {
+	if _, err := WriteMCPConfig(dir, 65536, ""); err == nil {
 		t.Error("WriteMCPConfig() error = nil, want error for port > 65535")
 	}
 }
@@ -0,0 +1,139 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/sqve/kamaji/internal/domain"
+	"gopkg.in/yaml.v3"
+)
+
+// proposalQueue is the layout of .kamaji/proposals.yaml.
+type proposalQueue struct {
+	Proposals []domain.Proposal `yaml:"proposals"`
+}
+
+// LoadProposals reads every proposal from .kamaji/proposals.yaml, oldest
+// first. Returns nil if the file doesn't exist.
+func LoadProposals(dir string) ([]domain.Proposal, error) {
+	path := filepath.Join(dir, ".kamaji", "proposals.yaml")
+
+	data, err := os.ReadFile(path) // #nosec G304 -- path derived from user working directory
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading proposals file: %w", err)
+	}
+
+	var queue proposalQueue
+	if err := yaml.Unmarshal(data, &queue); err != nil {
+		return nil, fmt.Errorf("parsing proposals YAML: %w", err)
+	}
+
+	return queue.Proposals, nil
+}
+
+func saveProposals(dir string, proposals []domain.Proposal) error {
+	data, err := yaml.Marshal(proposalQueue{Proposals: proposals})
+	if err != nil {
+		return fmt.Errorf("marshaling proposals: %w", err)
+	}
+
+	path := filepath.Join(dir, ".kamaji", "proposals.yaml")
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("writing proposals file: %w", err)
+	}
+
+	return nil
+}
+
+// AddProposal queues p as pending under the next free id and returns it,
+// failing if the ticket already has a pending proposal with the same
+// description. Uses file locking to prevent concurrent write races.
+func AddProposal(dir string, p domain.Proposal) (domain.Proposal, error) {
+	unlock, err := acquireProposalsLock(dir)
+	if err != nil {
+		return domain.Proposal{}, err
+	}
+	defer unlock()
+
+	proposals, err := LoadProposals(dir)
+	if err != nil {
+		return domain.Proposal{}, err
+	}
+
+	p.ID = 1
+	for _, existing := range proposals {
+		if existing.Status == domain.ProposalPending && existing.Ticket == p.Ticket && existing.Description == p.Description {
+			return domain.Proposal{}, fmt.Errorf("already proposed as #%d", existing.ID)
+		}
+		p.ID = max(p.ID, existing.ID+1)
+	}
+	p.Status = domain.ProposalPending
+	p.Time = time.Now().UTC()
+
+	if err := saveProposals(dir, append(proposals, p)); err != nil {
+		return domain.Proposal{}, err
+	}
+	return p, nil
+}
+
+// PendingProposal returns the proposal with the given id, failing unless it
+// is still pending.
+func PendingProposal(dir string, id int) (domain.Proposal, error) {
+	proposals, err := LoadProposals(dir)
+	if err != nil {
+		return domain.Proposal{}, err
+	}
+	return findPending(proposals, id)
+}
+
+// ResolveProposal sets a pending proposal's status to accepted or rejected.
+// Uses file locking to prevent concurrent write races.
+func ResolveProposal(dir string, id int, status string) error {
+	unlock, err := acquireProposalsLock(dir)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	proposals, err := LoadProposals(dir)
+	if err != nil {
+		return err
+	}
+	if _, err := findPending(proposals, id); err != nil {
+		return err
+	}
+
+	for i := range proposals {
+		if proposals[i].ID == id {
+			proposals[i].Status = status
+		}
+	}
+	return saveProposals(dir, proposals)
+}
+
+func findPending(proposals []domain.Proposal, id int) (domain.Proposal, error) {
+	for _, p := range proposals {
+		if p.ID != id {
+			continue
+		}
+		if p.Status != domain.ProposalPending {
+			return domain.Proposal{}, fmt.Errorf("proposal %d is already %s", id, p.Status)
+		}
+		return p, nil
+	}
+	return domain.Proposal{}, fmt.Errorf("unknown proposal: %d", id)
+}
+
+func acquireProposalsLock(dir string) (func(), error) {
+	kamajiDir := filepath.Join(dir, ".kamaji")
+	if err := os.MkdirAll(kamajiDir, 0o750); err != nil {
+		return nil, fmt.Errorf("creating .kamaji directory: %w", err)
+	}
+	return acquireFileLock(filepath.Join(kamajiDir, "proposals.lock"), "proposals lock")
+}
@@ -0,0 +1,84 @@
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sqve/kamaji/internal/domain"
+)
+
+func TestAddProposal_AssignsIDs(t *testing.T) {
+	dir := t.TempDir()
+
+	first, err := AddProposal(dir, domain.Proposal{Ticket: "login", Description: "Add rate limiting", Reason: "No throttling"})
+	if err != nil {
+		t.Fatalf("AddProposal error: %v", err)
+	}
+	second, err := AddProposal(dir, domain.Proposal{Ticket: "login", Description: "Log failures", Reason: "Audit"})
+	if err != nil {
+		t.Fatalf("AddProposal error: %v", err)
+	}
+	if first.ID != 1 || second.ID != 2 {
+		t.Errorf("IDs: got %d and %d, want 1 and 2", first.ID, second.ID)
+	}
+	if first.Status != domain.ProposalPending || first.Time.IsZero() {
+		t.Errorf("proposal: got status %q at %v, want pending with a time", first.Status, first.Time)
+	}
+
+	proposals, err := LoadProposals(dir)
+	if err != nil {
+		t.Fatalf("LoadProposals error: %v", err)
+	}
+	if len(proposals) != 2 || proposals[1].Description != "Log failures" {
+		t.Errorf("LoadProposals: got %+v", proposals)
+	}
+}
+
+func TestAddProposal_RejectsPendingDuplicate(t *testing.T) {
+	dir := t.TempDir()
+	p := domain.Proposal{Ticket: "login", Description: "Add rate limiting", Reason: "No throttling"}
+
+	if _, err := AddProposal(dir, p); err != nil {
+		t.Fatalf("AddProposal error: %v", err)
+	}
+	if _, err := AddProposal(dir, p); err == nil || !strings.Contains(err.Error(), "already proposed as #1") {
+		t.Errorf("AddProposal duplicate error = %v, want already proposed", err)
+	}
+
+	if err := ResolveProposal(dir, 1, domain.ProposalRejected); err != nil {
+		t.Fatalf("ResolveProposal error: %v", err)
+	}
+	if again, err := AddProposal(dir, p); err != nil || again.ID != 2 {
+		t.Errorf("AddProposal after rejection = #%d, %v, want #2", again.ID, err)
+	}
+}
+
+func TestResolveProposal(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := AddProposal(dir, domain.Proposal{Ticket: "login", Description: "Add rate limiting"}); err != nil {
+		t.Fatalf("AddProposal error: %v", err)
+	}
+
+	if err := ResolveProposal(dir, 1, domain.ProposalAccepted); err != nil {
+		t.Fatalf("ResolveProposal error: %v", err)
+	}
+	if _, err := PendingProposal(dir, 1); err == nil || !strings.Contains(err.Error(), "already accepted") {
+		t.Errorf("PendingProposal error = %v, want already accepted", err)
+	}
+	if err := ResolveProposal(dir, 1, domain.ProposalRejected); err == nil {
+		t.Error("ResolveProposal on an accepted proposal: want error")
+	}
+	if err := ResolveProposal(dir, 7, domain.ProposalRejected); err == nil || !strings.Contains(err.Error(), "unknown proposal: 7") {
+		t.Errorf("ResolveProposal error = %v, want unknown proposal", err)
+	}
+}
+
+func TestLoadProposals_MissingFile(t *testing.T) {
+	proposals, err := LoadProposals(t.TempDir())
+	if err != nil {
+		t.Fatalf("LoadProposals error: %v", err)
+	}
+	if len(proposals) != 0 {
+		t.Errorf("LoadProposals: got %d proposals, want 0", len(proposals))
+	}
+}
@@ -1,8 +1,12 @@
 package config
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
 	"os"
+	"slices"
+	"strings"
 
 	"github.com/sqve/kamaji/internal/domain"
 	"gopkg.in/yaml.v3"
@@ -44,5 +48,229 @@ func validateSprint(s *domain.Sprint) error {
 		}
 	}
 
+	if errs := validateDependencies(s); len(errs) > 0 {
+		return fmt.Errorf("%s: %s", errs[0].Field, errs[0].Message)
+	}
+	// Progress is recorded by key, so duplicates would share completion.
+	if errs := validateKeys(s); len(errs) > 0 {
+		return fmt.Errorf("%s: %s", errs[0].Field, errs[0].Message)
+	}
+	if errs := validateVerifiable(s); len(errs) > 0 {
+		return fmt.Errorf("%s: %s", errs[0].Field, errs[0].Message)
+	}
+
+	return nil
+}
+
+// AppendTask adds task to the end of the named ticket's tasks in the sprint
+// file at path. Only the description, steps and verify criteria are written.
+// The task's lines are inserted after the ticket's last task, so the rest of
+// the file keeps its comments, blank lines and formatting. The edited sprint
+// must still be valid.
+func AppendTask(path, ticketName string, task domain.Task) error {
+	data, err := os.ReadFile(path) // #nosec G304 -- user-provided config path is intentional
+	if err != nil {
+		return fmt.Errorf("reading sprint file: %w", err)
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("parsing sprint YAML: %w", err)
+	}
+	if len(doc.Content) == 0 {
+		return errors.New("sprint file is empty")
+	}
+
+	tickets := mappingValue(doc.Content[0], "tickets")
+	if tickets == nil || tickets.Kind != yaml.SequenceNode {
+		return errors.New("sprint file has no tickets")
+	}
+	var ticket *yaml.Node
+	for _, t := range tickets.Content {
+		if name := mappingValue(t, "name"); name != nil && name.Value == ticketName {
+			ticket = t
+			break
+		}
+	}
+	if ticket == nil {
+		return fmt.Errorf("unknown ticket: %s", ticketName)
+	}
+
+	indent := indentOf(data)
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(indent)
+	if err := enc.Encode(struct {
+		Description string   `yaml:"description"`
+		Steps       []string `yaml:"steps,omitempty"`
+		Verify      string   `yaml:"verify,omitempty"`
+	}{task.Description, task.Steps, task.Verify}); err != nil {
+		return fmt.Errorf("encoding task: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return fmt.Errorf("encoding task: %w", err)
+	}
+	body := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+
+	lines := strings.SplitAfter(string(data), "\n")
+	var at int
+	var insert []string
+	key, tasks := mappingPair(ticket, "tasks")
+	switch {
+	case tasks == nil:
+		keyCol := ticket.Column - 1
+		dashCol := keyCol + taskListOffset(tickets, indent)
+		at = itemEnd(lines, ticket.Line, tickets.Column-1)
+		insert = append([]string{strings.Repeat(" ", keyCol) + "tasks:"}, listItem(body, dashCol, dashCol+2)...)
+	case tasks.Kind == yaml.SequenceNode && tasks.Style&yaml.FlowStyle == 0:
+		last := tasks.Content[len(tasks.Content)-1]
+		at = itemEnd(lines, last.Line, tasks.Column-1)
+		insert = listItem(body, tasks.Column-1, last.Column-1)
+	case len(tasks.Content) == 0 && tasks.Line == key.Line:
+		// An empty "tasks:" or "tasks: []" becomes a block list.
+		line := lines[key.Line-1]
+		end := min(tasks.Column-1, len(strings.TrimRight(line, "\r\n")))
+		lines[key.Line-1] = strings.TrimRight(line[:end], " ") + line[len(strings.TrimRight(line, "\r\n")):]
+		dashCol := key.Column - 1 + taskListOffset(tickets, indent)
+		at = key.Line
+		insert = listItem(body, dashCol, dashCol+2)
+	default:
+		return fmt.Errorf("tasks of ticket %s are not a block list; add the task by hand", ticketName)
+	}
+
+	newline := "\n"
+	if bytes.Contains(data, []byte("\r\n")) {
+		newline = "\r\n"
+	}
+	if at > 0 && !strings.HasSuffix(lines[at-1], "\n") {
+		lines[at-1] += newline
+	}
+	var out strings.Builder
+	out.WriteString(strings.Join(lines[:at], ""))
+	for _, line := range insert {
+		out.WriteString(line + newline)
+	}
+	out.WriteString(strings.Join(lines[at:], ""))
+	edited := []byte(out.String())
+
+	var sprint domain.Sprint
+	if err := yaml.Unmarshal(edited, &sprint); err != nil {
+		return fmt.Errorf("parsing sprint YAML: %w", err)
+	}
+	if err := validateSprint(&sprint); err != nil {
+		return err
+	}
+	if !endsWithTask(&sprint, ticketName, task) {
+		return fmt.Errorf("could not add the task to %s in kamaji.yaml; add it by hand", ticketName)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("writing sprint file: %w", err)
+	}
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, edited, info.Mode().Perm()); err != nil {
+		return fmt.Errorf("writing sprint file: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("writing sprint file: %w", err)
+	}
+
 	return nil
 }
+
+// itemEnd returns the index of the line after the list item that starts on
+// line start (1-based) with its dash at column dashCol (0-based). The item
+// ends before the first line indented no deeper than the dash, and trailing
+// blank lines are left outside it.
+func itemEnd(lines []string, start, dashCol int) int {
+	end := start
+	for i := start; i < len(lines); i++ {
+		line := strings.TrimRight(lines[i], "\r\n")
+		trimmed := strings.TrimLeft(line, " ")
+		if trimmed == "" {
+			continue
+		}
+		if len(line)-len(trimmed) <= dashCol {
+			break
+		}
+		end = i + 1
+	}
+	return end
+}
+
+// listItem indents body as a list item with its dash at column dashCol and
+// its keys at column keyCol.
+func listItem(body []string, dashCol, keyCol int) []string {
+	gap := max(keyCol-dashCol-1, 1)
+	item := make([]string, len(body))
+	for i, line := range body {
+		switch {
+		case i == 0:
+			item[i] = strings.Repeat(" ", dashCol) + "-" + strings.Repeat(" ", gap) + line
+		case line != "":
+			item[i] = strings.Repeat(" ", dashCol+1+gap) + line
+		}
+	}
+	return item
+}
+
+// taskListOffset returns how far the first ticket with a task list indents
+// its dashes past the ticket's keys, defaulting to indent.
+func taskListOffset(tickets *yaml.Node, indent int) int {
+	for _, t := range tickets.Content {
+		if tasks := mappingValue(t, "tasks"); tasks != nil && tasks.Kind == yaml.SequenceNode &&
+			tasks.Style&yaml.FlowStyle == 0 && len(tasks.Content) > 0 {
+			return tasks.Column - t.Column
+		}
+	}
+	return indent
+}
+
+// endsWithTask reports whether the named ticket's last task is task.
+func endsWithTask(s *domain.Sprint, ticketName string, task domain.Task) bool {
+	for _, t := range s.Tickets {
+		if t.Name != ticketName {
+			continue
+		}
+		if len(t.Tasks) == 0 {
+			return false
+		}
+		last := t.Tasks[len(t.Tasks)-1]
+		return last.Description == task.Description && last.Verify == task.Verify && slices.Equal(last.Steps, task.Steps)
+	}
+	return false
+}
+
+// mappingPair returns the key and value nodes for key in a mapping node, or
+// nils.
+func mappingPair(node *yaml.Node, key string) (*yaml.Node, *yaml.Node) {
+	if node.Kind != yaml.MappingNode {
+		return nil, nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i], node.Content[i+1]
+		}
+	}
+	return nil, nil
+}
+
+// mappingValue returns the value node for key in a mapping node, or nil.
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	_, value := mappingPair(node, key)
+	return value
+}
+
+// indentOf returns the indentation of the first indented line in data,
+// defaulting to 4 spaces.
+func indentOf(data []byte) int {
+	for _, line := range strings.Split(string(data), "\n") {
+		trimmed := strings.TrimLeft(line, " ")
+		if n := len(line) - len(trimmed); n > 0 && trimmed != "" && !strings.HasPrefix(trimmed, "#") {
+			return n
+		}
+	}
+	return 4
+}
@@ -5,6 +5,8 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/sqve/kamaji/internal/domain"
 )
 
 func TestLoadSprint_Valid(t *testing.T) {
@@ -139,3 +141,258 @@ tickets:
 		t.Errorf("error should mention 'ticket[0].task[0]', got: %v", err)
 	}
 }
+
+func TestLoadSprint_ValidationError_DependencyCycle(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "kamaji.yaml")
+
+	content := `name: "Test Sprint"
+tickets:
+  - name: a
+    depends_on: [b]
+  - name: b
+    depends_on: [a]
+`
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+
+	_, err := LoadSprint(path)
+	if err == nil {
+		t.Fatal("expected error for dependency cycle")
+	}
+	if !strings.Contains(err.Error(), "dependency cycle") {
+		t.Errorf("error should mention dependency cycle: %v", err)
+	}
+}
+
+func TestAppendTask_KeepsComments(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "kamaji.yaml")
+
+	content := `# Auth sprint
+name: test
+tickets:
+    - name: login
+      branch: feat/login # reviewed by Ana
+      tasks:
+        - description: Add form
+    - name: docs
+      tasks: []
+`
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+
+	task := domain.Task{Description: "Add rate limiting", Steps: []string{"Limit by IP"}, Verify: "Returns 429"}
+	if err := AppendTask(path, "login", task); err != nil {
+		t.Fatalf("AppendTask error: %v", err)
+	}
+	if err := AppendTask(path, "docs", domain.Task{Description: "Update guide"}); err != nil {
+		t.Fatalf("AppendTask error: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read file: %v", err)
+	}
+	want := `# Auth sprint
+name: test
+tickets:
+    - name: login
+      branch: feat/login # reviewed by Ana
+      tasks:
+        - description: Add form
+        - description: Add rate limiting
+          steps:
+              - Limit by IP
+          verify: Returns 429
+    - name: docs
+      tasks:
+        - description: Update guide
+`
+	if string(data) != want {
+		t.Errorf("sprint file:\ngot:\n%s\nwant:\n%s", data, want)
+	}
+}
+
+func TestAppendTask_KeepsFormatting(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "kamaji.yaml")
+
+	content := `name: test
+
+# Login work
+tickets:
+  - name: login
+    branch: feat/login
+    tasks:
+      - description: Add form
+        steps:
+          - Render fields
+
+      - description: |
+          Validate input
+
+          on submit
+        verify: "Errors show"   # quoted on purpose
+
+  # Docs come last
+  - name: docs
+    branch: docs
+
+  - name: cleanup
+    tasks: []
+`
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+
+	for _, ticket := range []string{"login", "docs", "cleanup"} {
+		if err := AppendTask(path, ticket, domain.Task{Description: "Review " + ticket, Steps: []string{"Read it"}}); err != nil {
+			t.Fatalf("AppendTask(%s) error: %v", ticket, err)
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read file: %v", err)
+	}
+	want := `name: test
+
+# Login work
+tickets:
+  - name: login
+    branch: feat/login
+    tasks:
+      - description: Add form
+        steps:
+          - Render fields
+
+      - description: |
+          Validate input
+
+          on submit
+        verify: "Errors show"   # quoted on purpose
+      - description: Review login
+        steps:
+          - Read it
+
+  # Docs come last
+  - name: docs
+    branch: docs
+    tasks:
+      - description: Review docs
+        steps:
+          - Read it
+
+  - name: cleanup
+    tasks:
+      - description: Review cleanup
+        steps:
+          - Read it
+`
+	if string(data) != want {
+		t.Errorf("sprint file:\ngot:\n%s\nwant:\n%s", data, want)
+	}
+
+	sprint, err := LoadSprint(path)
+	if err != nil {
+		t.Fatalf("LoadSprint error: %v", err)
+	}
+	if got := sprint.Tickets[0].Tasks[1].Description; got != "Validate input\n\non submit\n" {
+		t.Errorf("existing task description = %q, want it unchanged", got)
+	}
+}
+
+func TestAppendTask_Errors(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "kamaji.yaml")
+
+	content := `name: test
+tickets:
+  - name: login
+    tasks:
+      - description: Add form
+`
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		ticket  string
+		task    domain.Task
+		wantErr string
+	}{
+		{"unknown ticket", "signup", domain.Task{Description: "Add form"}, "unknown ticket: signup"},
+		{"duplicate task", "login", domain.Task{Description: "Add form"}, "duplicate task description"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := AppendTask(path, tt.ticket, tt.task)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("AppendTask error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read file: %v", err)
+	}
+	if string(data) != content {
+		t.Errorf("sprint file changed after a failed append:\n%s", data)
+	}
+}
+
+func TestLoadSprint_ValidationError_DuplicateTaskKey(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "kamaji.yaml")
+
+	content := `name: "Test Sprint"
+tickets:
+  - name: ticket-1
+    tasks:
+      - description: Add form
+      - description: Add form
+`
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+
+	_, err := LoadSprint(path)
+	if err == nil {
+		t.Fatal("expected error for duplicate task description")
+	}
+	if !strings.Contains(err.Error(), "tickets[0].tasks[1].description") {
+		t.Errorf("error should mention 'tickets[0].tasks[1].description', got: %v", err)
+	}
+}
+
+func TestLoadSprint_ValidationError_AiderWithoutVerifyCmd(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "kamaji.yaml")
+
+	content := `name: "Test Sprint"
+agent: aider
+tickets:
+  - name: ticket-1
+    tasks:
+      - description: Add form
+        verify_cmd: make test
+      - description: Style form
+`
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+
+	_, err := LoadSprint(path)
+	if err == nil {
+		t.Fatal("expected error for aider task without verify_cmd")
+	}
+	if !strings.Contains(err.Error(), "tickets[0].tasks[1].verify_cmd") {
+		t.Errorf("error should mention 'tickets[0].tasks[1].verify_cmd', got: %v", err)
+	}
+}
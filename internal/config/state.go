@@ -32,7 +32,8 @@ func LoadState(dir string) (*domain.State, error) {
 }
 
 // SaveState writes the state to .kamaji/state.yaml in the given directory.
-// Creates the .kamaji directory if it doesn't exist.
+// Creates the .kamaji directory if it doesn't exist. The file is replaced
+// atomically so a crash never leaves a partially written state.
 func SaveState(dir string, state *domain.State) error {
 	kamajiDir := filepath.Join(dir, ".kamaji")
 	if err := os.MkdirAll(kamajiDir, 0o750); err != nil {
@@ -45,7 +46,12 @@ func SaveState(dir string, state *domain.State) error {
 	}
 
 	path := filepath.Join(kamajiDir, "state.yaml")
-	if err := os.WriteFile(path, data, 0o600); err != nil {
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0o600); err != nil {
+		return fmt.Errorf("writing state file: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		_ = os.Remove(tmpPath)
 		return fmt.Errorf("writing state file: %w", err)
 	}
 
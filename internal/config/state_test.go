@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 
@@ -16,9 +17,12 @@ func TestLoadState_Valid(t *testing.T) {
 		t.Fatalf("mkdir: %v", err)
 	}
 
-	content := `current_ticket: 2
-current_task: 5
-failure_count: 1
+	content := `tickets:
+  login-form:
+    done:
+      - Create form
+    failing_task: Add tests
+    failure_count: 1
 `
 	path := filepath.Join(kamajiDir, "state.yaml")
 	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
@@ -30,14 +34,12 @@ failure_count: 1
 		t.Fatalf("LoadState error: %v", err)
 	}
 
-	if state.CurrentTicket != 2 {
-		t.Errorf("CurrentTicket: got %d, want 2", state.CurrentTicket)
+	got := state.Tickets["login-form"]
+	if len(got.Done) != 1 || got.Done[0] != "Create form" {
+		t.Errorf("Done: got %q, want [Create form]", got.Done)
 	}
-	if state.CurrentTask != 5 {
-		t.Errorf("CurrentTask: got %d, want 5", state.CurrentTask)
-	}
-	if state.FailureCount != 1 {
-		t.Errorf("FailureCount: got %d, want 1", state.FailureCount)
+	if got.FailureCount != 1 {
+		t.Errorf("FailureCount: got %d, want 1", got.FailureCount)
 	}
 }
 
@@ -49,14 +51,8 @@ func TestLoadState_MissingFile_ReturnsZeroValue(t *testing.T) {
 		t.Fatalf("LoadState should not error for missing file: %v", err)
 	}
 
-	if state.CurrentTicket != 0 {
-		t.Errorf("CurrentTicket: got %d, want 0", state.CurrentTicket)
-	}
-	if state.CurrentTask != 0 {
-		t.Errorf("CurrentTask: got %d, want 0", state.CurrentTask)
-	}
-	if state.FailureCount != 0 {
-		t.Errorf("FailureCount: got %d, want 0", state.FailureCount)
+	if len(state.Tickets) != 0 {
+		t.Errorf("Tickets: got %v, want empty", state.Tickets)
 	}
 }
 
@@ -67,7 +63,7 @@ func TestLoadState_InvalidYAML(t *testing.T) {
 		t.Fatalf("mkdir: %v", err)
 	}
 
-	content := `current_ticket: 2
+	content := `tickets: 2
   invalid: yaml: syntax
 `
 	path := filepath.Join(kamajiDir, "state.yaml")
@@ -84,11 +80,9 @@ func TestLoadState_InvalidYAML(t *testing.T) {
 func TestSaveState_CreatesDirectory(t *testing.T) {
 	dir := t.TempDir()
 
-	state := &domain.State{
-		CurrentTicket: 1,
-		CurrentTask:   2,
-		FailureCount:  0,
-	}
+	state := &domain.State{Tickets: map[string]domain.TicketState{
+		"login-form": {Done: []string{"Create form"}},
+	}}
 
 	if err := SaveState(dir, state); err != nil {
 		t.Fatalf("SaveState error: %v", err)
@@ -110,19 +104,23 @@ func TestSaveState_CreatesDirectory(t *testing.T) {
 	if err != nil {
 		t.Fatalf("read state.yaml: %v", err)
 	}
-	if !strings.Contains(string(data), "current_ticket: 1") {
-		t.Errorf("state.yaml should contain 'current_ticket: 1', got: %s", data)
+	if !strings.Contains(string(data), "- Create form") {
+		t.Errorf("state.yaml should contain '- Create form', got: %s", data)
+	}
+
+	// No temp file left behind after atomic write
+	if _, err := os.Stat(path + ".tmp"); !os.IsNotExist(err) {
+		t.Error("state.yaml.tmp should not remain after SaveState")
 	}
 }
 
 func TestSaveState_Roundtrip(t *testing.T) {
 	dir := t.TempDir()
 
-	original := &domain.State{
-		CurrentTicket: 3,
-		CurrentTask:   7,
-		FailureCount:  2,
-	}
+	original := &domain.State{Tickets: map[string]domain.TicketState{
+		"login-form": {Done: []string{"Create form", "Add tests"}, FailingTask: "Add OAuth", FailureCount: 2},
+		"dashboard":  {FailingTask: "Add layout", FailureCount: 1},
+	}}
 
 	if err := SaveState(dir, original); err != nil {
 		t.Fatalf("SaveState error: %v", err)
@@ -133,13 +131,12 @@ func TestSaveState_Roundtrip(t *testing.T) {
 		t.Fatalf("LoadState error: %v", err)
 	}
 
-	if loaded.CurrentTicket != original.CurrentTicket {
-		t.Errorf("CurrentTicket: got %d, want %d", loaded.CurrentTicket, original.CurrentTicket)
-	}
-	if loaded.CurrentTask != original.CurrentTask {
-		t.Errorf("CurrentTask: got %d, want %d", loaded.CurrentTask, original.CurrentTask)
+	if len(loaded.Tickets) != len(original.Tickets) {
+		t.Fatalf("Tickets length: got %d, want %d", len(loaded.Tickets), len(original.Tickets))
 	}
-	if loaded.FailureCount != original.FailureCount {
-		t.Errorf("FailureCount: got %d, want %d", loaded.FailureCount, original.FailureCount)
+	for name, want := range original.Tickets {
+		if got := loaded.Tickets[name]; !reflect.DeepEqual(got, want) {
+			t.Errorf("Tickets[%q]: got %+v, want %+v", name, got, want)
+		}
 	}
 }
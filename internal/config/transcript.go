@@ -0,0 +1,111 @@
+package config
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/sqve/kamaji/internal/domain"
+)
+
+// maxTranscriptName bounds the task part of a transcript file name, since task
+// descriptions can be long sentences.
+const maxTranscriptName = 80
+
+// Transcript appends the events of one agent attempt to its own JSONL file.
+type Transcript struct {
+	Path    string
+	Attempt int
+	f       *os.File
+	w       *bufio.Writer
+}
+
+// TranscriptDir returns the directory holding a ticket's transcripts.
+func TranscriptDir(dir, ticketName string) string {
+	return filepath.Join(dir, ".kamaji", "transcripts", sanitizeFilename(ticketName))
+}
+
+// CreateTranscript opens .kamaji/transcripts/<ticket>/<task>-<attempt>.jsonl
+// for the next attempt at the task. Attempts are numbered from 1 and never
+// reuse the number of an earlier transcript.
+func CreateTranscript(dir, ticketName, taskKey string) (*Transcript, error) {
+	ticketDir := TranscriptDir(dir, ticketName)
+	if err := os.MkdirAll(ticketDir, 0o750); err != nil {
+		return nil, fmt.Errorf("creating transcript directory: %w", err)
+	}
+
+	base := transcriptName(taskKey)
+	for attempt := 1; ; attempt++ {
+		path := filepath.Join(ticketDir, base+"-"+strconv.Itoa(attempt)+".jsonl")
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600) // #nosec G304 -- path derived from user working directory
+		if errors.Is(err, os.ErrExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("creating transcript: %w", err)
+		}
+		return &Transcript{Path: path, Attempt: attempt, f: f, w: bufio.NewWriter(f)}, nil
+	}
+}
+
+// Write appends one event as a JSON line.
+func (t *Transcript) Write(event domain.TranscriptEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("marshaling transcript event: %w", err)
+	}
+	if _, err := t.w.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("writing transcript: %w", err)
+	}
+	return nil
+}
+
+// Close flushes buffered events and closes the file.
+func (t *Transcript) Close() error {
+	ferr := t.w.Flush()
+	cerr := t.f.Close()
+	if err := errors.Join(ferr, cerr); err != nil {
+		return fmt.Errorf("closing transcript: %w", err)
+	}
+	return nil
+}
+
+// LoadTranscript reads the events of a transcript file.
+func LoadTranscript(path string) ([]domain.TranscriptEvent, error) {
+	f, err := os.Open(path) // #nosec G304 -- path derived from user working directory
+	if err != nil {
+		return nil, fmt.Errorf("reading transcript: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	var events []domain.TranscriptEvent
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxEventLine)
+	for line := 1; scanner.Scan(); line++ {
+		var event domain.TranscriptEvent
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			return nil, fmt.Errorf("parsing transcript line %d: %w", line, err)
+		}
+		events = append(events, event)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading transcript: %w", err)
+	}
+	return events, nil
+}
+
+// transcriptName turns a task key into a file name stem.
+func transcriptName(taskKey string) string {
+	name := []rune(sanitizeFilename(taskKey))
+	if len(name) > maxTranscriptName {
+		name = name[:maxTranscriptName]
+	}
+	if len(name) == 0 {
+		return "task"
+	}
+	return string(name)
+}
@@ -0,0 +1,84 @@
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sqve/kamaji/internal/domain"
+)
+
+func TestCreateTranscript_NumbersAttempts(t *testing.T) {
+	dir := t.TempDir()
+
+	for want := 1; want <= 2; want++ {
+		tr, err := CreateTranscript(dir, "feat/login", "Add form")
+		if err != nil {
+			t.Fatalf("CreateTranscript error: %v", err)
+		}
+		if tr.Attempt != want {
+			t.Errorf("Attempt: got %d, want %d", tr.Attempt, want)
+		}
+		wantPath := filepath.Join(dir, ".kamaji", "transcripts", "feat-login", fmt.Sprintf("Add form-%d.jsonl", want))
+		if tr.Path != wantPath {
+			t.Errorf("Path: got %q, want %q", tr.Path, wantPath)
+		}
+		if err := tr.Close(); err != nil {
+			t.Fatalf("Close error: %v", err)
+		}
+	}
+}
+
+func TestTranscript_RoundTrips(t *testing.T) {
+	dir := t.TempDir()
+
+	tr, err := CreateTranscript(dir, "login", "form")
+	if err != nil {
+		t.Fatalf("CreateTranscript error: %v", err)
+	}
+	events := []domain.TranscriptEvent{
+		{Kind: domain.TranscriptToolUse, Tool: "Bash", Input: []byte(`{"command":"ls"}`)},
+		{Kind: domain.TranscriptResult, Text: "done", NumTurns: 2},
+	}
+	for _, e := range events {
+		if err := tr.Write(e); err != nil {
+			t.Fatalf("Write error: %v", err)
+		}
+	}
+	if err := tr.Close(); err != nil {
+		t.Fatalf("Close error: %v", err)
+	}
+
+	loaded, err := LoadTranscript(tr.Path)
+	if err != nil {
+		t.Fatalf("LoadTranscript error: %v", err)
+	}
+	if len(loaded) != 2 {
+		t.Fatalf("events: got %d, want 2", len(loaded))
+	}
+	if string(loaded[0].Input) != `{"command":"ls"}` {
+		t.Errorf("Input: got %s", loaded[0].Input)
+	}
+	if loaded[1].NumTurns != 2 {
+		t.Errorf("NumTurns: got %d, want 2", loaded[1].NumTurns)
+	}
+}
+
+func TestTranscriptName(t *testing.T) {
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{"setup", "setup"},
+		{"a/b:c", "a-b-c"},
+		{"", "task"},
+		{strings.Repeat("x", 100), strings.Repeat("x", maxTranscriptName)},
+	}
+
+	for _, tt := range tests {
+		if got := transcriptName(tt.key); got != tt.want {
+			t.Errorf("transcriptName(%q) = %q, want %q", tt.key, got, tt.want)
+		}
+	}
+}
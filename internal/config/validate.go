@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"regexp"
 	"strings"
 
 	"github.com/sqve/kamaji/internal/domain"
@@ -18,11 +19,18 @@ func ValidateSprint(s *domain.Sprint) []ValidationError {
 	var errors []ValidationError
 
 	errors = validateRequired("name", s.Name, errors)
+	errors = validateRules("rules", s.Rules, errors)
+	errors = validateRetry("", s.MaxAttempts, s.RetryBackoff, errors)
+	errors = validateModel("", s.Model, s.Effort, s.Escalation, errors)
+	errors = validateAgent(s.Agent, errors)
 
 	for i, ticket := range s.Tickets {
 		ticketPrefix := fmt.Sprintf("tickets[%d]", i)
 		errors = validateRequired(ticketPrefix+".name", ticket.Name, errors)
 		errors = validateNotEmpty(ticketPrefix+".description", ticket.Description, errors)
+		errors = validateRules(ticketPrefix+".rules", ticket.Rules, errors)
+		errors = validateRetry(ticketPrefix+".", ticket.MaxAttempts, ticket.RetryBackoff, errors)
+		errors = validateModel(ticketPrefix+".", ticket.Model, ticket.Effort, ticket.Escalation, errors)
 
 		for j, task := range ticket.Tasks {
 			taskField := fmt.Sprintf("%s.tasks[%d].description", ticketPrefix, j)
@@ -30,9 +38,301 @@ func ValidateSprint(s *domain.Sprint) []ValidationError {
 			if task.Description != "" {
 				errors = validateNotEmpty(taskField, task.Description, errors)
 			}
+			errors = validateDone(fmt.Sprintf("%s.tasks[%d]", ticketPrefix, j), task, errors)
+			errors = validateRules(fmt.Sprintf("%s.tasks[%d].rules", ticketPrefix, j), task.Rules, errors)
+			errors = validateRetry(fmt.Sprintf("%s.tasks[%d].", ticketPrefix, j), task.MaxAttempts, task.RetryBackoff, errors)
+			errors = validateModel(fmt.Sprintf("%s.tasks[%d].", ticketPrefix, j), task.Model, task.Effort, task.Escalation, errors)
 		}
 	}
 
+	errors = append(errors, validateDependencies(s)...)
+	errors = append(errors, validateKeys(s)...)
+	errors = append(errors, validateVerifiable(s)...)
+
+	return errors
+}
+
+// validateVerifiable checks that every task has a verify_cmd when the agent
+// cannot report its own result. Aider has no MCP client, so its sessions pass
+// on exit status alone and verify_cmd is the only check of the work.
+func validateVerifiable(s *domain.Sprint) []ValidationError {
+	if s.Agent.Kind() != domain.AgentAider {
+		return nil
+	}
+	var errors []ValidationError
+	for i, ticket := range s.Tickets {
+		for j, task := range ticket.Tasks {
+			if task.VerifyCmd == "" {
+				errors = append(errors, ValidationError{
+					Field:   fmt.Sprintf("tickets[%d].tasks[%d].verify_cmd", i, j),
+					Message: "required with agent aider, which cannot report task_complete",
+				})
+			}
+		}
+	}
+	return errors
+}
+
+// validateKeys checks that ticket and task keys are unique. Keys record
+// progress in the runtime state, so duplicates would share completion. A key
+// is the id when set, otherwise the ticket name or task description; duplicate
+// ticket names are reported by validateDependencies.
+func validateKeys(s *domain.Sprint) []ValidationError {
+	var errors []ValidationError
+
+	ticketIDs := make(map[string]bool)
+	ticketKeys := make(map[string]bool)
+	for i := range s.Tickets {
+		ticket := &s.Tickets[i]
+		prefix := fmt.Sprintf("tickets[%d]", i)
+		key := ticket.Key()
+		switch {
+		case key == "" || !ticketKeys[key]:
+		case ticket.ID != "":
+			errors = append(errors, ValidationError{Field: prefix + ".id", Message: fmt.Sprintf("duplicate ticket id %q", key)})
+		case ticketIDs[key]:
+			errors = append(errors, ValidationError{Field: prefix + ".name", Message: fmt.Sprintf("ticket name %q is already used as an id", key)})
+		}
+		ticketKeys[key] = true
+		ticketIDs[key] = ticketIDs[key] || ticket.ID != ""
+
+		taskIDs := make(map[string]bool)
+		taskKeys := make(map[string]bool)
+		for j := range ticket.Tasks {
+			task := &ticket.Tasks[j]
+			taskPrefix := fmt.Sprintf("%s.tasks[%d]", prefix, j)
+			key := task.Key()
+			switch {
+			case key == "" || !taskKeys[key]:
+			case task.ID != "":
+				errors = append(errors, ValidationError{Field: taskPrefix + ".id", Message: fmt.Sprintf("duplicate task id %q", key)})
+			case taskIDs[key]:
+				errors = append(errors, ValidationError{Field: taskPrefix + ".description", Message: fmt.Sprintf("task description %q is already used as an id", key)})
+			default:
+				errors = append(errors, ValidationError{
+					Field:   taskPrefix + ".description",
+					Message: fmt.Sprintf("duplicate task description %q; set id to tell the tasks apart", key),
+				})
+			}
+			taskKeys[key] = true
+			taskIDs[key] = taskIDs[key] || task.ID != ""
+		}
+	}
+
+	return errors
+}
+
+// validateDependencies checks ticket name uniqueness and that depends_on forms
+// a DAG of known tickets. depends_on refers to tickets by name, so duplicates
+// would be ambiguous.
+func validateDependencies(s *domain.Sprint) []ValidationError {
+	var errors []ValidationError
+
+	seen := make(map[string]bool, len(s.Tickets))
+	for i, ticket := range s.Tickets {
+		if ticket.Name == "" {
+			continue
+		}
+		if seen[ticket.Name] {
+			errors = append(errors, ValidationError{
+				Field:   fmt.Sprintf("tickets[%d].name", i),
+				Message: fmt.Sprintf("duplicate ticket name %q", ticket.Name),
+			})
+		}
+		seen[ticket.Name] = true
+	}
+
+	for i, ticket := range s.Tickets {
+		for j, dep := range ticket.DependsOn {
+			field := fmt.Sprintf("tickets[%d].depends_on[%d]", i, j)
+			switch {
+			case dep == ticket.Name:
+				errors = append(errors, ValidationError{Field: field, Message: "ticket cannot depend on itself"})
+			case !seen[dep]:
+				errors = append(errors, ValidationError{Field: field, Message: fmt.Sprintf("unknown ticket %q", dep)})
+			}
+		}
+	}
+
+	if cycle := findCycle(s); len(cycle) > 0 {
+		errors = append(errors, ValidationError{
+			Field:   "tickets",
+			Message: "dependency cycle: " + strings.Join(cycle, " -> "),
+		})
+	}
+
+	return errors
+}
+
+// findCycle returns the ticket names forming the first dependency cycle found,
+// or nil when depends_on is acyclic. Self and unknown dependencies are ignored.
+func findCycle(s *domain.Sprint) []string {
+	deps := make(map[string][]string, len(s.Tickets))
+	for _, ticket := range s.Tickets {
+		deps[ticket.Name] = ticket.DependsOn
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	marks := make(map[string]int, len(deps))
+	var path []string
+
+	var visit func(name string) []string
+	visit = func(name string) []string {
+		marks[name] = visiting
+		path = append(path, name)
+		for _, dep := range deps[name] {
+			if _, ok := deps[dep]; !ok || dep == name {
+				continue
+			}
+			switch marks[dep] {
+			case visiting:
+				for i, n := range path {
+					if n == dep {
+						return append(append([]string{}, path[i:]...), dep)
+					}
+				}
+			case unvisited:
+				if cycle := visit(dep); cycle != nil {
+					return cycle
+				}
+			}
+		}
+		path = path[:len(path)-1]
+		marks[name] = visited
+		return nil
+	}
+
+	for _, ticket := range s.Tickets {
+		if marks[ticket.Name] == unvisited {
+			if cycle := visit(ticket.Name); cycle != nil {
+				return cycle
+			}
+		}
+	}
+	return nil
+}
+
+func validateDone(prefix string, task domain.Task, errors []ValidationError) []ValidationError {
+	if task.VerifyTimeout != 0 && task.VerifyCmd == "" {
+		errors = append(errors, ValidationError{
+			Field:   prefix + ".verify_timeout",
+			Message: "requires verify_cmd",
+		})
+	}
+	if task.Done == nil {
+		return errors
+	}
+	if task.VerifyCmd == "" {
+		errors = append(errors, ValidationError{
+			Field:   prefix + ".done",
+			Message: "requires verify_cmd",
+		})
+	}
+	if task.Done.Output != "" {
+		if _, err := regexp.Compile(task.Done.Output); err != nil {
+			errors = append(errors, ValidationError{
+				Field:   prefix + ".done.output",
+				Message: "invalid regex: " + err.Error(),
+			})
+		}
+	}
+	return errors
+}
+
+// validateRules checks a single rule level. IDs must be unique within a level
+// so overrides are unambiguous, and removals must name the rule they drop.
+func validateRules(prefix string, rules []domain.Rule, errors []ValidationError) []ValidationError {
+	seen := make(map[string]bool, len(rules))
+	for i, rule := range rules {
+		field := fmt.Sprintf("%s[%d]", prefix, i)
+		switch {
+		case rule.Remove && rule.ID == "":
+			errors = append(errors, ValidationError{Field: field + ".id", Message: "required to remove a rule"})
+		case rule.Remove && rule.Text != "":
+			errors = append(errors, ValidationError{Field: field + ".text", Message: "not allowed when removing a rule"})
+		case !rule.Remove && strings.TrimSpace(rule.Text) == "":
+			errors = append(errors, ValidationError{Field: field + ".text", Message: "required"})
+		}
+		if rule.ID == "" {
+			continue
+		}
+		if seen[rule.ID] {
+			errors = append(errors, ValidationError{
+				Field:   field + ".id",
+				Message: fmt.Sprintf("duplicate rule id %q", rule.ID),
+			})
+		}
+		seen[rule.ID] = true
+	}
+	return errors
+}
+
+// validateAgent checks the agent name, and that only custom agents set a
+// command and prompt delivery.
+func validateAgent(a domain.Agent, errors []ValidationError) []ValidationError {
+	switch a.Kind() {
+	case domain.AgentClaude, domain.AgentCodex, domain.AgentAider:
+		if len(a.Command) > 0 {
+			errors = append(errors, ValidationError{Field: "agent.command", Message: "only allowed for custom agents"})
+		}
+		if a.Prompt != "" {
+			errors = append(errors, ValidationError{Field: "agent.prompt", Message: "only allowed for custom agents"})
+		}
+	case domain.AgentCustom:
+		if len(a.Command) == 0 || strings.TrimSpace(a.Command[0]) == "" {
+			errors = append(errors, ValidationError{Field: "agent.command", Message: "required for custom agents"})
+		}
+		switch a.Prompt {
+		case "", domain.PromptStdin, domain.PromptArg, domain.PromptFile:
+		default:
+			errors = append(errors, ValidationError{
+				Field:   "agent.prompt",
+				Message: fmt.Sprintf("unknown prompt delivery %q: want stdin, arg or file", a.Prompt),
+			})
+		}
+	default:
+		errors = append(errors, ValidationError{
+			Field:   "agent.name",
+			Message: fmt.Sprintf("unknown agent %q: want claude, codex, aider or custom", a.Name),
+		})
+	}
+	return errors
+}
+
+// validateRetry checks retry settings. Zero values inherit and are allowed.
+func validateRetry(prefix string, maxAttempts int, backoff float64, errors []ValidationError) []ValidationError {
+	if maxAttempts < 0 {
+		errors = append(errors, ValidationError{Field: prefix + "max_attempts", Message: "must be at least 1"})
+	}
+	if backoff != 0 && backoff < 1 {
+		errors = append(errors, ValidationError{Field: prefix + "retry_backoff", Message: "must be at least 1"})
+	}
+	return errors
+}
+
+// validateModel checks model settings. A level sets either model or
+// escalation, and escalation entries must name a model.
+func validateModel(prefix, model, effort string, escalation []string, errors []ValidationError) []ValidationError {
+	if model != "" && len(escalation) > 0 {
+		errors = append(errors, ValidationError{Field: prefix + "escalation", Message: "cannot be combined with model"})
+	}
+	for i, m := range escalation {
+		if strings.TrimSpace(m) == "" {
+			errors = append(errors, ValidationError{Field: fmt.Sprintf("%sescalation[%d]", prefix, i), Message: "cannot be empty"})
+		}
+	}
+	switch effort {
+	case "", domain.EffortLow, domain.EffortMedium, domain.EffortHigh:
+	default:
+		errors = append(errors, ValidationError{
+			Field:   prefix + "effort",
+			Message: fmt.Sprintf("unknown effort %q: want low, medium or high", effort),
+		})
+	}
 	return errors
 }
 
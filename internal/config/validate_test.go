@@ -2,6 +2,7 @@ package config
 
 import (
 	"testing"
+	"time"
 
 	"github.com/sqve/kamaji/internal/domain"
 )
@@ -201,3 +202,372 @@ func TestValidateSprint_MultipleErrors(t *testing.T) {
 		}
 	}
 }
+
+func TestValidateSprint_DoneWithoutVerifyCmd(t *testing.T) {
+	sprint := &domain.Sprint{
+		Name: "Test Sprint",
+		Tickets: []domain.Ticket{
+			{
+				Name: "ticket-1",
+				Tasks: []domain.Task{
+					{Description: "Task", Done: &domain.Done{Output: "ok"}},
+				},
+			},
+		},
+	}
+
+	errs := ValidateSprint(sprint)
+	if len(errs) != 1 {
+		t.Fatalf("expected 1 error, got %d: %v", len(errs), errs)
+	}
+	if errs[0].Field != "tickets[0].tasks[0].done" {
+		t.Errorf("Field: got %q, want %q", errs[0].Field, "tickets[0].tasks[0].done")
+	}
+}
+
+func TestValidateSprint_VerifyTimeoutWithoutVerifyCmd(t *testing.T) {
+	sprint := &domain.Sprint{
+		Name: "Test Sprint",
+		Tickets: []domain.Ticket{
+			{
+				Name: "ticket-1",
+				Tasks: []domain.Task{
+					{Description: "Task", VerifyTimeout: domain.Duration(time.Minute)},
+				},
+			},
+		},
+	}
+
+	errs := ValidateSprint(sprint)
+	if len(errs) != 1 {
+		t.Fatalf("expected 1 error, got %d: %v", len(errs), errs)
+	}
+	if errs[0].Field != "tickets[0].tasks[0].verify_timeout" {
+		t.Errorf("Field: got %q, want %q", errs[0].Field, "tickets[0].tasks[0].verify_timeout")
+	}
+}
+
+func TestValidateSprint_InvalidDoneOutputRegex(t *testing.T) {
+	sprint := &domain.Sprint{
+		Name: "Test Sprint",
+		Tickets: []domain.Ticket{
+			{
+				Name: "ticket-1",
+				Tasks: []domain.Task{
+					{Description: "Task", VerifyCmd: "make test", Done: &domain.Done{Output: "(unclosed"}},
+				},
+			},
+		},
+	}
+
+	errs := ValidateSprint(sprint)
+	if len(errs) != 1 {
+		t.Fatalf("expected 1 error, got %d: %v", len(errs), errs)
+	}
+	if errs[0].Field != "tickets[0].tasks[0].done.output" {
+		t.Errorf("Field: got %q, want %q", errs[0].Field, "tickets[0].tasks[0].done.output")
+	}
+}
+
+func TestValidateSprint_Dependencies(t *testing.T) {
+	tests := []struct {
+		name      string
+		tickets   []domain.Ticket
+		wantField string
+		wantMsg   string
+	}{
+		{
+			name: "unknown dependency",
+			tickets: []domain.Ticket{
+				{Name: "a", DependsOn: []string{"missing"}},
+			},
+			wantField: "tickets[0].depends_on[0]",
+			wantMsg:   `unknown ticket "missing"`,
+		},
+		{
+			name: "self dependency",
+			tickets: []domain.Ticket{
+				{Name: "a", DependsOn: []string{"a"}},
+			},
+			wantField: "tickets[0].depends_on[0]",
+			wantMsg:   "ticket cannot depend on itself",
+		},
+		{
+			name: "duplicate name",
+			tickets: []domain.Ticket{
+				{Name: "a"},
+				{Name: "a"},
+			},
+			wantField: "tickets[1].name",
+			wantMsg:   `duplicate ticket name "a"`,
+		},
+		{
+			name: "cycle",
+			tickets: []domain.Ticket{
+				{Name: "a", DependsOn: []string{"b"}},
+				{Name: "b", DependsOn: []string{"c"}},
+				{Name: "c", DependsOn: []string{"a"}},
+			},
+			wantField: "tickets",
+			wantMsg:   "dependency cycle: a -> b -> c -> a",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := ValidateSprint(&domain.Sprint{Name: "Test Sprint", Tickets: tt.tickets})
+			if len(errs) != 1 {
+				t.Fatalf("expected 1 error, got %d: %v", len(errs), errs)
+			}
+			if errs[0].Field != tt.wantField {
+				t.Errorf("Field: got %q, want %q", errs[0].Field, tt.wantField)
+			}
+			if errs[0].Message != tt.wantMsg {
+				t.Errorf("Message: got %q, want %q", errs[0].Message, tt.wantMsg)
+			}
+		})
+	}
+}
+
+func TestValidateSprint_Keys(t *testing.T) {
+	tests := []struct {
+		name      string
+		tickets   []domain.Ticket
+		wantField string
+		wantMsg   string
+	}{
+		{
+			name:      "duplicate ticket id",
+			tickets:   []domain.Ticket{{ID: "t", Name: "a"}, {ID: "t", Name: "b"}},
+			wantField: "tickets[1].id",
+			wantMsg:   `duplicate ticket id "t"`,
+		},
+		{
+			name:      "ticket name used as id",
+			tickets:   []domain.Ticket{{ID: "b", Name: "a"}, {Name: "b"}},
+			wantField: "tickets[1].name",
+			wantMsg:   `ticket name "b" is already used as an id`,
+		},
+		{
+			name: "duplicate task description",
+			tickets: []domain.Ticket{{Name: "a", Tasks: []domain.Task{
+				{Description: "Add tests"}, {Description: "Add tests"},
+			}}},
+			wantField: "tickets[0].tasks[1].description",
+			wantMsg:   `duplicate task description "Add tests"; set id to tell the tasks apart`,
+		},
+		{
+			name: "duplicate task id",
+			tickets: []domain.Ticket{{Name: "a", Tasks: []domain.Task{
+				{ID: "x", Description: "One"}, {ID: "x", Description: "Two"},
+			}}},
+			wantField: "tickets[0].tasks[1].id",
+			wantMsg:   `duplicate task id "x"`,
+		},
+		{
+			name: "task description used as id",
+			tickets: []domain.Ticket{{Name: "a", Tasks: []domain.Task{
+				{ID: "Two", Description: "One"}, {Description: "Two"},
+			}}},
+			wantField: "tickets[0].tasks[1].description",
+			wantMsg:   `task description "Two" is already used as an id`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := ValidateSprint(&domain.Sprint{Name: "Test Sprint", Tickets: tt.tickets})
+			if len(errs) != 1 {
+				t.Fatalf("expected 1 error, got %d: %v", len(errs), errs)
+			}
+			if errs[0].Field != tt.wantField {
+				t.Errorf("Field: got %q, want %q", errs[0].Field, tt.wantField)
+			}
+			if errs[0].Message != tt.wantMsg {
+				t.Errorf("Message: got %q, want %q", errs[0].Message, tt.wantMsg)
+			}
+		})
+	}
+}
+
+func TestValidateSprint_DuplicateTasksWithIDs(t *testing.T) {
+	sprint := &domain.Sprint{
+		Name: "Test Sprint",
+		Tickets: []domain.Ticket{{Name: "a", Tasks: []domain.Task{
+			{ID: "first", Description: "Add tests"}, {ID: "second", Description: "Add tests"},
+		}}},
+	}
+
+	if errs := ValidateSprint(sprint); len(errs) != 0 {
+		t.Errorf("expected no errors, got %v", errs)
+	}
+}
+
+func TestValidateSprint_ValidDependencies(t *testing.T) {
+	sprint := &domain.Sprint{
+		Name: "Test Sprint",
+		Tickets: []domain.Ticket{
+			{Name: "a"},
+			{Name: "b", DependsOn: []string{"a"}},
+			{Name: "c", DependsOn: []string{"a", "b"}},
+		},
+	}
+
+	if errs := ValidateSprint(sprint); len(errs) != 0 {
+		t.Errorf("expected no errors, got %d: %v", len(errs), errs)
+	}
+}
+
+func TestValidateSprint_Rules(t *testing.T) {
+	tests := []struct {
+		name      string
+		sprint    *domain.Sprint
+		wantField string
+		wantMsg   string
+	}{
+		{
+			name:      "empty text",
+			sprint:    &domain.Sprint{Name: "s", Rules: []domain.Rule{{ID: "a"}}},
+			wantField: "rules[0].text",
+			wantMsg:   "required",
+		},
+		{
+			name: "remove without id",
+			sprint: &domain.Sprint{Name: "s", Tickets: []domain.Ticket{
+				{Name: "t", Rules: []domain.Rule{{Remove: true}}},
+			}},
+			wantField: "tickets[0].rules[0].id",
+			wantMsg:   "required to remove a rule",
+		},
+		{
+			name: "remove with text",
+			sprint: &domain.Sprint{Name: "s", Tickets: []domain.Ticket{
+				{Name: "t", Tasks: []domain.Task{
+					{Description: "d", Rules: []domain.Rule{{ID: "a", Text: "x", Remove: true}}},
+				}},
+			}},
+			wantField: "tickets[0].tasks[0].rules[0].text",
+			wantMsg:   "not allowed when removing a rule",
+		},
+		{
+			name: "duplicate id",
+			sprint: &domain.Sprint{Name: "s", Rules: []domain.Rule{
+				{ID: "a", Text: "one"},
+				{ID: "a", Text: "two"},
+			}},
+			wantField: "rules[1].id",
+			wantMsg:   `duplicate rule id "a"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := ValidateSprint(tt.sprint)
+			if len(errs) != 1 {
+				t.Fatalf("expected 1 error, got %d: %v", len(errs), errs)
+			}
+			if errs[0].Field != tt.wantField {
+				t.Errorf("Field: got %q, want %q", errs[0].Field, tt.wantField)
+			}
+			if errs[0].Message != tt.wantMsg {
+				t.Errorf("Message: got %q, want %q", errs[0].Message, tt.wantMsg)
+			}
+		})
+	}
+}
+
+func TestValidateSprint_Retry(t *testing.T) {
+	sprint := &domain.Sprint{
+		Name:         "Test Sprint",
+		RetryBackoff: 0.5,
+		Tickets: []domain.Ticket{{
+			Name:  "ticket-1",
+			Tasks: []domain.Task{{Description: "task", MaxAttempts: -1}},
+		}},
+	}
+
+	errs := ValidateSprint(sprint)
+	if len(errs) != 2 {
+		t.Fatalf("expected 2 errors, got %d: %v", len(errs), errs)
+	}
+	if errs[0].Field != "retry_backoff" {
+		t.Errorf("Field: got %q, want %q", errs[0].Field, "retry_backoff")
+	}
+	if errs[1].Field != "tickets[0].tasks[0].max_attempts" {
+		t.Errorf("Field: got %q, want %q", errs[1].Field, "tickets[0].tasks[0].max_attempts")
+	}
+
+	sprint.RetryBackoff = 2
+	sprint.Tickets[0].Tasks[0].MaxAttempts = 5
+	if errs := ValidateSprint(sprint); len(errs) != 0 {
+		t.Errorf("expected no errors, got %d: %v", len(errs), errs)
+	}
+}
+
+func TestValidateSprint_Agent(t *testing.T) {
+	tests := []struct {
+		name      string
+		agent     domain.Agent
+		verifyCmd string
+		wantField string
+	}{
+		{name: "default", agent: domain.Agent{}},
+		{name: "codex with args", agent: domain.Agent{Name: "codex", Args: []string{"-m", "o3"}}},
+		{name: "custom", agent: domain.Agent{Name: "custom", Command: []string{"my-agent"}, Prompt: "arg"}},
+		{name: "unknown agent", agent: domain.Agent{Name: "gpt"}, wantField: "agent.name"},
+		{name: "custom without command", agent: domain.Agent{Name: "custom"}, wantField: "agent.command"},
+		{name: "custom with bad prompt", agent: domain.Agent{Name: "custom", Command: []string{"a"}, Prompt: "pipe"}, wantField: "agent.prompt"},
+		{name: "command on claude", agent: domain.Agent{Command: []string{"a"}}, wantField: "agent.command"},
+		{name: "prompt on aider", agent: domain.Agent{Name: "aider", Prompt: "file"}, verifyCmd: "make test", wantField: "agent.prompt"},
+		{name: "aider with verify_cmd", agent: domain.Agent{Name: "aider"}, verifyCmd: "make test"},
+		{name: "aider without verify_cmd", agent: domain.Agent{Name: "aider"}, wantField: "tickets[0].tasks[0].verify_cmd"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sprint := &domain.Sprint{
+				Name:    "Test Sprint",
+				Agent:   tt.agent,
+				Tickets: []domain.Ticket{{Name: "ticket-1", Tasks: []domain.Task{{Description: "task", VerifyCmd: tt.verifyCmd}}}},
+			}
+
+			errs := ValidateSprint(sprint)
+			if tt.wantField == "" {
+				if len(errs) != 0 {
+					t.Errorf("expected no errors, got %d: %v", len(errs), errs)
+				}
+				return
+			}
+			if len(errs) != 1 {
+				t.Fatalf("expected 1 error, got %d: %v", len(errs), errs)
+			}
+			if errs[0].Field != tt.wantField {
+				t.Errorf("Field: got %q, want %q", errs[0].Field, tt.wantField)
+			}
+		})
+	}
+}
+
+func TestValidateSprint_Model(t *testing.T) {
+	sprint := &domain.Sprint{
+		Name:       "Test Sprint",
+		Escalation: []string{"haiku", "opus"},
+		Effort:     "extreme",
+		Tickets: []domain.Ticket{{
+			Name:  "ticket-1",
+			Model: "sonnet",
+			Tasks: []domain.Task{{Description: "task", Model: "opus", Escalation: []string{"haiku", " "}}},
+		}},
+	}
+
+	errs := ValidateSprint(sprint)
+	want := []string{"effort", "tickets[0].tasks[0].escalation", "tickets[0].tasks[0].escalation[1]"}
+	if len(errs) != len(want) {
+		t.Fatalf("expected %d errors, got %d: %v", len(want), len(errs), errs)
+	}
+	for i, field := range want {
+		if errs[i].Field != field {
+			t.Errorf("Field: got %q, want %q", errs[i].Field, field)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package domain
+
+import "gopkg.in/yaml.v3"
+
+// Agent names accepted in kamaji.yaml.
+const (
+	AgentClaude = "claude" // Claude Code, the default
+	AgentCodex  = "codex"  // OpenAI Codex CLI
+	AgentAider  = "aider"  // Aider, which has no MCP client and is judged by its exit status
+	AgentCustom = "custom" // Any command, configured with Command and Prompt
+)
+
+// Prompt delivery modes for custom agents.
+const (
+	PromptStdin = "stdin" // Written to the agent's stdin, the default
+	PromptArg   = "arg"   // Appended as the last argument
+	PromptFile  = "file"  // Written to a file named by KAMAJI_PROMPT_FILE and {prompt_file} in Command
+)
+
+// Agent selects the coding agent kamaji spawns for each task. In YAML it is
+// either a plain agent name or a mapping.
+type Agent struct {
+	Name    string   `yaml:"name,omitempty"`    // One of the Agent* names, defaults to claude
+	Command []string `yaml:"command,omitempty"` // Program and arguments of a custom agent
+	Prompt  string   `yaml:"prompt,omitempty"`  // How a custom agent receives the prompt: stdin, arg or file
+	Args    []string `yaml:"args,omitempty"`    // Extra arguments appended to any agent's command line
+}
+
+// Kind returns the agent name, defaulting to claude.
+func (a Agent) Kind() string {
+	if a.Name == "" {
+		return AgentClaude
+	}
+	return a.Name
+}
+
+// UnmarshalYAML accepts both the plain name and the mapping form.
+func (a *Agent) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*a = Agent{}
+		return node.Decode(&a.Name)
+	}
+	type plain Agent
+	var p plain
+	if err := node.Decode(&p); err != nil {
+		return err
+	}
+	*a = Agent(p)
+	return nil
+}
+
+// MarshalYAML writes an agent with only a name as a plain string.
+func (a Agent) MarshalYAML() (any, error) {
+	if a.Command == nil && a.Prompt == "" && a.Args == nil {
+		return a.Name, nil
+	}
+	type plain Agent
+	return plain(a), nil
+}
@@ -0,0 +1,8 @@
+package domain
+
+// ContextFile is a reference file embedded in the agent prompt.
+type ContextFile struct {
+	Path      string // Slash-separated, relative to the work dir
+	Content   string
+	Truncated bool // Content was cut to fit the context size limits
+}
@@ -0,0 +1,51 @@
+package domain
+
+import "time"
+
+// Event types recorded in .kamaji/events.jsonl.
+const (
+	EventSprintStart      = "sprint_start"      // State holds the progress the run resumed from
+	EventSprintComplete   = "sprint_complete"   // Every ticket is complete
+	EventStuck            = "stuck"             // The ticket hit max_attempts; Summary holds the last failure
+	EventInterrupted      = "interrupted"       // Summary holds the error that ended the run
+	EventBranchCreated    = "branch_created"    // Branch is the new ticket branch
+	EventDependencyMerged = "dependency_merged" // Branch is the merged dependency branch
+	EventSpawn            = "spawn"             // An agent session started for the task; Model when one was chosen
+	EventSignal           = "signal"            // An MCP tool call; Tool, Status and Summary
+	EventStaleSignal      = "stale_signal"      // A signal from an earlier session, discarded; Tool, Status and Summary
+	EventTimeout          = "timeout"           // Summary holds the watchdog reason
+	EventVerify           = "verify"            // Status is pass or fail, Summary the reason
+	EventReview           = "review"            // Status is approve or reject, Summary the reasons
+	EventCommit           = "commit"            // Commit is the new HEAD
+	EventReset            = "reset"             // Uncommitted changes were discarded
+	EventCompleted        = "completed"         // History: a task passed
+	EventFailed           = "failed"            // History: an attempt failed
+	EventInsight          = "insight"           // History: Summary is a recorded insight
+	EventPlan             = "plan"              // History: Summary is the plan for the task
+	EventQuestion         = "question"          // History: Summary is an ask_human question, Answer its answer
+	EventManualAction     = "manual_action"     // History: a skip, retry, goto or reset
+	EventProposal         = "proposal"          // A propose_task proposal; Status is pending, accepted or rejected, Summary the task
+	EventStateSaved       = "state_saved"       // Progress is the ticket's saved state, nil once removed
+)
+
+// Event is one line of the append-only journal. Ticket is the ticket name and
+// Task the task description; Key is the ticket's state key for state_saved.
+type Event struct {
+	Time     time.Time     `json:"time"`
+	Type     string        `json:"type"`
+	Ticket   string        `json:"ticket,omitempty"`
+	Task     string        `json:"task,omitempty"`
+	Branch   string        `json:"branch,omitempty"`
+	Commit   string        `json:"commit,omitempty"`
+	Tool     string        `json:"tool,omitempty"`
+	Status   string        `json:"status,omitempty"`
+	Summary  string        `json:"summary,omitempty"`
+	Answer   string        `json:"answer,omitempty"`
+	Model    string        `json:"model,omitempty"`
+	Key      string        `json:"key,omitempty"`
+	Progress *TicketState  `json:"progress,omitempty"`
+	Action   *ManualAction `json:"action,omitempty"`
+	Usage    *Usage        `json:"usage,omitempty"`     // Usage of the attempt, on completed and failed
+	TimedOut bool          `json:"timed_out,omitempty"` // On failed, when the watchdog stopped the session
+	State    *State        `json:"state,omitempty"`
+}
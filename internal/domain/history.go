@@ -2,26 +2,57 @@ package domain
 
 // TicketHistory persists to .kamaji/history/<ticket>.yaml.
 type TicketHistory struct {
-	Ticket         string          `yaml:"ticket"`
-	Completed      []CompletedTask `yaml:"completed"`
-	FailedAttempts []FailedAttempt `yaml:"failed_attempts"`
-	Insights       []string        `yaml:"insights"`
+	Ticket         string          `yaml:"ticket" json:"ticket"`
+	Completed      []CompletedTask `yaml:"completed" json:"completed"`
+	FailedAttempts []FailedAttempt `yaml:"failed_attempts" json:"failed_attempts"`
+	Insights       []string        `yaml:"insights" json:"insights"`
+	Plans          []TaskPlan      `yaml:"plans,omitempty" json:"plans"`
+	Questions      []Question      `yaml:"questions,omitempty" json:"questions"`
+	ManualActions  []ManualAction  `yaml:"manual_actions,omitempty" json:"manual_actions"`
 }
 
 type CompletedTask struct {
-	Task    string `yaml:"task"`
-	Summary string `yaml:"summary"`
+	Task    string `yaml:"task" json:"task"`
+	Summary string `yaml:"summary" json:"summary"`
+	Usage   *Usage `yaml:"usage,omitempty" json:"usage,omitempty"` // Usage of the passing attempt
 }
 
 type FailedAttempt struct {
-	Task    string `yaml:"task"`
-	Summary string `yaml:"summary"`
+	Task     string `yaml:"task" json:"task"`
+	Summary  string `yaml:"summary" json:"summary"`
+	TimedOut bool   `yaml:"timed_out,omitempty" json:"timed_out,omitempty"` // The watchdog stopped the session
+	Usage    *Usage `yaml:"usage,omitempty" json:"usage,omitempty"`
+}
+
+// TaskPlan is a plan returned by a planning session, which the following
+// implementation session was given.
+type TaskPlan struct {
+	Task string `yaml:"task" json:"task"`
+	Plan string `yaml:"plan" json:"plan"`
+}
+
+// Question is an agent's ask_human question with the answer it got.
+type Question struct {
+	Task     string `yaml:"task" json:"task"`
+	Question string `yaml:"question" json:"question"`
+	Answer   string `yaml:"answer" json:"answer"`
+}
+
+// ManualAction records a state change made by hand, such as skipping a task.
+type ManualAction struct {
+	Action string `yaml:"action" json:"action"`
+	Task   string `yaml:"task,omitempty" json:"task,omitempty"`
+	Detail string `yaml:"detail,omitempty" json:"detail,omitempty"`
 }
 
 // HistorySummary provides aggregate statistics for ticket history.
 type HistorySummary struct {
-	TotalCompleted int
-	TotalFailed    int
-	TotalInsights  int
-	TicketCount    int
+	TotalCompleted int   `json:"completed"`
+	TotalFailed    int   `json:"failed"`
+	TotalInsights  int   `json:"insights"`
+	TotalPlans     int   `json:"plans"`
+	TotalQuestions int   `json:"questions"`
+	TotalManual    int   `json:"manual_actions"`
+	TicketCount    int   `json:"tickets"`
+	Usage          Usage `json:"usage"` // Summed over completed tasks and failed attempts
 }
@@ -0,0 +1,29 @@
+package domain
+
+import "time"
+
+// Proposal statuses.
+const (
+	ProposalPending  = "pending"
+	ProposalAccepted = "accepted"
+	ProposalRejected = "rejected"
+)
+
+// Proposal is a follow-up task an agent suggested with propose_task. It waits
+// in .kamaji/proposals.yaml until it is accepted into kamaji.yaml or rejected.
+type Proposal struct {
+	ID          int       `yaml:"id"`
+	Ticket      string    `yaml:"ticket"`
+	Description string    `yaml:"description"`
+	Steps       []string  `yaml:"steps,omitempty"`
+	Verify      string    `yaml:"verify,omitempty"`
+	Reason      string    `yaml:"reason"`
+	Task        string    `yaml:"task,omitempty"` // Task whose session proposed it
+	Status      string    `yaml:"status"`
+	Time        time.Time `yaml:"time"`
+}
+
+// NewTask returns the task to add to the sprint when the proposal is accepted.
+func (p *Proposal) NewTask() Task {
+	return Task{Description: p.Description, Steps: p.Steps, Verify: p.Verify}
+}
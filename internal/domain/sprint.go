@@ -1,22 +1,160 @@
 package domain
 
+import (
+	"fmt"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
 // Sprint is loaded from kamaji.yaml.
 type Sprint struct {
-	Name       string   `yaml:"name"`
-	BaseBranch string   `yaml:"base_branch"`
-	Rules      []string `yaml:"rules"`
-	Tickets    []Ticket `yaml:"tickets"`
+	Name            string   `yaml:"name"`
+	BaseBranch      string   `yaml:"base_branch"`
+	Agent           Agent    `yaml:"agent,omitempty"` // Coding agent spawned per task, defaults to claude
+	Rules           []Rule   `yaml:"rules"`
+	Timeout         Duration `yaml:"timeout,omitempty"`          // Default wall-clock limit per agent session
+	IdleTimeout     Duration `yaml:"idle_timeout,omitempty"`     // Default limit on time without agent output
+	QuestionTimeout Duration `yaml:"question_timeout,omitempty"` // How long ask_human waits for an answer, defaults to 10m
+	MaxAttempts     int      `yaml:"max_attempts,omitempty"`     // Attempts per task before the sprint is stuck, defaults to 3
+	RetryDelay      Duration `yaml:"retry_delay,omitempty"`      // Wait before retrying a failed task
+	RetryBackoff    float64  `yaml:"retry_backoff,omitempty"`    // Multiplies the delay for each further retry
+	Model           string   `yaml:"model,omitempty"`            // Default agent model, empty for the agent's own default
+	Effort          string   `yaml:"effort,omitempty"`           // Default reasoning effort: low, medium or high
+	Escalation      []string `yaml:"escalation,omitempty"`       // Models per attempt, the last repeated; replaces model
+	Plan            *bool    `yaml:"plan,omitempty"`             // Run a read-only planning session before each attempt
+	Review          *bool    `yaml:"review,omitempty"`           // Have a fresh session review each pass before it is committed
+	Tickets         []Ticket `yaml:"tickets"`
 }
 
 type Ticket struct {
-	Name        string `yaml:"name"`
-	Branch      string `yaml:"branch"`
-	Description string `yaml:"description"`
-	Tasks       []Task `yaml:"tasks"`
+	ID           string   `yaml:"id,omitempty"` // Stable state key, defaults to the name
+	Name         string   `yaml:"name"`
+	Branch       string   `yaml:"branch"`
+	Description  string   `yaml:"description"`
+	DependsOn    []string `yaml:"depends_on,omitempty"` // Ticket names that must complete first
+	Rules        []Rule   `yaml:"rules,omitempty"`
+	Context      []string `yaml:"context,omitempty"` // Paths, globs or @file references to inject
+	Timeout      Duration `yaml:"timeout,omitempty"`
+	IdleTimeout  Duration `yaml:"idle_timeout,omitempty"`
+	MaxAttempts  int      `yaml:"max_attempts,omitempty"`
+	RetryDelay   Duration `yaml:"retry_delay,omitempty"`
+	RetryBackoff float64  `yaml:"retry_backoff,omitempty"`
+	Model        string   `yaml:"model,omitempty"`
+	Effort       string   `yaml:"effort,omitempty"`
+	Escalation   []string `yaml:"escalation,omitempty"`
+	Plan         *bool    `yaml:"plan,omitempty"`
+	Review       *bool    `yaml:"review,omitempty"`
+	Tasks        []Task   `yaml:"tasks"`
 }
 
 type Task struct {
-	Description string   `yaml:"description"`
-	Steps       []string `yaml:"steps"`
-	Verify      string   `yaml:"verify"`
+	ID            string   `yaml:"id,omitempty"` // Stable state key, defaults to the description
+	Description   string   `yaml:"description"`
+	Steps         []string `yaml:"steps"`
+	Verify        string   `yaml:"verify"`
+	VerifyCmd     string   `yaml:"verify_cmd,omitempty"`
+	VerifyTimeout Duration `yaml:"verify_timeout,omitempty"` // Limit on verify_cmd, defaults to the session timeout
+	Done          *Done    `yaml:"done,omitempty"`
+	Rules         []Rule   `yaml:"rules,omitempty"`
+	Context       []string `yaml:"context,omitempty"`
+	Timeout       Duration `yaml:"timeout,omitempty"`
+	IdleTimeout   Duration `yaml:"idle_timeout,omitempty"`
+	MaxAttempts   int      `yaml:"max_attempts,omitempty"`
+	RetryDelay    Duration `yaml:"retry_delay,omitempty"`
+	RetryBackoff  float64  `yaml:"retry_backoff,omitempty"`
+	Model         string   `yaml:"model,omitempty"`
+	Effort        string   `yaml:"effort,omitempty"`
+	Escalation    []string `yaml:"escalation,omitempty"`
+	Plan          *bool    `yaml:"plan,omitempty"`
+	Review        *bool    `yaml:"review,omitempty"`
+}
+
+// Reasoning effort levels accepted in kamaji.yaml.
+const (
+	EffortLow    = "low"
+	EffortMedium = "medium"
+	EffortHigh   = "high"
+)
+
+// Key identifies the ticket's progress in the runtime state.
+func (t *Ticket) Key() string {
+	if t.ID != "" {
+		return t.ID
+	}
+	return t.Name
+}
+
+// Key identifies the task's completion in the runtime state, so progress
+// follows the task when tasks are inserted or reordered.
+func (t *Task) Key() string {
+	if t.ID != "" {
+		return t.ID
+	}
+	return t.Description
+}
+
+// Done defines when VerifyCmd counts as passing. A nil Done requires exit code 0.
+type Done struct {
+	ExitCode *int   `yaml:"exit_code,omitempty"` // Expected exit code, defaults to 0
+	Output   string `yaml:"output,omitempty"`    // Regex that combined output must match
+}
+
+// Rule is an agent guideline. Rules cascade from sprint to ticket to task; a
+// rule with an ID replaces an inherited rule with the same ID, or drops it when
+// Remove is set. In YAML a rule is either a plain string or a mapping.
+type Rule struct {
+	ID     string `yaml:"id,omitempty"`
+	Text   string `yaml:"text,omitempty"`
+	Remove bool   `yaml:"remove,omitempty"`
+}
+
+// UnmarshalYAML accepts both the plain string and the mapping form.
+func (r *Rule) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*r = Rule{}
+		return node.Decode(&r.Text)
+	}
+	type plain Rule
+	var p plain
+	if err := node.Decode(&p); err != nil {
+		return err
+	}
+	*r = Rule(p)
+	return nil
+}
+
+// MarshalYAML writes rules without an ID as plain strings.
+func (r Rule) MarshalYAML() (any, error) {
+	if r.ID == "" && !r.Remove {
+		return r.Text, nil
+	}
+	type plain Rule
+	return plain(r), nil
+}
+
+// Duration is a time.Duration written in YAML as a Go duration string, such as
+// "30m" or "1h30m". Zero means unset.
+type Duration time.Duration
+
+// UnmarshalYAML parses a duration string, rejecting negative values.
+func (d *Duration) UnmarshalYAML(node *yaml.Node) error {
+	var s string
+	if err := node.Decode(&s); err != nil {
+		return err
+	}
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return fmt.Errorf("line %d: invalid duration %q", node.Line, s)
+	}
+	if parsed < 0 {
+		return fmt.Errorf("line %d: duration %q must not be negative", node.Line, s)
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+// MarshalYAML writes the duration as a string.
+func (d Duration) MarshalYAML() (any, error) {
+	return time.Duration(d).String(), nil
 }
@@ -1,7 +1,10 @@
 package domain
 
 import (
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
@@ -10,7 +13,7 @@ func TestSprint_YAMLRoundtrip(t *testing.T) {
 	original := Sprint{
 		Name:       "Test Sprint",
 		BaseBranch: "main",
-		Rules:      []string{"Rule 1", "Rule 2"},
+		Rules:      []Rule{{Text: "Rule 1"}, {ID: "tests", Text: "Rule 2"}},
 		Tickets: []Ticket{
 			{
 				Name:        "login-form",
@@ -44,7 +47,10 @@ func TestSprint_YAMLRoundtrip(t *testing.T) {
 		t.Errorf("BaseBranch: got %q, want %q", decoded.BaseBranch, original.BaseBranch)
 	}
 	if len(decoded.Rules) != len(original.Rules) {
-		t.Errorf("Rules length: got %d, want %d", len(decoded.Rules), len(original.Rules))
+		t.Fatalf("Rules length: got %d, want %d", len(decoded.Rules), len(original.Rules))
+	}
+	if decoded.Rules[1] != original.Rules[1] {
+		t.Errorf("Rules[1]: got %+v, want %+v", decoded.Rules[1], original.Rules[1])
 	}
 	if len(decoded.Tickets) != len(original.Tickets) {
 		t.Fatalf("Tickets length: got %d, want %d", len(decoded.Tickets), len(original.Tickets))
@@ -115,7 +121,7 @@ tickets:
 	if s.BaseBranch != "develop" {
 		t.Errorf("BaseBranch: got %q, want %q", s.BaseBranch, "develop")
 	}
-	if len(s.Rules) != 1 || s.Rules[0] != "Follow patterns" {
+	if len(s.Rules) != 1 || s.Rules[0].Text != "Follow patterns" {
 		t.Errorf("Rules: got %v, want [Follow patterns]", s.Rules)
 	}
 	if len(s.Tickets) != 1 {
@@ -154,3 +160,126 @@ func TestTask_ZeroValue(t *testing.T) {
 		t.Errorf("Verify zero value: got %q, want empty", task.Verify)
 	}
 }
+
+func TestRule_YAMLForms(t *testing.T) {
+	yamlData := `
+rules:
+  - "Plain rule"
+  - id: tests
+    text: "Run the tests"
+  - id: lint
+    remove: true
+`
+	var s Sprint
+	if err := yaml.Unmarshal([]byte(yamlData), &s); err != nil {
+		t.Fatalf("unmarshal error: %v", err)
+	}
+
+	want := []Rule{
+		{Text: "Plain rule"},
+		{ID: "tests", Text: "Run the tests"},
+		{ID: "lint", Remove: true},
+	}
+	if len(s.Rules) != len(want) {
+		t.Fatalf("Rules: got %d rules, want %d", len(s.Rules), len(want))
+	}
+	for i := range want {
+		if s.Rules[i] != want[i] {
+			t.Errorf("Rules[%d]: got %+v, want %+v", i, s.Rules[i], want[i])
+		}
+	}
+
+	data, err := yaml.Marshal(Rule{Text: "Plain rule"})
+	if err != nil {
+		t.Fatalf("marshal error: %v", err)
+	}
+	if string(data) != "Plain rule\n" {
+		t.Errorf("Marshal plain rule: got %q, want %q", data, "Plain rule\n")
+	}
+}
+
+func TestAgent_YAMLForms(t *testing.T) {
+	tests := []struct {
+		name string
+		yaml string
+		want Agent
+	}{
+		{name: "omitted", yaml: "name: s\n", want: Agent{}},
+		{name: "plain name", yaml: "agent: codex\n", want: Agent{Name: "codex"}},
+		{
+			name: "mapping",
+			yaml: "agent:\n  name: custom\n  command: [my-agent, --fast]\n  prompt: file\n",
+			want: Agent{Name: "custom", Command: []string{"my-agent", "--fast"}, Prompt: "file"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var s Sprint
+			if err := yaml.Unmarshal([]byte(tt.yaml), &s); err != nil {
+				t.Fatalf("unmarshal error: %v", err)
+			}
+			if !reflect.DeepEqual(s.Agent, tt.want) {
+				t.Errorf("Agent: got %+v, want %+v", s.Agent, tt.want)
+			}
+		})
+	}
+
+	data, err := yaml.Marshal(Sprint{Name: "s", Agent: Agent{Name: "aider"}})
+	if err != nil {
+		t.Fatalf("marshal error: %v", err)
+	}
+	if !strings.Contains(string(data), "agent: aider\n") {
+		t.Errorf("Marshal plain agent: got %q, want %q", data, "agent: aider")
+	}
+	if (Agent{}).Kind() != AgentClaude {
+		t.Errorf("Kind(): got %q, want %q", (Agent{}).Kind(), AgentClaude)
+	}
+}
+
+func TestDuration_YAML(t *testing.T) {
+	var s Sprint
+	if err := yaml.Unmarshal([]byte("timeout: 1h30m\nidle_timeout: 90s\n"), &s); err != nil {
+		t.Fatalf("unmarshal error: %v", err)
+	}
+	if time.Duration(s.Timeout) != 90*time.Minute {
+		t.Errorf("Timeout: got %v, want 1h30m0s", time.Duration(s.Timeout))
+	}
+	if time.Duration(s.IdleTimeout) != 90*time.Second {
+		t.Errorf("IdleTimeout: got %v, want 1m30s", time.Duration(s.IdleTimeout))
+	}
+
+	data, err := yaml.Marshal(Task{Description: "d", Timeout: Duration(time.Minute)})
+	if err != nil {
+		t.Fatalf("marshal error: %v", err)
+	}
+	if !strings.Contains(string(data), "timeout: 1m0s") {
+		t.Errorf("Marshal: got %q, want timeout: 1m0s", data)
+	}
+
+	for _, bad := range []string{"timeout: soon", "timeout: -5m"} {
+		if err := yaml.Unmarshal([]byte(bad), &s); err == nil {
+			t.Errorf("Unmarshal(%q) error = nil, want error", bad)
+		}
+	}
+}
+
+func TestKeys_PreferID(t *testing.T) {
+	ticket := Ticket{Name: "login-form"}
+	if got := ticket.Key(); got != "login-form" {
+		t.Errorf("Ticket.Key() = %q, want name", got)
+	}
+	ticket.ID = "T-1"
+	if got := ticket.Key(); got != "T-1" {
+		t.Errorf("Ticket.Key() = %q, want id", got)
+	}
+
+	task := Task{Description: "Add tests"}
+	if got := task.Key(); got != "Add tests" {
+		t.Errorf("Task.Key() = %q, want description", got)
+	}
+	task.ID = "tests"
+	if got := task.Key(); got != "tests" {
+		t.Errorf("Task.Key() = %q, want id", got)
+	}
+}
@@ -1,8 +1,23 @@
 package domain
 
 // State persists to .kamaji/state.yaml.
+// Progress is tracked per ticket so independent tickets can advance concurrently.
 type State struct {
-	CurrentTicket int `yaml:"current_ticket"`
-	CurrentTask   int `yaml:"current_task"`
-	FailureCount  int `yaml:"failure_count"`
+	Tickets map[string]TicketState `yaml:"tickets,omitempty" json:"tickets,omitempty"` // Keyed by Ticket.Key
+
+	// Single cursor of releases before per-ticket progress. It is moved into
+	// Tickets by statemachine.MigrateState and never written back.
+	LegacyTicket   *int `yaml:"current_ticket,omitempty" json:"-"`
+	LegacyTask     int  `yaml:"current_task,omitempty" json:"-"`
+	LegacyFailures int  `yaml:"failure_count,omitempty" json:"-"`
+}
+
+// TicketState records which of a ticket's tasks are done, by Task.Key, which
+// of those were skipped rather than passed, and the consecutive failures of
+// the task being worked on.
+type TicketState struct {
+	Done         []string `yaml:"done,omitempty" json:"done,omitempty"`
+	Skipped      []string `yaml:"skipped,omitempty" json:"skipped,omitempty"`           // Task.Keys in Done that were skipped
+	FailingTask  string   `yaml:"failing_task,omitempty" json:"failing_task,omitempty"` // Task.Key that FailureCount applies to
+	FailureCount int      `yaml:"failure_count,omitempty" json:"failure_count,omitempty"`
 }
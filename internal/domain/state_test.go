@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"strings"
 	"testing"
 
 	"gopkg.in/yaml.v3"
@@ -8,9 +9,10 @@ import (
 
 func TestState_YAMLRoundtrip(t *testing.T) {
 	original := State{
-		CurrentTicket: 2,
-		CurrentTask:   5,
-		FailureCount:  1,
+		Tickets: map[string]TicketState{
+			"login-form": {Done: []string{"Create form"}, FailingTask: "Add tests", FailureCount: 1},
+			"dashboard":  {Done: []string{"Add layout", "Add charts"}},
+		},
 	}
 
 	data, err := yaml.Marshal(&original)
@@ -23,32 +25,30 @@ func TestState_YAMLRoundtrip(t *testing.T) {
 		t.Fatalf("unmarshal error: %v", err)
 	}
 
-	if decoded.CurrentTicket != original.CurrentTicket {
-		t.Errorf("CurrentTicket: got %d, want %d", decoded.CurrentTicket, original.CurrentTicket)
+	if len(decoded.Tickets) != 2 {
+		t.Fatalf("Tickets length: got %d, want 2", len(decoded.Tickets))
 	}
-	if decoded.CurrentTask != original.CurrentTask {
-		t.Errorf("CurrentTask: got %d, want %d", decoded.CurrentTask, original.CurrentTask)
+	got := decoded.Tickets["login-form"]
+	if got.FailingTask != "Add tests" || got.FailureCount != 1 {
+		t.Errorf("failures: got %q x%d, want \"Add tests\" x1", got.FailingTask, got.FailureCount)
 	}
-	if decoded.FailureCount != original.FailureCount {
-		t.Errorf("FailureCount: got %d, want %d", decoded.FailureCount, original.FailureCount)
+	if len(decoded.Tickets["dashboard"].Done) != 2 {
+		t.Errorf("dashboard Done: got %q, want 2 tasks", decoded.Tickets["dashboard"].Done)
 	}
 }
 
 func TestState_ZeroValue(t *testing.T) {
 	var s State
-	if s.CurrentTicket != 0 {
-		t.Errorf("CurrentTicket zero value: got %d, want 0", s.CurrentTicket)
+	if s.Tickets != nil {
+		t.Errorf("Tickets zero value: got %v, want nil", s.Tickets)
 	}
-	if s.CurrentTask != 0 {
-		t.Errorf("CurrentTask zero value: got %d, want 0", s.CurrentTask)
-	}
-	if s.FailureCount != 0 {
-		t.Errorf("FailureCount zero value: got %d, want 0", s.FailureCount)
+	if got := s.Tickets["missing"]; len(got.Done) != 0 || got.FailureCount != 0 {
+		t.Errorf("missing ticket: got %+v, want zero value", got)
 	}
 }
 
-func TestState_YAMLTags(t *testing.T) {
-	yamlData := `current_ticket: 3
+func TestState_LegacyYAML(t *testing.T) {
+	yamlData := `current_ticket: 0
 current_task: 7
 failure_count: 2
 `
@@ -57,13 +57,41 @@ failure_count: 2
 		t.Fatalf("unmarshal error: %v", err)
 	}
 
-	if s.CurrentTicket != 3 {
-		t.Errorf("CurrentTicket: got %d, want 3", s.CurrentTicket)
+	if s.LegacyTicket == nil || *s.LegacyTicket != 0 {
+		t.Errorf("LegacyTicket: got %v, want 0", s.LegacyTicket)
+	}
+	if s.LegacyTask != 7 || s.LegacyFailures != 2 {
+		t.Errorf("legacy cursor: got task %d x%d, want task 7 x2", s.LegacyTask, s.LegacyFailures)
 	}
-	if s.CurrentTask != 7 {
-		t.Errorf("CurrentTask: got %d, want 7", s.CurrentTask)
+}
+
+func TestState_DoneYAML(t *testing.T) {
+	yamlData := `tickets:
+  feature-x:
+    done:
+      - Create form
+      - add-tests
+    failing_task: Add OAuth
+    failure_count: 2
+`
+	var s State
+	if err := yaml.Unmarshal([]byte(yamlData), &s); err != nil {
+		t.Fatalf("unmarshal error: %v", err)
+	}
+
+	got := s.Tickets["feature-x"]
+	if len(got.Done) != 2 || got.Done[1] != "add-tests" {
+		t.Errorf("Done: got %q, want [Create form add-tests]", got.Done)
+	}
+	if got.FailingTask != "Add OAuth" || got.FailureCount != 2 {
+		t.Errorf("failures: got %q x%d, want \"Add OAuth\" x2", got.FailingTask, got.FailureCount)
+	}
+
+	data, err := yaml.Marshal(&s)
+	if err != nil {
+		t.Fatalf("marshal error: %v", err)
 	}
-	if s.FailureCount != 2 {
-		t.Errorf("FailureCount: got %d, want 2", s.FailureCount)
+	if strings.Contains(string(data), "current_task") {
+		t.Errorf("marshaled state should omit the unset legacy cursor, got:\n%s", data)
 	}
 }
@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Transcript event kinds parsed from the agent's stream-json output.
+const (
+	TranscriptSystem     = "system"      // Session metadata such as the init message; Text holds the subtype
+	TranscriptText       = "text"        // Assistant text
+	TranscriptToolUse    = "tool_use"    // Tool call; Tool, ToolID and Input
+	TranscriptToolResult = "tool_result" // Tool output; ToolID, Text and IsError
+	TranscriptResult     = "result"      // Final result of the session
+	TranscriptOutput     = "output"      // A line that is not stream-json, kept verbatim
+	TranscriptProgress   = "progress"    // A report_progress call; Text and Percent
+)
+
+// TranscriptEvent is one line of an attempt transcript stored in
+// .kamaji/transcripts/<ticket>/<task>-<attempt>.jsonl.
+type TranscriptEvent struct {
+	Time       time.Time       `json:"time"`
+	Kind       string          `json:"kind"`
+	Text       string          `json:"text,omitempty"`
+	Tool       string          `json:"tool,omitempty"`
+	ToolID     string          `json:"tool_id,omitempty"`
+	Input      json.RawMessage `json:"input,omitempty"`
+	IsError    bool            `json:"is_error,omitempty"`
+	SessionID  string          `json:"session_id,omitempty"`
+	Model      string          `json:"model,omitempty"`
+	NumTurns   int             `json:"num_turns,omitempty"`
+	DurationMS int64           `json:"duration_ms,omitempty"`
+	Usage      *Usage          `json:"usage,omitempty"` // Set on the final result
+	Percent    *int            `json:"percent,omitempty"`
+}
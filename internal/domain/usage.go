@@ -0,0 +1,52 @@
+package domain
+
+// Usage is the token usage and cost an agent session reported in its final
+// stream-json result.
+type Usage struct {
+	InputTokens         int64   `yaml:"input_tokens,omitempty" json:"input_tokens"`
+	OutputTokens        int64   `yaml:"output_tokens,omitempty" json:"output_tokens"`
+	CacheCreationTokens int64   `yaml:"cache_creation_tokens,omitempty" json:"cache_creation_tokens"`
+	CacheReadTokens     int64   `yaml:"cache_read_tokens,omitempty" json:"cache_read_tokens"`
+	CostUSD             float64 `yaml:"cost_usd,omitempty" json:"cost_usd"`
+}
+
+// Add accumulates other into u.
+func (u *Usage) Add(other Usage) {
+	u.InputTokens += other.InputTokens
+	u.OutputTokens += other.OutputTokens
+	u.CacheCreationTokens += other.CacheCreationTokens
+	u.CacheReadTokens += other.CacheReadTokens
+	u.CostUSD += other.CostUSD
+}
+
+// Tokens returns all tokens, including cache writes and reads.
+func (u Usage) Tokens() int64 {
+	return u.InputTokens + u.OutputTokens + u.CacheCreationTokens + u.CacheReadTokens
+}
+
+// IsZero reports whether no usage was recorded.
+func (u Usage) IsZero() bool {
+	return u == Usage{}
+}
+
+// TaskStats is the usage of every attempt at one task.
+type TaskStats struct {
+	Task     string `json:"task"`
+	Attempts int    `json:"attempts"`
+	Usage    Usage  `json:"usage"`
+}
+
+// TicketStats is the usage of a ticket's attempts, per task.
+type TicketStats struct {
+	Ticket   string      `json:"ticket"`
+	Attempts int         `json:"attempts"`
+	Usage    Usage       `json:"usage"`
+	Tasks    []TaskStats `json:"tasks"`
+}
+
+// SprintStats is the usage of every attempt in the sprint, per ticket.
+type SprintStats struct {
+	Attempts int           `json:"attempts"`
+	Usage    Usage         `json:"usage"`
+	Tickets  []TicketStats `json:"tickets"`
+}
@@ -0,0 +1,23 @@
+package domain
+
+import "testing"
+
+func TestUsage_AddAndTokens(t *testing.T) {
+	var u Usage
+	if !u.IsZero() {
+		t.Error("IsZero() = false for zero value")
+	}
+
+	u.Add(Usage{InputTokens: 1, OutputTokens: 2, CacheCreationTokens: 3, CacheReadTokens: 4, CostUSD: 0.25})
+	u.Add(Usage{InputTokens: 10, CostUSD: 0.5})
+
+	if got := u.Tokens(); got != 20 {
+		t.Errorf("Tokens() = %d, want 20", got)
+	}
+	if u.CostUSD != 0.75 {
+		t.Errorf("CostUSD = %v, want 0.75", u.CostUSD)
+	}
+	if u.IsZero() {
+		t.Error("IsZero() = true after Add")
+	}
+}
@@ -4,7 +4,10 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"os"
 	"os/exec"
+	"path/filepath"
+	"strings"
 )
 
 // ErrNothingToCommit is returned when CommitChanges is called with no staged changes.
@@ -14,6 +17,10 @@ var ErrNothingToCommit = errors.New("nothing to commit")
 // Use errors.Is to check for this error type.
 var ErrBranchExists = errors.New("branch already exists")
 
+// ErrDirtyWorktree is returned when tracked files have uncommitted changes
+// that a checkout would carry over to another branch.
+var ErrDirtyWorktree = errors.New("uncommitted changes in the working tree")
+
 // runGit executes a git command in the specified directory.
 //
 //nolint:unparam // stdout will be used by future operations
@@ -85,7 +92,39 @@ func CreateBranch(workDir, baseBranch, ticketBranch string) error {
 	return nil
 }
 
-// CommitChanges stages all changes and commits with the provided message.
+// CheckoutBranch switches workDir to an existing branch. It is a no-op when
+// the branch is already checked out, and returns ErrDirtyWorktree rather than
+// carry uncommitted changes to tracked files over to it. Untracked files are
+// left alone, as git checkout keeps them.
+func CheckoutBranch(workDir, branch string) error {
+	if workDir == "" {
+		return errors.New("workDir required")
+	}
+	if branch == "" {
+		return errors.New("branch required")
+	}
+
+	current, _, err := runGit(workDir, "symbolic-ref", "--quiet", "--short", "HEAD")
+	if err == nil && strings.TrimSpace(current) == branch {
+		return nil
+	}
+
+	status, stderr, err := runGit(workDir, "status", "--porcelain", "--untracked-files=no")
+	if err != nil {
+		return fmt.Errorf("git status (%s): %w", stderr, err)
+	}
+	if strings.TrimSpace(status) != "" {
+		return fmt.Errorf("%w: commit or stash them before checking out %s", ErrDirtyWorktree, branch)
+	}
+
+	_, stderr, err = runGit(workDir, "checkout", branch)
+	if err != nil {
+		return fmt.Errorf("checkout %s (%s): %w", branch, stderr, err)
+	}
+	return nil
+}
+
+// CommitChanges stages all changes except .kamaji runtime state and commits with the provided message.
 func CommitChanges(workDir, message string) error {
 	if workDir == "" {
 		return errors.New("workDir required")
@@ -94,19 +133,18 @@ func CommitChanges(workDir, message string) error {
 		return errors.New("commit message required")
 	}
 
-	_, stderr, err := runGit(workDir, "add", "-A")
-	if err != nil {
-		return fmt.Errorf("git add (%s): %w", stderr, err)
+	if err := stageAll(workDir); err != nil {
+		return err
 	}
 
 	// Check if anything is staged
-	_, _, err = runGit(workDir, "diff", "--cached", "--quiet")
+	_, _, err := runGit(workDir, "diff", "--cached", "--quiet")
 	if err == nil {
 		// Exit 0 means no differences (nothing staged)
 		return ErrNothingToCommit
 	}
 
-	_, stderr, err = runGit(workDir, "commit", "-m", message)
+	_, stderr, err := runGit(workDir, "commit", "-m", message)
 	if err != nil {
 		return fmt.Errorf("git commit (%s): %w", stderr, err)
 	}
@@ -114,6 +152,32 @@ func CommitChanges(workDir, message string) error {
 	return nil
 }
 
+// StagedDiff stages all changes except .kamaji runtime state, as CommitChanges
+// would, and returns the diff of what would be committed.
+func StagedDiff(workDir string) (string, error) {
+	if workDir == "" {
+		return "", errors.New("workDir required")
+	}
+
+	if err := stageAll(workDir); err != nil {
+		return "", err
+	}
+
+	diff, stderr, err := runGit(workDir, "diff", "--cached")
+	if err != nil {
+		return "", fmt.Errorf("git diff (%s): %w", stderr, err)
+	}
+	return diff, nil
+}
+
+func stageAll(workDir string) error {
+	_, stderr, err := runGit(workDir, "add", "-A", "--", ":/", ":(exclude).kamaji")
+	if err != nil {
+		return fmt.Errorf("git add (%s): %w", stderr, err)
+	}
+	return nil
+}
+
 // ResetToHead discards all uncommitted changes and removes untracked files.
 func ResetToHead(workDir string) error {
 	if workDir == "" {
@@ -133,3 +197,130 @@ func ResetToHead(workDir string) error {
 
 	return nil
 }
+
+// AddWorktree checks out ticketBranch in a worktree at path, creating the branch
+// from baseBranch when it does not exist yet. An existing worktree at path is
+// reused so interrupted runs can resume. Returns true when the branch was created.
+func AddWorktree(workDir, path, baseBranch, ticketBranch string) (bool, error) {
+	if workDir == "" {
+		return false, errors.New("workDir required")
+	}
+	if path == "" {
+		return false, errors.New("path required")
+	}
+	if baseBranch == "" {
+		return false, errors.New("baseBranch required")
+	}
+	if ticketBranch == "" {
+		return false, errors.New("ticketBranch required")
+	}
+
+	if _, err := os.Stat(filepath.Join(path, ".git")); err == nil {
+		return false, nil
+	}
+
+	exists, err := BranchExists(workDir, ticketBranch)
+	if err != nil {
+		return false, err
+	}
+
+	args := []string{"worktree", "add", path, ticketBranch}
+	if !exists {
+		args = []string{"worktree", "add", "-b", ticketBranch, path, baseBranch}
+	}
+
+	_, stderr, err := runGit(workDir, args...)
+	if err != nil {
+		return false, fmt.Errorf("add worktree %s (%s): %w", path, stderr, err)
+	}
+
+	return !exists, nil
+}
+
+// RemoveWorktree deletes the worktree at path. The checked out branch is kept.
+func RemoveWorktree(workDir, path string) error {
+	if workDir == "" {
+		return errors.New("workDir required")
+	}
+	if path == "" {
+		return errors.New("path required")
+	}
+
+	_, stderr, err := runGit(workDir, "worktree", "remove", "--force", path)
+	if err != nil {
+		return fmt.Errorf("remove worktree %s (%s): %w", path, stderr, err)
+	}
+
+	return nil
+}
+
+// MergeBranch merges branch into the currently checked out branch.
+// A conflicting merge is aborted so the working tree is left clean.
+func MergeBranch(workDir, branch string) error {
+	if workDir == "" {
+		return errors.New("workDir required")
+	}
+	if branch == "" {
+		return errors.New("branch required")
+	}
+
+	stdout, stderr, err := runGit(workDir, "merge", "--no-edit", branch)
+	if err != nil {
+		// Git reports conflicts on stdout, so both streams explain the failure.
+		detail := joinLines(stdout + "\n" + stderr)
+		conflicts, _, _ := runGit(workDir, "diff", "--name-only", "--diff-filter=U")
+		_, _, _ = runGit(workDir, "merge", "--abort")
+		if paths := strings.Fields(conflicts); len(paths) > 0 {
+			return fmt.Errorf("merge %s: conflicts in %s (%s): %w", branch, strings.Join(paths, ", "), detail, err)
+		}
+		return fmt.Errorf("merge %s (%s): %w", branch, detail, err)
+	}
+
+	return nil
+}
+
+// joinLines puts git's multi-line output on one line for an error message.
+func joinLines(s string) string {
+	var lines []string
+	for line := range strings.Lines(s) {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "; ")
+}
+
+// IsAncestor reports whether commit is reachable from HEAD, such as a branch
+// that has already been merged.
+func IsAncestor(workDir, commit string) (bool, error) {
+	if workDir == "" {
+		return false, errors.New("workDir required")
+	}
+	if commit == "" {
+		return false, errors.New("commit required")
+	}
+
+	_, stderr, err := runGit(workDir, "merge-base", "--is-ancestor", commit, "HEAD")
+	if err != nil {
+		// Exit status 1 means not an ancestor; anything else is a failure.
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+			return false, nil
+		}
+		return false, fmt.Errorf("git merge-base %s (%s): %w", commit, strings.TrimSpace(stderr), err)
+	}
+	return true, nil
+}
+
+// HeadCommit returns the full SHA of HEAD.
+func HeadCommit(workDir string) (string, error) {
+	if workDir == "" {
+		return "", errors.New("workDir required")
+	}
+
+	stdout, stderr, err := runGit(workDir, "rev-parse", "HEAD")
+	if err != nil {
+		return "", fmt.Errorf("git rev-parse (%s): %w", stderr, err)
+	}
+	return strings.TrimSpace(stdout), nil
+}
@@ -1,6 +1,7 @@
 package git
 
 import (
+	"errors"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -224,3 +225,306 @@ func TestResetToHead_NotAGitRepo(t *testing.T) {
 		t.Error("ResetToHead() error = nil, want error for non-git directory")
 	}
 }
+
+func TestCommitChanges_ExcludesKamajiDir(t *testing.T) {
+	dir := t.TempDir()
+	testutil.InitGitRepo(t, dir)
+
+	kamajiDir := testutil.SetupKamajiDir(t, dir)
+	if err := os.WriteFile(filepath.Join(kamajiDir, "state.yaml"), []byte("tickets: {}\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "feature.txt"), []byte("feature\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := CommitChanges(dir, "feat: add feature"); err != nil {
+		t.Fatalf("CommitChanges() error = %v", err)
+	}
+
+	out, _, err := runGit(dir, "show", "--name-only", "--format=", "HEAD")
+	if err != nil {
+		t.Fatalf("git show failed: %v", err)
+	}
+	if !strings.Contains(out, "feature.txt") {
+		t.Errorf("commit should contain feature.txt, got: %q", out)
+	}
+	if strings.Contains(out, ".kamaji") {
+		t.Errorf("commit should not contain .kamaji files, got: %q", out)
+	}
+}
+
+func TestStagedDiff_ExcludesKamajiDir(t *testing.T) {
+	dir := t.TempDir()
+	testutil.InitGitRepo(t, dir)
+
+	kamajiDir := testutil.SetupKamajiDir(t, dir)
+	if err := os.WriteFile(filepath.Join(kamajiDir, "state.yaml"), []byte("tickets: {}\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "feature.txt"), []byte("feature\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	diff, err := StagedDiff(dir)
+	if err != nil {
+		t.Fatalf("StagedDiff() error = %v", err)
+	}
+	if !strings.Contains(diff, "+feature") {
+		t.Errorf("diff should contain the new file, got: %q", diff)
+	}
+	if strings.Contains(diff, ".kamaji") {
+		t.Errorf("diff should not contain .kamaji files, got: %q", diff)
+	}
+}
+
+// Worktree tests
+
+func TestAddWorktree_CreatesBranch(t *testing.T) {
+	dir := t.TempDir()
+	testutil.InitGitRepo(t, dir)
+	path := filepath.Join(t.TempDir(), "wt")
+
+	created, err := AddWorktree(dir, path, "main", "feature/wt")
+	if err != nil {
+		t.Fatalf("AddWorktree() error = %v", err)
+	}
+	if !created {
+		t.Error("created = false, want true for new branch")
+	}
+
+	out, _, err := runGit(path, "branch", "--show-current")
+	if err != nil {
+		t.Fatalf("git branch failed: %v", err)
+	}
+	if strings.TrimSpace(out) != "feature/wt" {
+		t.Errorf("worktree branch = %q, want %q", strings.TrimSpace(out), "feature/wt")
+	}
+
+	// Main checkout stays on its branch
+	out, _, _ = runGit(dir, "branch", "--show-current")
+	if strings.TrimSpace(out) != "main" {
+		t.Errorf("main branch = %q, want %q", strings.TrimSpace(out), "main")
+	}
+}
+
+func TestAddWorktree_ReusesExisting(t *testing.T) {
+	dir := t.TempDir()
+	testutil.InitGitRepo(t, dir)
+	path := filepath.Join(t.TempDir(), "wt")
+
+	if _, err := AddWorktree(dir, path, "main", "feature/wt"); err != nil {
+		t.Fatalf("AddWorktree() error = %v", err)
+	}
+
+	created, err := AddWorktree(dir, path, "main", "feature/wt")
+	if err != nil {
+		t.Fatalf("AddWorktree() second call error = %v", err)
+	}
+	if created {
+		t.Error("created = true, want false when reusing worktree")
+	}
+}
+
+func TestAddWorktree_ExistingBranch(t *testing.T) {
+	dir := t.TempDir()
+	testutil.InitGitRepo(t, dir, "feature/existing")
+	path := filepath.Join(t.TempDir(), "wt")
+
+	created, err := AddWorktree(dir, path, "main", "feature/existing")
+	if err != nil {
+		t.Fatalf("AddWorktree() error = %v", err)
+	}
+	if created {
+		t.Error("created = true, want false for existing branch")
+	}
+}
+
+func TestRemoveWorktree_KeepsBranch(t *testing.T) {
+	dir := t.TempDir()
+	testutil.InitGitRepo(t, dir)
+	path := filepath.Join(t.TempDir(), "wt")
+
+	if _, err := AddWorktree(dir, path, "main", "feature/wt"); err != nil {
+		t.Fatalf("AddWorktree() error = %v", err)
+	}
+	if err := RemoveWorktree(dir, path); err != nil {
+		t.Fatalf("RemoveWorktree() error = %v", err)
+	}
+
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("worktree directory should be removed")
+	}
+	exists, err := BranchExists(dir, "feature/wt")
+	if err != nil {
+		t.Fatalf("BranchExists() error = %v", err)
+	}
+	if !exists {
+		t.Error("branch should survive worktree removal")
+	}
+}
+
+func TestMergeBranch_BringsInCommits(t *testing.T) {
+	dir := t.TempDir()
+	testutil.InitGitRepo(t, dir)
+
+	if err := CreateBranch(dir, "main", "feature/dep"); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "dep.txt"), []byte("dep\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := CommitChanges(dir, "feat: dependency"); err != nil {
+		t.Fatal(err)
+	}
+	if err := CreateBranch(dir, "main", "feature/dependent"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := MergeBranch(dir, "feature/dep"); err != nil {
+		t.Fatalf("MergeBranch() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "dep.txt")); err != nil {
+		t.Error("merged file dep.txt should exist")
+	}
+}
+
+func TestMergeBranch_MissingBranch(t *testing.T) {
+	dir := t.TempDir()
+	testutil.InitGitRepo(t, dir)
+
+	if err := MergeBranch(dir, "nonexistent"); err == nil {
+		t.Error("MergeBranch() error = nil, want error for missing branch")
+	}
+}
+
+func TestMergeBranch_ConflictNamesPaths(t *testing.T) {
+	dir := t.TempDir()
+	testutil.InitGitRepo(t, dir)
+
+	for _, branch := range []string{"feature/dep", "feature/dependent"} {
+		if err := CreateBranch(dir, "main", branch); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "shared.txt"), []byte(branch+"\n"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if err := CommitChanges(dir, "feat: "+branch); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	err := MergeBranch(dir, "feature/dep")
+	if err == nil {
+		t.Fatal("MergeBranch() error = nil, want conflict")
+	}
+	if !strings.Contains(err.Error(), "conflicts in shared.txt") {
+		t.Errorf("MergeBranch() error = %q, want the conflicting path", err)
+	}
+	if !strings.Contains(err.Error(), "CONFLICT") {
+		t.Errorf("MergeBranch() error = %q, want git's conflict report", err)
+	}
+	status := exec.Command("git", "status", "--porcelain")
+	status.Dir = dir
+	if out, err := status.Output(); err != nil || len(out) != 0 {
+		t.Errorf("git status after the aborted merge = %q, %v, want clean", out, err)
+	}
+}
+
+func TestIsAncestor(t *testing.T) {
+	dir := t.TempDir()
+	testutil.InitGitRepo(t, dir)
+
+	if err := CreateBranch(dir, "main", "feature/dep"); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "dep.txt"), []byte("dep\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := CommitChanges(dir, "feat: dependency"); err != nil {
+		t.Fatal(err)
+	}
+	if err := CreateBranch(dir, "main", "feature/dependent"); err != nil {
+		t.Fatal(err)
+	}
+
+	if merged, err := IsAncestor(dir, "feature/dep"); err != nil || merged {
+		t.Errorf("IsAncestor() before merge = %v, %v, want false", merged, err)
+	}
+	if err := MergeBranch(dir, "feature/dep"); err != nil {
+		t.Fatal(err)
+	}
+	if merged, err := IsAncestor(dir, "feature/dep"); err != nil || !merged {
+		t.Errorf("IsAncestor() after merge = %v, %v, want true", merged, err)
+	}
+	if _, err := IsAncestor(dir, "nonexistent"); err == nil {
+		t.Error("IsAncestor() error = nil, want error for missing branch")
+	}
+}
+
+func TestHeadCommit_MatchesLog(t *testing.T) {
+	dir := t.TempDir()
+	testutil.InitGitRepo(t, dir)
+
+	sha, err := HeadCommit(dir)
+	if err != nil {
+		t.Fatalf("HeadCommit() error = %v", err)
+	}
+
+	cmd := exec.Command("git", "log", "-1", "--format=%H")
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("git log failed: %v", err)
+	}
+	if want := strings.TrimSpace(string(out)); sha != want {
+		t.Errorf("HeadCommit() = %q, want %q", sha, want)
+	}
+}
+
+func TestCheckoutBranch_SwitchesBranch(t *testing.T) {
+	dir := t.TempDir()
+	testutil.InitGitRepo(t, dir, "feature/existing")
+
+	if err := CheckoutBranch(dir, "feature/existing"); err != nil {
+		t.Fatalf("CheckoutBranch() error = %v", err)
+	}
+
+	cmd := exec.Command("git", "branch", "--show-current")
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("git branch --show-current failed: %v", err)
+	}
+	if branch := strings.TrimSpace(string(out)); branch != "feature/existing" {
+		t.Errorf("current branch = %q, want %q", branch, "feature/existing")
+	}
+}
+
+func TestCheckoutBranch_DirtyWorktree(t *testing.T) {
+	dir := t.TempDir()
+	testutil.InitGitRepo(t, dir, "feature/existing")
+
+	if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte("changed\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	err := CheckoutBranch(dir, "feature/existing")
+	if !errors.Is(err, ErrDirtyWorktree) {
+		t.Errorf("CheckoutBranch() error = %v, want ErrDirtyWorktree", err)
+	}
+}
+
+func TestCheckoutBranch_AlreadyCheckedOut(t *testing.T) {
+	dir := t.TempDir()
+	testutil.InitGitRepo(t, dir)
+
+	// Changes on the current branch are not carried anywhere, so they are fine.
+	if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte("changed\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := CheckoutBranch(dir, "main"); err != nil {
+		t.Errorf("CheckoutBranch() error = %v, want nil", err)
+	}
+}
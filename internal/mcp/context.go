@@ -0,0 +1,148 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sqve/kamaji/internal/domain"
+)
+
+// Ticket states reported by get_sprint_overview.
+const (
+	TicketDone       = "done"
+	TicketInProgress = "in_progress"
+	TicketPending    = "pending"
+	TicketBlocked    = "blocked" // Waiting on depends_on
+	TicketStuck      = "stuck"
+)
+
+// ContextProvider answers the read-only context tools, which let agents fetch
+// task context on demand instead of receiving all of it in the prompt.
+type ContextProvider interface {
+	// Task returns the task the session is working on.
+	Task() (*TaskView, error)
+	// TicketHistory returns a ticket's history, the session's ticket when
+	// ticket is empty, filtered to one kind of entry when kind is set.
+	TicketHistory(ticket, kind string) (*domain.TicketHistory, error)
+	// SprintOverview returns the progress of every ticket in the sprint.
+	SprintOverview() (*SprintOverview, error)
+	// SearchInsights returns insights from all tickets containing query.
+	SearchInsights(query string) ([]InsightMatch, error)
+}
+
+// TaskView is the get_task result.
+type TaskView struct {
+	Ticket            string   `json:"ticket"`
+	Branch            string   `json:"branch"`
+	TicketDescription string   `json:"ticket_description,omitempty"`
+	Task              string   `json:"task"`
+	Steps             []string `json:"steps,omitempty"`
+	Verify            string   `json:"verify,omitempty"`
+	VerifyCmd         string   `json:"verify_cmd,omitempty"`
+	Rules             []string `json:"rules,omitempty"`
+	Attempt           int      `json:"attempt"` // 1-based
+	MaxAttempts       int      `json:"max_attempts"`
+}
+
+// SprintOverview is the get_sprint_overview result.
+type SprintOverview struct {
+	Sprint  string           `json:"sprint"`
+	Tickets []TicketOverview `json:"tickets"`
+}
+
+// TicketOverview is one ticket's progress in a SprintOverview.
+type TicketOverview struct {
+	Name        string   `json:"name"`
+	Branch      string   `json:"branch"`
+	Description string   `json:"description,omitempty"`
+	Status      string   `json:"status"` // One of the Ticket state constants
+	TasksDone   int      `json:"tasks_done"`
+	TasksTotal  int      `json:"tasks_total"`
+	DependsOn   []string `json:"depends_on,omitempty"`
+}
+
+// InsightMatch is one search_insights result.
+type InsightMatch struct {
+	Ticket  string `json:"ticket"`
+	Insight string `json:"insight"`
+}
+
+// WithContextProvider registers the read-only context tools, answered by p.
+func WithContextProvider(p ContextProvider) Option {
+	return func(s *Server) {
+		s.provider = p
+	}
+}
+
+type GetTicketHistoryArgs struct {
+	Ticket string `json:"ticket"`
+	Type   string `json:"type"`
+}
+
+type SearchInsightsArgs struct {
+	Query string `json:"query"`
+}
+
+func (s *Server) handleGetTask(_ context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	task, err := s.provider.Task()
+	return jsonResult(task, err), nil
+}
+
+func (s *Server) handleGetTicketHistory(_ context.Context, _ mcp.CallToolRequest, args GetTicketHistoryArgs) (*mcp.CallToolResult, error) {
+	history, err := s.provider.TicketHistory(args.Ticket, args.Type)
+	return jsonResult(history, err), nil
+}
+
+func (s *Server) handleGetSprintOverview(_ context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	overview, err := s.provider.SprintOverview()
+	return jsonResult(overview, err), nil
+}
+
+func (s *Server) handleSearchInsights(_ context.Context, _ mcp.CallToolRequest, args SearchInsightsArgs) (*mcp.CallToolResult, error) {
+	if args.Query == "" {
+		return mcp.NewToolResultError("query is required"), nil
+	}
+	matches, err := s.provider.SearchInsights(args.Query)
+	if matches == nil {
+		matches = []InsightMatch{}
+	}
+	return jsonResult(matches, err), nil
+}
+
+func (s *Server) registerContextTools() {
+	s.mcpServer.AddTool(mcp.NewTool("get_task",
+		mcp.WithDescription("Get the current task with its steps, verify criteria, rules and attempt"),
+		mcp.WithReadOnlyHintAnnotation(true),
+	), s.handleGetTask)
+
+	s.mcpServer.AddTool(mcp.NewTool("get_ticket_history",
+		mcp.WithDescription("Get a ticket's completed tasks, failed attempts, insights, plans, questions and manual actions"),
+		mcp.WithString("ticket", mcp.Description("ticket name, defaults to the current ticket")),
+		mcp.WithString("type", mcp.Description("only completed, failed, insights, plans, questions or manual entries")),
+		mcp.WithReadOnlyHintAnnotation(true),
+	), mcp.NewTypedToolHandler(s.handleGetTicketHistory))
+
+	s.mcpServer.AddTool(mcp.NewTool("get_sprint_overview",
+		mcp.WithDescription("Get the status and task progress of every ticket in the sprint"),
+		mcp.WithReadOnlyHintAnnotation(true),
+	), s.handleGetSprintOverview)
+
+	s.mcpServer.AddTool(mcp.NewTool("search_insights",
+		mcp.WithDescription("Search the insights recorded on all tickets"),
+		mcp.WithString("query", mcp.Required(), mcp.Description("text to look for, case-insensitive")),
+		mcp.WithReadOnlyHintAnnotation(true),
+	), mcp.NewTypedToolHandler(s.handleSearchInsights))
+}
+
+// jsonResult returns v as JSON text, or err as a tool error.
+func jsonResult(v any, err error) *mcp.CallToolResult {
+	if err != nil {
+		return mcp.NewToolResultError(err.Error())
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error())
+	}
+	return mcp.NewToolResultText(string(data))
+}
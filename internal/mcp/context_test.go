@@ -0,0 +1,121 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sqve/kamaji/internal/domain"
+)
+
+type fakeProvider struct {
+	query string
+}
+
+func (fakeProvider) Task() (*TaskView, error) {
+	return &TaskView{Ticket: "login", Task: "Add form", Attempt: 2, MaxAttempts: 3}, nil
+}
+
+func (fakeProvider) TicketHistory(ticket, _ string) (*domain.TicketHistory, error) {
+	if ticket == "missing" {
+		return nil, errors.New(`unknown ticket "missing"`)
+	}
+	return &domain.TicketHistory{Ticket: "login", Insights: []string{"Uses Zustand"}}, nil
+}
+
+func (fakeProvider) SprintOverview() (*SprintOverview, error) {
+	return &SprintOverview{Sprint: "s", Tickets: []TicketOverview{{Name: "login", Status: TicketInProgress, TasksTotal: 2}}}, nil
+}
+
+func (p *fakeProvider) SearchInsights(query string) ([]InsightMatch, error) {
+	p.query = query
+	return nil, nil
+}
+
+func newContextClient(t *testing.T, p ContextProvider, opts ...Option) *client.Client {
+	t.Helper()
+	s := NewServer(append(opts, WithContextProvider(p))...)
+	c, err := client.NewInProcessClient(s.mcpServer)
+	if err != nil {
+		t.Fatalf("NewInProcessClient() error = %v", err)
+	}
+	t.Cleanup(func() { _ = c.Close() })
+
+	_, err = c.Initialize(context.Background(), mcp.InitializeRequest{
+		Params: mcp.InitializeParams{
+			ProtocolVersion: "2024-11-05",
+			ClientInfo:      mcp.Implementation{Name: "test-client", Version: "1.0.0"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+	return c
+}
+
+func callTool(t *testing.T, c *client.Client, name string, args map[string]any) *mcp.CallToolResult {
+	t.Helper()
+	result, err := c.CallTool(context.Background(), mcp.CallToolRequest{
+		Params: mcp.CallToolParams{Name: name, Arguments: args},
+	})
+	if err != nil {
+		t.Fatalf("CallTool(%s) error = %v", name, err)
+	}
+	return result
+}
+
+func resultText(result *mcp.CallToolResult) string {
+	return result.Content[0].(mcp.TextContent).Text
+}
+
+func TestServer_ContextTools(t *testing.T) {
+	p := &fakeProvider{}
+	c := newContextClient(t, p)
+
+	var task TaskView
+	if err := json.Unmarshal([]byte(resultText(callTool(t, c, "get_task", nil))), &task); err != nil {
+		t.Fatalf("get_task result: %v", err)
+	}
+	if task.Task != "Add form" || task.Attempt != 2 {
+		t.Errorf("get_task = %+v, want task %q attempt 2", task, "Add form")
+	}
+
+	history := resultText(callTool(t, c, "get_ticket_history", map[string]any{"type": "insights"}))
+	if !strings.Contains(history, `"insights":["Uses Zustand"]`) {
+		t.Errorf("get_ticket_history = %s, want the insight", history)
+	}
+
+	overview := resultText(callTool(t, c, "get_sprint_overview", nil))
+	if !strings.Contains(overview, `"status":"in_progress"`) {
+		t.Errorf("get_sprint_overview = %s, want the ticket status", overview)
+	}
+
+	if got := resultText(callTool(t, c, "search_insights", map[string]any{"query": "zustand"})); got != "[]" {
+		t.Errorf("search_insights = %s, want []", got)
+	}
+	if p.query != "zustand" {
+		t.Errorf("search query = %q, want %q", p.query, "zustand")
+	}
+}
+
+func TestServer_ContextToolErrors(t *testing.T) {
+	c := newContextClient(t, &fakeProvider{})
+
+	if result := callTool(t, c, "get_ticket_history", map[string]any{"ticket": "missing"}); !result.IsError {
+		t.Error("get_ticket_history(missing) IsError = false, want true")
+	}
+	if result := callTool(t, c, "search_insights", map[string]any{"query": ""}); !result.IsError {
+		t.Error("search_insights(empty) IsError = false, want true")
+	}
+}
+
+func TestServer_ContextToolsNeedProvider(t *testing.T) {
+	s := NewServer()
+	if s.mcpServer.GetTool("get_task") != nil {
+		t.Error("get_task registered without a context provider")
+	}
+}
@@ -0,0 +1,57 @@
+package mcp
+
+import (
+	"context"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/sqve/kamaji/internal/domain"
+)
+
+// TaskProposer queues the follow-up tasks agents suggest with propose_task
+// for a human to accept or reject. It returns the proposal's id.
+type TaskProposer interface {
+	ProposeTask(args ProposeTaskArgs) (int, error)
+}
+
+// WithTaskProposer registers propose_task, queueing proposals with p.
+func WithTaskProposer(p TaskProposer) Option {
+	return func(s *Server) {
+		s.proposer = p
+	}
+}
+
+type ProposeTaskArgs struct {
+	Ticket      string   `json:"ticket"` // Empty for the current ticket
+	Description string   `json:"description"`
+	Steps       []string `json:"steps"`
+	Verify      string   `json:"verify"`
+	Reason      string   `json:"reason"`
+}
+
+type ProposeTaskResult struct {
+	ID     int    `json:"id"`
+	Status string `json:"status"`
+}
+
+func (s *Server) handleProposeTask(_ context.Context, _ mcp.CallToolRequest, args ProposeTaskArgs) (*mcp.CallToolResult, error) {
+	if strings.TrimSpace(args.Description) == "" {
+		return mcp.NewToolResultError("description is required"), nil
+	}
+	if strings.TrimSpace(args.Reason) == "" {
+		return mcp.NewToolResultError("reason is required"), nil
+	}
+	id, err := s.proposer.ProposeTask(args)
+	return jsonResult(ProposeTaskResult{ID: id, Status: domain.ProposalPending}, err), nil
+}
+
+func (s *Server) registerProposeTask() {
+	s.mcpServer.AddTool(mcp.NewTool("propose_task",
+		mcp.WithDescription("Propose a follow-up task found while working, queued for a human to accept into the sprint"),
+		mcp.WithString("ticket", mcp.Description("ticket the task belongs to, defaults to the current ticket")),
+		mcp.WithString("description", mcp.Required(), mcp.Description("what the task should do")),
+		mcp.WithArray("steps", mcp.WithStringItems(), mcp.Description("steps to take, in order")),
+		mcp.WithString("verify", mcp.Description("how to tell the task is done")),
+		mcp.WithString("reason", mcp.Required(), mcp.Description("why the work is needed and why it is outside the current task")),
+	), mcp.NewTypedToolHandler(s.handleProposeTask))
+}
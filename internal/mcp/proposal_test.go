@@ -0,0 +1,58 @@
+package mcp
+
+import (
+	"errors"
+	"testing"
+)
+
+type fakeProposer struct {
+	got ProposeTaskArgs
+}
+
+func (p *fakeProposer) ProposeTask(args ProposeTaskArgs) (int, error) {
+	if args.Ticket == "missing" {
+		return 0, errors.New(`unknown ticket "missing"`)
+	}
+	p.got = args
+	return 3, nil
+}
+
+func TestServer_ProposeTask(t *testing.T) {
+	p := &fakeProposer{}
+	c := newContextClient(t, &fakeProvider{}, WithTaskProposer(p))
+
+	result := callTool(t, c, "propose_task", map[string]any{
+		"description": "Add rate limiting",
+		"steps":       []string{"Limit by IP", "Return 429"},
+		"reason":      "Login has no throttling",
+	})
+	if got := resultText(result); got != `{"id":3,"status":"pending"}` {
+		t.Errorf("propose_task = %s, want id 3 pending", got)
+	}
+	if len(p.got.Steps) != 2 || p.got.Reason != "Login has no throttling" {
+		t.Errorf("proposed args = %+v", p.got)
+	}
+
+	tests := []struct {
+		name string
+		args map[string]any
+	}{
+		{"blank description", map[string]any{"description": " ", "reason": "Why"}},
+		{"missing reason", map[string]any{"description": "Add docs"}},
+		{"unknown ticket", map[string]any{"ticket": "missing", "description": "Add docs", "reason": "Why"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := callTool(t, c, "propose_task", tt.args); !result.IsError {
+				t.Error("propose_task IsError = false, want true")
+			}
+		})
+	}
+}
+
+func TestServer_ProposeTaskNeedsProposer(t *testing.T) {
+	s := NewServer()
+	if s.mcpServer.GetTool("propose_task") != nil {
+		t.Error("propose_task registered without a proposer")
+	}
+}
@@ -0,0 +1,74 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Question is a pending ask_human call. The agent's tool call blocks until
+// the question is answered or declined, or the call is cancelled.
+type Question struct {
+	Text  string
+	reply chan questionReply
+}
+
+type questionReply struct {
+	answer  string
+	decline string // Why no answer was given, set instead of answer
+}
+
+// Answer returns text to the agent as the tool result.
+func (q Question) Answer(text string) {
+	q.reply <- questionReply{answer: text}
+}
+
+// Decline tells the agent no answer is coming, and why.
+func (q Question) Decline(reason string) {
+	q.reply <- questionReply{decline: reason}
+}
+
+type AskHumanArgs struct {
+	Question string `json:"question"`
+}
+
+type AskHumanResult struct {
+	Answer string `json:"answer"`
+}
+
+// Questions returns a receive-only channel of ask_human calls waiting for an
+// answer. Each must be answered or declined.
+func (s *Server) Questions() <-chan Question {
+	return s.questions
+}
+
+func (s *Server) handleAskHuman(ctx context.Context, _ mcp.CallToolRequest, args AskHumanArgs) (*mcp.CallToolResult, error) {
+	if strings.TrimSpace(args.Question) == "" {
+		return mcp.NewToolResultError("question is required"), nil
+	}
+
+	q := Question{Text: args.Question, reply: make(chan questionReply, 1)}
+	select {
+	case s.questions <- q:
+	case <-sessionEnded(ctx):
+		return mcp.NewToolResultError(errUndelivered), nil
+	case <-ctx.Done():
+		return mcp.NewToolResultError("question cancelled"), nil
+	}
+
+	select {
+	case r := <-q.reply:
+		if r.decline != "" {
+			return mcp.NewToolResultError(r.decline), nil
+		}
+		data, err := json.Marshal(AskHumanResult{Answer: r.answer})
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		return mcp.NewToolResultText(string(data)), nil
+	case <-ctx.Done():
+		return mcp.NewToolResultError("question cancelled"), nil
+	}
+}
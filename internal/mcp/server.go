@@ -4,10 +4,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"log/slog"
+	"math"
 	"net"
 	"net/http"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
@@ -23,7 +24,14 @@ type Server struct {
 	httpServer   *http.Server
 	started      bool
 	signals      chan Signal
+	sendMu       sync.RWMutex  // Read-held while a handler sends, so signals is never closed under it
+	stopped      chan struct{} // Closed on shutdown to release handlers waiting to send
+	questions    chan Question // Unbuffered so only a waiting session takes a question
 	closeSignals sync.Once
+	provider     ContextProvider         // Answers the read-only context tools when set
+	proposer     TaskProposer            // Queues propose_task proposals when set
+	session      atomic.Pointer[session] // The agent session being accepted, nil between sessions
+	sessions     atomic.Uint64           // Id of the latest session
 }
 
 type Option func(*Server)
@@ -40,7 +48,9 @@ func NewServer(opts ...Option) *Server {
 		mcpServer: server.NewMCPServer("kamaji", version.Version,
 			server.WithToolCapabilities(true),
 		),
-		signals: make(chan Signal, 10),
+		signals:   make(chan Signal, 10),
+		stopped:   make(chan struct{}),
+		questions: make(chan Question),
 	}
 	for _, opt := range opts {
 		opt(s)
@@ -49,7 +59,8 @@ func NewServer(opts ...Option) *Server {
 	return s
 }
 
-// Signals returns a receive-only channel for tool call events.
+// Signals returns a receive-only channel for tool call events, each tagged
+// with the session that sent it. It is closed on shutdown.
 func (s *Server) Signals() <-chan Signal {
 	return s.signals
 }
@@ -59,12 +70,8 @@ func (s *Server) handleTaskComplete(ctx context.Context, req mcp.CallToolRequest
 	if err != nil {
 		return result, err
 	}
-	if !result.IsError {
-		select {
-		case s.signals <- Signal{Tool: SignalToolTaskComplete, Status: args.Status, Summary: args.Summary}:
-		default:
-			slog.Debug("dropped signal: channel full", "tool", SignalToolTaskComplete)
-		}
+	if !result.IsError && !s.emit(ctx, Signal{Tool: SignalToolTaskComplete, Status: args.Status, Summary: args.Summary}) {
+		return mcp.NewToolResultError(errUndelivered), nil
 	}
 	return result, nil
 }
@@ -74,12 +81,41 @@ func (s *Server) handleNoteInsight(ctx context.Context, req mcp.CallToolRequest,
 	if err != nil {
 		return result, err
 	}
-	if !result.IsError {
-		select {
-		case s.signals <- Signal{Tool: SignalToolNoteInsight, Summary: args.Text}:
-		default:
-			slog.Debug("dropped signal: channel full", "tool", SignalToolNoteInsight)
-		}
+	if !result.IsError && !s.emit(ctx, Signal{Tool: SignalToolNoteInsight, Summary: args.Text}) {
+		return mcp.NewToolResultError(errUndelivered), nil
+	}
+	return result, nil
+}
+
+func (s *Server) handleSubmitPlan(ctx context.Context, req mcp.CallToolRequest, args SubmitPlanArgs) (*mcp.CallToolResult, error) {
+	result, err := HandleSubmitPlan(ctx, req, args)
+	if err != nil {
+		return result, err
+	}
+	if !result.IsError && !s.emit(ctx, Signal{Tool: SignalToolSubmitPlan, Summary: args.Plan}) {
+		return mcp.NewToolResultError(errUndelivered), nil
+	}
+	return result, nil
+}
+
+func (s *Server) handleSubmitReview(ctx context.Context, req mcp.CallToolRequest, args SubmitReviewArgs) (*mcp.CallToolResult, error) {
+	result, err := HandleSubmitReview(ctx, req, args)
+	if err != nil {
+		return result, err
+	}
+	if !result.IsError && !s.emit(ctx, Signal{Tool: SignalToolSubmitReview, Status: args.Verdict, Summary: args.Reasons}) {
+		return mcp.NewToolResultError(errUndelivered), nil
+	}
+	return result, nil
+}
+
+func (s *Server) handleReportProgress(ctx context.Context, req mcp.CallToolRequest, args ReportProgressArgs) (*mcp.CallToolResult, error) {
+	result, err := HandleReportProgress(ctx, req, args)
+	if err != nil {
+		return result, err
+	}
+	if !result.IsError && !s.emit(ctx, Signal{Tool: SignalToolReportProgress, Summary: args.Message, Percent: int(math.Round(args.Percent))}) {
+		return mcp.NewToolResultError(errUndelivered), nil
 	}
 	return result, nil
 }
@@ -97,9 +133,43 @@ func (s *Server) registerTools() {
 		mcp.WithString("text", mcp.Required(), mcp.Description("insight to record")),
 	)
 	s.mcpServer.AddTool(noteInsightTool, mcp.NewTypedToolHandler(s.handleNoteInsight))
+
+	submitPlanTool := mcp.NewTool("submit_plan",
+		mcp.WithDescription("Return the implementation plan from a planning session"),
+		mcp.WithString("plan", mcp.Required(), mcp.Description("files to change and the steps to take, in order")),
+	)
+	s.mcpServer.AddTool(submitPlanTool, mcp.NewTypedToolHandler(s.handleSubmitPlan))
+
+	submitReviewTool := mcp.NewTool("submit_review",
+		mcp.WithDescription("Return the verdict from a review session"),
+		mcp.WithString("verdict", mcp.Required(), mcp.Description("approve or reject")),
+		mcp.WithString("reasons", mcp.Required(), mcp.Description("why the change does or does not complete the task")),
+	)
+	s.mcpServer.AddTool(submitReviewTool, mcp.NewTypedToolHandler(s.handleSubmitReview))
+
+	reportProgressTool := mcp.NewTool("report_progress",
+		mcp.WithDescription("Report progress on a long task"),
+		mcp.WithString("message", mcp.Required(), mcp.Description("what was just done or is being worked on")),
+		mcp.WithNumber("percent", mcp.Required(), mcp.Min(0), mcp.Max(100), mcp.Description("estimated percent of the task done, 0 to 100")),
+	)
+	s.mcpServer.AddTool(reportProgressTool, mcp.NewTypedToolHandler(s.handleReportProgress))
+
+	askHumanTool := mcp.NewTool("ask_human",
+		mcp.WithDescription("Ask the person running kamaji a blocking question and wait for the answer"),
+		mcp.WithString("question", mcp.Required(), mcp.Description("what you need to know, with the options you see")),
+	)
+	s.mcpServer.AddTool(askHumanTool, mcp.NewTypedToolHandler(s.handleAskHuman))
+
+	if s.provider != nil {
+		s.registerContextTools()
+	}
+	if s.proposer != nil {
+		s.registerProposeTask()
+	}
 }
 
-// Start returns the port once listening.
+// Start listens on the loopback interface and returns the port once
+// listening.
 func (s *Server) Start() (int, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -108,7 +178,7 @@ func (s *Server) Start() (int, error) {
 		return 0, errors.New("server already started")
 	}
 
-	addr := fmt.Sprintf(":%d", s.port)
+	addr := fmt.Sprintf("127.0.0.1:%d", s.port)
 	listener, err := net.Listen("tcp", addr)
 	if err != nil {
 		return 0, fmt.Errorf("failed to listen: %w", err)
@@ -118,7 +188,7 @@ func (s *Server) Start() (int, error) {
 
 	mcpHandler := server.NewStreamableHTTPServer(s.mcpServer)
 	mux := http.NewServeMux()
-	mux.Handle("/mcp", mcpHandler)
+	mux.Handle("/mcp", s.authorize(mcpHandler))
 
 	s.httpServer = &http.Server{
 		Handler:           mux,
@@ -141,7 +211,12 @@ func (s *Server) Shutdown(ctx context.Context) error {
 	}
 
 	s.started = false
-	s.closeSignals.Do(func() { close(s.signals) })
+	s.closeSignals.Do(func() {
+		close(s.stopped)
+		s.sendMu.Lock()
+		close(s.signals)
+		s.sendMu.Unlock()
+	})
 	return s.httpServer.Shutdown(ctx)
 }
 
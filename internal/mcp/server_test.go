@@ -8,9 +8,29 @@ import (
 	"time"
 
 	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/client/transport"
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
+func serverURL(port int) string {
+	return "http://127.0.0.1:" + strconv.Itoa(port) + "/mcp"
+}
+
+// withToken starts a session on s and returns the client option that sends
+// its token.
+func withToken(t *testing.T, s *Server) transport.StreamableHTTPCOption {
+	t.Helper()
+	_, token, err := s.NewSession()
+	if err != nil {
+		t.Fatalf("NewSession() error = %v", err)
+	}
+	return withBearer(token)
+}
+
+func withBearer(token string) transport.StreamableHTTPCOption {
+	return transport.WithHTTPHeaders(map[string]string{"Authorization": "Bearer " + token})
+}
+
 func TestNewServer_DefaultPort(t *testing.T) {
 	s := NewServer()
 	if s.port != 0 {
@@ -39,7 +59,7 @@ func TestServer_Start(t *testing.T) {
 	}
 
 	// Verify server is listening by making a request
-	resp, err := http.Get("http://localhost:" + strconv.Itoa(port) + "/mcp")
+	resp, err := http.Get(serverURL(port))
 	if err != nil {
 		t.Fatalf("HTTP GET error = %v", err)
 	}
@@ -102,7 +122,7 @@ func TestServer_Shutdown(t *testing.T) {
 	time.Sleep(50 * time.Millisecond)
 
 	// Verify server is no longer listening
-	resp, err := http.Get("http://localhost:" + strconv.Itoa(port) + "/mcp")
+	resp, err := http.Get(serverURL(port))
 	if err == nil {
 		_ = resp.Body.Close()
 		t.Error("Server still responding after Shutdown")
@@ -126,7 +146,7 @@ func TestServer_Signal_TaskComplete_Pass(t *testing.T) {
 	defer func() { _ = s.Shutdown(context.Background()) }()
 
 	// Create client and call task_complete
-	c, err := client.NewStreamableHttpClient("http://localhost:" + strconv.Itoa(port) + "/mcp")
+	c, err := client.NewStreamableHttpClient(serverURL(port), withToken(t, s))
 	if err != nil {
 		t.Fatalf("NewStreamableHttpClient() error = %v", err)
 	}
@@ -181,7 +201,7 @@ func TestServer_Signal_TaskComplete_Fail(t *testing.T) {
 	}
 	defer func() { _ = s.Shutdown(context.Background()) }()
 
-	c, err := client.NewStreamableHttpClient("http://localhost:" + strconv.Itoa(port) + "/mcp")
+	c, err := client.NewStreamableHttpClient(serverURL(port), withToken(t, s))
 	if err != nil {
 		t.Fatalf("NewStreamableHttpClient() error = %v", err)
 	}
@@ -229,7 +249,7 @@ func TestServer_Signal_NoteInsight(t *testing.T) {
 	}
 	defer func() { _ = s.Shutdown(context.Background()) }()
 
-	c, err := client.NewStreamableHttpClient("http://localhost:" + strconv.Itoa(port) + "/mcp")
+	c, err := client.NewStreamableHttpClient(serverURL(port), withToken(t, s))
 	if err != nil {
 		t.Fatalf("NewStreamableHttpClient() error = %v", err)
 	}
@@ -268,6 +288,58 @@ func TestServer_Signal_NoteInsight(t *testing.T) {
 	}
 }
 
+func TestServer_Signal_ReportProgress(t *testing.T) {
+	s := NewServer()
+	c, err := client.NewInProcessClient(s.mcpServer)
+	if err != nil {
+		t.Fatalf("NewInProcessClient() error = %v", err)
+	}
+	defer func() { _ = c.Close() }()
+
+	ctx := context.Background()
+	_, _ = c.Initialize(ctx, mcp.InitializeRequest{
+		Params: mcp.InitializeParams{
+			ProtocolVersion: "2024-11-05",
+			ClientInfo:      mcp.Implementation{Name: "test", Version: "1.0.0"},
+		},
+	})
+
+	_, _ = c.CallTool(ctx, mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Name:      "report_progress",
+			Arguments: map[string]any{"message": "Wrote the handler", "percent": 40},
+		},
+	})
+
+	select {
+	case sig := <-s.Signals():
+		if sig.Tool != SignalToolReportProgress {
+			t.Errorf("Signal.Tool = %q, want %q", sig.Tool, SignalToolReportProgress)
+		}
+		if sig.Summary != "Wrote the handler" || sig.Percent != 40 {
+			t.Errorf("Signal = %+v, want message %q at 40%%", sig, "Wrote the handler")
+		}
+	case <-time.After(time.Second):
+		t.Error("Timeout waiting for signal")
+	}
+}
+
+func TestServer_Signal_ReportProgressRoundsPercent(t *testing.T) {
+	s := NewServer()
+	c := newSessionClient(t, s)
+
+	callTool(t, c, "report_progress", map[string]any{"message": "Halfway", "percent": 42.6})
+
+	select {
+	case sig := <-s.Signals():
+		if sig.Percent != 43 {
+			t.Errorf("Signal.Percent = %d, want 43", sig.Percent)
+		}
+	case <-time.After(time.Second):
+		t.Error("Timeout waiting for signal")
+	}
+}
+
 func TestServer_Signal_MultipleInOrder(t *testing.T) {
 	s := NewServer()
 	port, err := s.Start()
@@ -276,7 +348,7 @@ func TestServer_Signal_MultipleInOrder(t *testing.T) {
 	}
 	defer func() { _ = s.Shutdown(context.Background()) }()
 
-	c, err := client.NewStreamableHttpClient("http://localhost:" + strconv.Itoa(port) + "/mcp")
+	c, err := client.NewStreamableHttpClient(serverURL(port), withToken(t, s))
 	if err != nil {
 		t.Fatalf("NewStreamableHttpClient() error = %v", err)
 	}
@@ -345,7 +417,7 @@ func TestServer_ToolsRegistered(t *testing.T) {
 		t.Fatalf("ListTools() error = %v", err)
 	}
 
-	// Verify both tools are registered
+	// Verify all tools are registered
 	toolNames := make(map[string]bool)
 	for _, tool := range tools.Tools {
 		toolNames[tool.Name] = true
@@ -357,4 +429,127 @@ func TestServer_ToolsRegistered(t *testing.T) {
 	if !toolNames["note_insight"] {
 		t.Error("tool note_insight not registered")
 	}
+	if !toolNames["submit_plan"] {
+		t.Error("tool submit_plan not registered")
+	}
+	if !toolNames["submit_review"] {
+		t.Error("tool submit_review not registered")
+	}
+	if !toolNames["ask_human"] {
+		t.Error("tool ask_human not registered")
+	}
+}
+
+func TestServer_AskHuman(t *testing.T) {
+	s := NewServer()
+	c, err := client.NewInProcessClient(s.mcpServer)
+	if err != nil {
+		t.Fatalf("NewInProcessClient() error = %v", err)
+	}
+	defer func() { _ = c.Close() }()
+
+	ctx := context.Background()
+	_, _ = c.Initialize(ctx, mcp.InitializeRequest{
+		Params: mcp.InitializeParams{
+			ProtocolVersion: "2024-11-05",
+			ClientInfo:      mcp.Implementation{Name: "test", Version: "1.0.0"},
+		},
+	})
+
+	go func() {
+		q := <-s.Questions()
+		if q.Text == "Which database?" {
+			q.Answer("Postgres")
+		} else {
+			q.Decline("no answer")
+		}
+	}()
+
+	result, err := c.CallTool(ctx, mcp.CallToolRequest{
+		Params: mcp.CallToolParams{Name: "ask_human", Arguments: map[string]any{"question": "Which database?"}},
+	})
+	if err != nil {
+		t.Fatalf("CallTool() error = %v", err)
+	}
+	if text := result.Content[0].(mcp.TextContent).Text; text != `{"answer":"Postgres"}` {
+		t.Errorf("ask_human result = %s, want the answer", text)
+	}
+
+	go func() { (<-s.Questions()).Decline("no answer") }()
+
+	result, err = c.CallTool(ctx, mcp.CallToolRequest{
+		Params: mcp.CallToolParams{Name: "ask_human", Arguments: map[string]any{"question": "Anything?"}},
+	})
+	if err != nil {
+		t.Fatalf("CallTool() error = %v", err)
+	}
+	if !result.IsError {
+		t.Error("ask_human IsError = false, want true when declined")
+	}
+}
+
+func TestServer_RejectsWrongToken(t *testing.T) {
+	s := NewServer()
+	port, err := s.Start()
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer func() { _ = s.Shutdown(context.Background()) }()
+
+	_, token, err := s.NewSession()
+	if err != nil {
+		t.Fatalf("NewSession() error = %v", err)
+	}
+	_, stale, err := s.NewSession()
+	if err != nil {
+		t.Fatalf("NewSession() error = %v", err)
+	}
+	if token == stale {
+		t.Fatal("NewSession() returned the same token twice")
+	}
+
+	tests := []struct {
+		name   string
+		header string
+	}{
+		{"missing", ""},
+		{"wrong", "Bearer nope"},
+		{"replaced", "Bearer " + token},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := client.NewStreamableHttpClient(serverURL(port),
+				transport.WithHTTPHeaders(map[string]string{"Authorization": tt.header}))
+			if err != nil {
+				t.Fatalf("NewStreamableHttpClient() error = %v", err)
+			}
+			defer func() { _ = c.Close() }()
+
+			_, err = c.CallTool(context.Background(), mcp.CallToolRequest{
+				Params: mcp.CallToolParams{
+					Name:      "task_complete",
+					Arguments: map[string]any{"status": "pass", "summary": "Forged"},
+				},
+			})
+			if err == nil {
+				t.Error("CallTool() error = nil, want unauthorized")
+			}
+		})
+	}
+
+	s.EndSession()
+	resp, err := http.Get(serverURL(port))
+	if err != nil {
+		t.Fatalf("HTTP GET error = %v", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("status after EndSession = %d, want %d", resp.StatusCode, http.StatusUnauthorized)
+	}
+
+	select {
+	case sig := <-s.Signals():
+		t.Errorf("got signal %+v from an unauthorized client", sig)
+	default:
+	}
 }
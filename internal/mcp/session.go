@@ -0,0 +1,104 @@
+package mcp
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"net/http"
+)
+
+// errUndelivered is the tool error for a signal whose session ended, or whose
+// server shut down, before kamaji took it.
+const errUndelivered = "session ended before kamaji received the call"
+
+// session is the agent session the server accepts calls from.
+type session struct {
+	id    uint64
+	token string
+	ended chan struct{} // Closed when the session ends, releasing its waiting calls
+}
+
+type sessionKey struct{}
+
+// NewSession starts accepting calls from a new agent session, ending the
+// previous one so a lingering earlier agent cannot signal. It returns the id
+// the session's signals are tagged with and the bearer token the agent must
+// send as Authorization: Bearer <token>.
+func (s *Server) NewSession() (uint64, string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return 0, "", fmt.Errorf("generating MCP token: %w", err)
+	}
+	next := &session{id: s.sessions.Add(1), token: hex.EncodeToString(b), ended: make(chan struct{})}
+	if prev := s.session.Swap(next); prev != nil {
+		close(prev.ended)
+	}
+	return next.id, next.token, nil
+}
+
+// EndSession stops accepting the current session's token and fails its calls
+// that are still waiting for delivery.
+func (s *Server) EndSession() {
+	if prev := s.session.Swap(nil); prev != nil {
+		close(prev.ended)
+	}
+}
+
+// authorize rejects requests that do not carry the current session's bearer
+// token, and passes the session on to the tool handlers. Without a session no
+// request is accepted.
+func (s *Server) authorize(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sess := s.session.Load()
+		got := r.Header.Get("Authorization")
+		if sess == nil || subtle.ConstantTimeCompare([]byte(got), []byte("Bearer "+sess.token)) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, "invalid or missing MCP token", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), sessionKey{}, sess)))
+	})
+}
+
+// sessionEnded returns a channel closed when the calling session ends. Calls
+// made in-process, outside any session, get nil, which never closes.
+func sessionEnded(ctx context.Context) <-chan struct{} {
+	if sess, ok := ctx.Value(sessionKey{}).(*session); ok {
+		return sess.ended
+	}
+	return nil
+}
+
+// emit tags sig with the calling session and delivers it. Rather than drop a
+// signal when the buffer is full it waits until the signal is taken, the
+// session ends, the call is cancelled or the server shuts down. Returns false
+// if the signal was not delivered.
+func (s *Server) emit(ctx context.Context, sig Signal) bool {
+	if sess, ok := ctx.Value(sessionKey{}).(*session); ok {
+		sig.Session = sess.id
+	}
+
+	s.sendMu.RLock()
+	defer s.sendMu.RUnlock()
+	select {
+	case <-s.stopped:
+		slog.Warn("undelivered signal: server stopped", "tool", sig.Tool)
+		return false
+	default:
+	}
+
+	select {
+	case s.signals <- sig:
+		return true
+	case <-sessionEnded(ctx):
+		slog.Warn("undelivered signal: session ended", "tool", sig.Tool, "session", sig.Session)
+	case <-ctx.Done():
+		slog.Warn("undelivered signal: call cancelled", "tool", sig.Tool, "session", sig.Session)
+	case <-s.stopped:
+		slog.Warn("undelivered signal: server stopped", "tool", sig.Tool)
+	}
+	return false
+}
@@ -0,0 +1,132 @@
+package mcp
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func newSessionClient(t *testing.T, s *Server) *client.Client {
+	t.Helper()
+	if _, err := s.Start(); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	t.Cleanup(func() { _ = s.Shutdown(context.Background()) })
+	return connect(t, s, withToken(t, s))
+}
+
+// connect returns an initialized HTTP client of the started server s.
+func connect(t *testing.T, s *Server, opt transport.StreamableHTTPCOption) *client.Client {
+	t.Helper()
+	c, err := client.NewStreamableHttpClient(serverURL(s.Port()), opt)
+	if err != nil {
+		t.Fatalf("NewStreamableHttpClient() error = %v", err)
+	}
+	t.Cleanup(func() { _ = c.Close() })
+
+	_, err = c.Initialize(context.Background(), mcp.InitializeRequest{
+		Params: mcp.InitializeParams{
+			ProtocolVersion: "2024-11-05",
+			ClientInfo:      mcp.Implementation{Name: "test", Version: "1.0.0"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+	return c
+}
+
+func TestServer_SignalsTaggedWithSession(t *testing.T) {
+	s := NewServer()
+	c := newSessionClient(t, s)
+
+	callTool(t, c, "note_insight", map[string]any{"text": "first"})
+	first := <-s.Signals()
+
+	// A new session's signals carry its own id.
+	id, token, err := s.NewSession()
+	if err != nil {
+		t.Fatalf("NewSession() error = %v", err)
+	}
+	callTool(t, connect(t, s, withBearer(token)), "note_insight", map[string]any{"text": "second"})
+	second := <-s.Signals()
+
+	if first.Session == 0 {
+		t.Error("first Signal.Session = 0, want a session id")
+	}
+	if second.Session != id {
+		t.Errorf("second Signal.Session = %d, want %d", second.Session, id)
+	}
+	if first.Session == second.Session {
+		t.Errorf("both sessions tagged %d, want distinct ids", first.Session)
+	}
+}
+
+func TestServer_SignalsNotDroppedWhenFull(t *testing.T) {
+	s := NewServer()
+	c := newSessionClient(t, s)
+
+	const n = 25 // More than the signal buffer holds
+	go func() {
+		for i := range n {
+			_, _ = c.CallTool(context.Background(), mcp.CallToolRequest{
+				Params: mcp.CallToolParams{Name: "note_insight", Arguments: map[string]any{"text": strconv.Itoa(i)}},
+			})
+		}
+	}()
+
+	for i := range n {
+		select {
+		case sig := <-s.Signals():
+			if sig.Summary != strconv.Itoa(i) {
+				t.Fatalf("signal %d Summary = %q, want %q", i, sig.Summary, strconv.Itoa(i))
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("got %d signals, want %d", i, n)
+		}
+	}
+}
+
+func TestServer_EndSessionReleasesWaitingSignal(t *testing.T) {
+	s := NewServer()
+	c := newSessionClient(t, s)
+
+	for i := range cap(s.signals) {
+		callTool(t, c, "note_insight", map[string]any{"text": strconv.Itoa(i)})
+	}
+
+	results := make(chan *mcp.CallToolResult, 1)
+	go func() {
+		result, _ := c.CallTool(context.Background(), mcp.CallToolRequest{
+			Params: mcp.CallToolParams{Name: "task_complete", Arguments: map[string]any{"status": "pass", "summary": "Late"}},
+		})
+		results <- result
+	}()
+
+	select {
+	case <-results:
+		t.Fatal("task_complete returned while the signal buffer was full")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	s.EndSession()
+	select {
+	case result := <-results:
+		if result == nil {
+			t.Fatal("task_complete call failed")
+		}
+		if !result.IsError {
+			t.Error("task_complete IsError = false, want true after the session ended")
+		}
+		if got := resultText(result); got != errUndelivered {
+			t.Errorf("task_complete result = %q, want %q", got, errUndelivered)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("task_complete still waiting after EndSession")
+	}
+}
@@ -3,14 +3,18 @@ package mcp
 import (
 	"context"
 	"encoding/json"
+	"strings"
 
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
 // Signal tool name constants.
 const (
-	SignalToolTaskComplete = "task_complete"
-	SignalToolNoteInsight  = "note_insight"
+	SignalToolTaskComplete   = "task_complete"
+	SignalToolNoteInsight    = "note_insight"
+	SignalToolSubmitPlan     = "submit_plan"
+	SignalToolSubmitReview   = "submit_review"
+	SignalToolReportProgress = "report_progress"
 )
 
 // Status constants for task_complete results.
@@ -19,11 +23,19 @@ const (
 	StatusFail = "fail"
 )
 
+// Verdict constants for submit_review results.
+const (
+	VerdictApprove = "approve"
+	VerdictReject  = "reject"
+)
+
 // Signal represents a tool call event emitted by the MCP server.
 type Signal struct {
-	Tool    string // SignalToolTaskComplete or SignalToolNoteInsight
-	Status  string // "pass" or "fail" (only for task_complete)
-	Summary string // task_complete summary or note_insight text
+	Tool    string // One of the SignalTool constants
+	Status  string // task_complete status or submit_review verdict
+	Summary string // task_complete summary, note_insight text, submit_plan plan, submit_review reasons or report_progress message
+	Percent int    // report_progress percent complete
+	Session uint64 // Id from NewSession of the session that sent it, 0 for calls outside a session
 }
 
 type TaskCompleteArgs struct {
@@ -88,3 +100,82 @@ func HandleNoteInsight(_ context.Context, _ mcp.CallToolRequest, args NoteInsigh
 
 	return mcp.NewToolResultText(string(data)), nil
 }
+
+type SubmitPlanArgs struct {
+	Plan string `json:"plan"`
+}
+
+type SubmitPlanResult struct {
+	Accepted bool `json:"accepted"`
+}
+
+//nolint:unparam // error return required by mcp-go TypedToolHandler interface
+func HandleSubmitPlan(_ context.Context, _ mcp.CallToolRequest, args SubmitPlanArgs) (*mcp.CallToolResult, error) {
+	if strings.TrimSpace(args.Plan) == "" {
+		return mcp.NewToolResultError("plan is required"), nil
+	}
+
+	data, err := json.Marshal(SubmitPlanResult{Accepted: true})
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	return mcp.NewToolResultText(string(data)), nil
+}
+
+type SubmitReviewArgs struct {
+	Verdict string `json:"verdict"`
+	Reasons string `json:"reasons"`
+}
+
+type SubmitReviewResult struct {
+	Verdict  string `json:"verdict"`
+	Accepted bool   `json:"accepted"`
+}
+
+//nolint:unparam // error return required by mcp-go TypedToolHandler interface
+func HandleSubmitReview(_ context.Context, _ mcp.CallToolRequest, args SubmitReviewArgs) (*mcp.CallToolResult, error) {
+	if args.Verdict != VerdictApprove && args.Verdict != VerdictReject {
+		return mcp.NewToolResultError("verdict must be approve or reject"), nil
+	}
+
+	if strings.TrimSpace(args.Reasons) == "" {
+		return mcp.NewToolResultError("reasons are required"), nil
+	}
+
+	data, err := json.Marshal(SubmitReviewResult{Verdict: args.Verdict, Accepted: true})
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	return mcp.NewToolResultText(string(data)), nil
+}
+
+// ReportProgressArgs takes percent as a float because the schema declares a
+// JSON number, which agents may send with a fraction.
+type ReportProgressArgs struct {
+	Message string  `json:"message"`
+	Percent float64 `json:"percent"`
+}
+
+type ReportProgressResult struct {
+	Recorded bool `json:"recorded"`
+}
+
+//nolint:unparam // error return required by mcp-go TypedToolHandler interface
+func HandleReportProgress(_ context.Context, _ mcp.CallToolRequest, args ReportProgressArgs) (*mcp.CallToolResult, error) {
+	if strings.TrimSpace(args.Message) == "" {
+		return mcp.NewToolResultError("message is required"), nil
+	}
+
+	if args.Percent < 0 || args.Percent > 100 {
+		return mcp.NewToolResultError("percent must be between 0 and 100"), nil
+	}
+
+	data, err := json.Marshal(ReportProgressResult{Recorded: true})
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	return mcp.NewToolResultText(string(data)), nil
+}
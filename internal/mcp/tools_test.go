@@ -143,3 +143,93 @@ func TestHandleNoteInsight_Empty(t *testing.T) {
 		t.Error("HandleNoteInsight() IsError = false, want true for empty text")
 	}
 }
+
+func TestHandleSubmitPlan(t *testing.T) {
+	result, err := HandleSubmitPlan(context.Background(), mcp.CallToolRequest{}, SubmitPlanArgs{Plan: "1. Edit a.go"})
+	if err != nil {
+		t.Fatalf("HandleSubmitPlan() error = %v", err)
+	}
+	if result.IsError {
+		t.Fatal("HandleSubmitPlan() returned error result")
+	}
+
+	var content SubmitPlanResult
+	if err := json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &content); err != nil {
+		t.Fatalf("Failed to unmarshal result: %v", err)
+	}
+	if !content.Accepted {
+		t.Error("result.Accepted = false, want true")
+	}
+
+	result, err = HandleSubmitPlan(context.Background(), mcp.CallToolRequest{}, SubmitPlanArgs{Plan: "  "})
+	if err != nil {
+		t.Fatalf("HandleSubmitPlan() error = %v", err)
+	}
+	if !result.IsError {
+		t.Error("HandleSubmitPlan() IsError = false, want true for blank plan")
+	}
+}
+
+func TestHandleSubmitReview(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    SubmitReviewArgs
+		wantErr bool
+	}{
+		{"approve", SubmitReviewArgs{Verdict: VerdictApprove, Reasons: "Matches the task"}, false},
+		{"reject", SubmitReviewArgs{Verdict: VerdictReject, Reasons: "Tests are missing"}, false},
+		{"unknown verdict", SubmitReviewArgs{Verdict: "maybe", Reasons: "Unsure"}, true},
+		{"blank reasons", SubmitReviewArgs{Verdict: VerdictReject, Reasons: " "}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := HandleSubmitReview(context.Background(), mcp.CallToolRequest{}, tt.args)
+			if err != nil {
+				t.Fatalf("HandleSubmitReview() error = %v", err)
+			}
+			if result.IsError != tt.wantErr {
+				t.Fatalf("HandleSubmitReview() IsError = %v, want %v", result.IsError, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			var content SubmitReviewResult
+			if err := json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &content); err != nil {
+				t.Fatalf("Failed to unmarshal result: %v", err)
+			}
+			if content.Verdict != tt.args.Verdict || !content.Accepted {
+				t.Errorf("result = %+v, want verdict %q accepted", content, tt.args.Verdict)
+			}
+		})
+	}
+}
+
+func TestHandleReportProgress(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    ReportProgressArgs
+		wantErr bool
+	}{
+		{"valid", ReportProgressArgs{Message: "Wrote the handler", Percent: 40}, false},
+		{"bounds", ReportProgressArgs{Message: "Starting", Percent: 0}, false},
+		{"fraction", ReportProgressArgs{Message: "Halfway", Percent: 42.5}, false},
+		{"blank message", ReportProgressArgs{Message: " ", Percent: 40}, true},
+		{"negative percent", ReportProgressArgs{Message: "Starting", Percent: -1}, true},
+		{"percent over 100", ReportProgressArgs{Message: "Done", Percent: 101}, true},
+		{"fraction over 100", ReportProgressArgs{Message: "Done", Percent: 100.5}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := HandleReportProgress(context.Background(), mcp.CallToolRequest{}, tt.args)
+			if err != nil {
+				t.Fatalf("HandleReportProgress() error = %v", err)
+			}
+			if result.IsError != tt.wantErr {
+				t.Errorf("HandleReportProgress() IsError = %v, want %v", result.IsError, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package orchestrator
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/sqve/kamaji/internal/config"
+	"github.com/sqve/kamaji/internal/domain"
+	"github.com/sqve/kamaji/internal/mcp"
+	"github.com/sqve/kamaji/internal/prompt"
+	"github.com/sqve/kamaji/internal/statemachine"
+)
+
+// contextTools answers the MCP context tools for the sessions of one ticket
+// from the sprint, the shared state and the ticket histories.
+type contextTools struct {
+	r           *runner
+	ticketIndex int
+}
+
+func (c contextTools) Task() (*mcp.TaskView, error) {
+	var info *statemachine.TaskInfo
+	var failures int
+	c.r.handler.view(func(state *domain.State) {
+		info = statemachine.TicketTask(state, c.r.sprint, c.ticketIndex)
+		if info != nil {
+			failures = statemachine.Progress(state, info.Ticket).FailureCount
+		}
+	})
+	if info == nil {
+		return nil, errors.New("ticket has no task left")
+	}
+	return &mcp.TaskView{
+		Ticket:            info.Ticket.Name,
+		Branch:            info.Ticket.Branch,
+		TicketDescription: info.Ticket.Description,
+		Task:              info.Task.Description,
+		Steps:             info.Task.Steps,
+		Verify:            info.Task.Verify,
+		VerifyCmd:         info.Task.VerifyCmd,
+		Rules:             prompt.TaskRules(c.r.sprint, info.Ticket, info.Task),
+		Attempt:           failures + 1,
+		MaxAttempts:       statemachine.MaxAttempts(c.r.sprint, info),
+	}, nil
+}
+
+func (c contextTools) TicketHistory(ticket, kind string) (*domain.TicketHistory, error) {
+	if ticket == "" {
+		ticket = c.r.sprint.Tickets[c.ticketIndex].Name
+	}
+	if statemachine.FindTicket(c.r.sprint, ticket) == nil {
+		return nil, fmt.Errorf("unknown ticket %q", ticket)
+	}
+	history, err := config.LoadTicketHistory(c.r.cfg.WorkDir, ticket)
+	if err != nil {
+		return nil, err
+	}
+	return config.FilterHistory(history, kind)
+}
+
+func (c contextTools) SprintOverview() (*mcp.SprintOverview, error) {
+	overview := &mcp.SprintOverview{Sprint: c.r.sprint.Name, Tickets: []mcp.TicketOverview{}}
+	c.r.handler.view(func(state *domain.State) {
+		for i := range c.r.sprint.Tickets {
+			ticket := &c.r.sprint.Tickets[i]
+			progress := statemachine.Progress(state, ticket)
+			status := ticketStatus(state, c.r.sprint, ticket, progress)
+			if i == c.ticketIndex && status == mcp.TicketPending {
+				status = mcp.TicketInProgress // The asking session is working on it
+			}
+			overview.Tickets = append(overview.Tickets, mcp.TicketOverview{
+				Name:        ticket.Name,
+				Branch:      ticket.Branch,
+				Description: ticket.Description,
+				Status:      status,
+				TasksDone:   progress.TasksDone,
+				TasksTotal:  len(ticket.Tasks),
+				DependsOn:   ticket.DependsOn,
+			})
+		}
+	})
+	return overview, nil
+}
+
+func (c contextTools) SearchInsights(query string) ([]mcp.InsightMatch, error) {
+	histories, err := config.ListTicketHistories(c.r.cfg.WorkDir)
+	if err != nil {
+		return nil, err
+	}
+	query = strings.ToLower(query)
+	var matches []mcp.InsightMatch
+	for _, h := range histories {
+		for _, insight := range h.Insights {
+			if strings.Contains(strings.ToLower(insight), query) {
+				matches = append(matches, mcp.InsightMatch{Ticket: h.Ticket, Insight: insight})
+			}
+		}
+	}
+	return matches, nil
+}
+
+func ticketStatus(state *domain.State, sprint *domain.Sprint, ticket *domain.Ticket, progress statemachine.TicketProgress) string {
+	switch {
+	case statemachine.TicketComplete(state, ticket):
+		return mcp.TicketDone
+	case statemachine.IsStuck(state, sprint, ticket):
+		return mcp.TicketStuck
+	case !statemachine.DependenciesMet(state, sprint, ticket):
+		return mcp.TicketBlocked
+	case progress.Started():
+		return mcp.TicketInProgress
+	default:
+		return mcp.TicketPending
+	}
+}
@@ -0,0 +1,111 @@
+package orchestrator
+
+import (
+	"errors"
+	"os"
+
+	"github.com/sqve/kamaji/internal/config"
+	"github.com/sqve/kamaji/internal/output"
+	"github.com/sqve/kamaji/internal/prompt"
+	"github.com/sqve/kamaji/internal/statemachine"
+)
+
+// DryRun prints the remaining tasks in execution order, with the branch each
+// new ticket would create and the exact prompt each task would receive. It
+// assumes every task passes and never spawns agents, runs git, or saves state.
+// Context files are read from the ticket's worktree when a parallel run has
+// already created it, otherwise from the current checkout. Returns the number
+// of tasks that would run.
+func DryRun(cfg RunConfig) (int, error) {
+	if cfg.WorkDir == "" {
+		return 0, errors.New("WorkDir is required")
+	}
+	if cfg.SprintPath == "" {
+		return 0, errors.New("SprintPath is required")
+	}
+
+	sprint, err := config.LoadSprint(cfg.SprintPath)
+	if err != nil {
+		return 0, err
+	}
+
+	// The loaded state is a private copy; advancing it is never persisted.
+	state, err := config.LoadState(cfg.WorkDir)
+	if err != nil {
+		return 0, err
+	}
+
+	if _, err := reconcileState(sprint, state, cfg.AcceptChanges, true); err != nil {
+		return 0, err
+	}
+
+	tasks := 0
+	started := make(map[string]bool)
+	for {
+		info := statemachine.NextTask(state, sprint)
+		if info == nil {
+			break
+		}
+		ticket := info.Ticket
+
+		if !started[ticket.Name] {
+			started[ticket.Name] = true
+			if !statemachine.Progress(state, ticket).Started() {
+				output.PrintTicketStart(ticket)
+				output.PrintBranchPlanned(ticket.Branch, sprint.BaseBranch)
+				for _, dep := range ticket.DependsOn {
+					if depTicket := statemachine.FindTicket(sprint, dep); depTicket != nil {
+						output.PrintMergePlanned(depTicket.Branch)
+					}
+				}
+			}
+		}
+
+		// Planned tasks show the planning prompt, since the task prompt
+		// depends on the plan the session submits.
+		planning := statemachine.Plan(sprint, info)
+		dir, ownDir := dryRunDir(cfg, ticket.Name)
+		var promptText string
+		var err error
+		if planning {
+			promptText, err = prompt.AssemblePlanContext(sprint, info, cfg.WorkDir, dir)
+		} else {
+			promptText, err = prompt.AssembleTaskContext(sprint, info, cfg.WorkDir, dir, "")
+		}
+		if err != nil {
+			return tasks, err
+		}
+
+		output.PrintTaskStart(info, sprint)
+		if !ownDir && len(ticket.Context)+len(info.Task.Context) > 0 {
+			output.PrintInfo("Context files are read from the current checkout; the run reads them on " + ticket.Branch)
+		}
+		if planning {
+			output.PrintInfo("Planning session runs first; its plan is added to the task prompt")
+		}
+		if statemachine.Review(sprint, info) {
+			output.PrintInfo("A review session checks a pass before it is committed")
+		}
+		output.PrintPrompt(promptText)
+		tasks++
+
+		statemachine.RecordPass(state, ticket)
+	}
+
+	output.PrintDryRunComplete(tasks)
+	return tasks, nil
+}
+
+// dryRunDir returns the directory context files are read from, and whether it
+// is the one the run would use. A parallel run reuses the ticket's worktree
+// once created; any other run checks out the ticket branch, which a dry run
+// cannot do, so the current checkout stands in for it.
+func dryRunDir(cfg RunConfig, ticketName string) (string, bool) {
+	if cfg.Parallel > 1 {
+		dir := config.WorktreeDir(cfg.WorkDir, ticketName)
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			return dir, true
+		}
+	}
+	return cfg.WorkDir, false
+}
@@ -0,0 +1,110 @@
+package orchestrator_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sqve/kamaji/internal/config"
+	"github.com/sqve/kamaji/internal/domain"
+	"github.com/sqve/kamaji/internal/orchestrator"
+	"github.com/sqve/kamaji/internal/testutil"
+)
+
+func TestDryRun_ResumesFromSavedState(t *testing.T) {
+	dir := t.TempDir()
+	sprint := &domain.Sprint{
+		Name:       "test",
+		BaseBranch: "main",
+		Tickets: []domain.Ticket{
+			{Name: "TICKET-1", Branch: "feat/one", Tasks: []domain.Task{
+				{Description: "Finished task"},
+				{Description: "Retried task"},
+			}},
+			{Name: "TICKET-2", Branch: "feat/two", Tasks: []domain.Task{{Description: "Fresh task"}}},
+		},
+	}
+	sprintPath := writeSprintFile(t, dir, sprint)
+
+	state := &domain.State{Tickets: map[string]domain.TicketState{
+		"TICKET-1": {Done: []string{"Finished task"}, FailingTask: "Retried task", FailureCount: 2},
+	}}
+	if err := config.SaveState(dir, state); err != nil {
+		t.Fatal(err)
+	}
+	statePath := filepath.Join(dir, ".kamaji", "state.yaml")
+	before, err := os.ReadFile(statePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var tasks int
+	output := testutil.CaptureStdout(t, func() {
+		tasks, err = orchestrator.DryRun(orchestrator.RunConfig{WorkDir: dir, SprintPath: sprintPath})
+	})
+	if err != nil {
+		t.Fatalf("DryRun() error = %v", err)
+	}
+
+	if tasks != 2 {
+		t.Errorf("tasks = %d, want 2", tasks)
+	}
+	testutil.AssertNotContains(t, output, "Finished task")
+	testutil.AssertContains(t, output, "<current>\nRetried task\n</current>")
+	testutil.AssertNotContains(t, output, "Would create branch: feat/one")
+	testutil.AssertContains(t, output, "Would create branch: feat/two from main")
+
+	after, err := os.ReadFile(statePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(after) != string(before) {
+		t.Errorf("state file changed:\n%s\nwant:\n%s", after, before)
+	}
+}
+
+func TestDryRun_ConfigValidation(t *testing.T) {
+	if _, err := orchestrator.DryRun(orchestrator.RunConfig{SprintPath: "kamaji.yaml"}); err == nil {
+		t.Error("expected error for empty WorkDir")
+	}
+	if _, err := orchestrator.DryRun(orchestrator.RunConfig{WorkDir: t.TempDir()}); err == nil {
+		t.Error("expected error for empty SprintPath")
+	}
+}
+
+func TestDryRun_ReadsContextFromTicketWorktree(t *testing.T) {
+	dir := t.TempDir()
+	sprint := &domain.Sprint{
+		Name:       "test",
+		BaseBranch: "main",
+		Tickets: []domain.Ticket{
+			{Name: "TICKET-1", Branch: "feat/one", Context: []string{"notes.md"}, Tasks: []domain.Task{{Description: "Resumed task"}}},
+			{Name: "TICKET-2", Branch: "feat/two", Context: []string{"notes.md"}, Tasks: []domain.Task{{Description: "Fresh task"}}},
+		},
+	}
+	sprintPath := writeSprintFile(t, dir, sprint)
+
+	worktree := config.WorktreeDir(dir, "TICKET-1")
+	if err := os.MkdirAll(worktree, 0o750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(worktree, "notes.md"), []byte("From the worktree"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "notes.md"), []byte("From the checkout"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var err error
+	output := testutil.CaptureStdout(t, func() {
+		_, err = orchestrator.DryRun(orchestrator.RunConfig{WorkDir: dir, SprintPath: sprintPath, Parallel: 2})
+	})
+	if err != nil {
+		t.Fatalf("DryRun() error = %v", err)
+	}
+
+	testutil.AssertContains(t, output, "From the worktree")
+	testutil.AssertContains(t, output, "From the checkout")
+	testutil.AssertContains(t, output, "the run reads them on feat/two")
+	testutil.AssertNotContains(t, output, "the run reads them on feat/one")
+}
@@ -2,6 +2,8 @@ package orchestrator
 
 import (
 	"errors"
+	"fmt"
+	"sync"
 
 	"github.com/sqve/kamaji/internal/config"
 	"github.com/sqve/kamaji/internal/domain"
@@ -12,27 +14,45 @@ import (
 
 // Handler orchestrates pass/fail/stuck workflows for task outcomes.
 // Handler does not own state or sprint; the caller owns these values and is
-// responsible for their lifecycle. Handler expects single-threaded access.
+// responsible for their lifecycle. State mutations and saves are serialized, so
+// handlers derived via InDir may be used from concurrent ticket workers.
 type Handler struct {
-	workDir string
+	workDir string // Holds .kamaji state and history
+	gitDir  string // Where git operations run, differs from workDir for worktrees
 	state   *domain.State
 	sprint  *domain.Sprint
+	mu      *sync.Mutex
 }
 
 // NewHandler creates a Handler with the required dependencies.
 func NewHandler(workDir string, state *domain.State, sprint *domain.Sprint) *Handler {
 	return &Handler{
 		workDir: workDir,
+		gitDir:  workDir,
 		state:   state,
 		sprint:  sprint,
+		mu:      &sync.Mutex{},
 	}
 }
 
+// InDir returns a Handler sharing state with h that runs git operations in dir.
+func (h *Handler) InDir(dir string) *Handler {
+	c := *h
+	c.gitDir = dir
+	return &c
+}
+
 // OnPass commits changes, records completion, advances state, and persists.
 // If no files were changed, the commit is skipped but the task still advances.
-func (h *Handler) OnPass(ticketName, taskDesc, summary string) error {
+// Each step is appended to the event journal. Usage may be nil when unknown.
+func (h *Handler) OnPass(ticketName, taskDesc, summary string, usage *domain.Usage) error {
+	ticket, err := h.ticket(ticketName)
+	if err != nil {
+		return err
+	}
+
 	committed := true
-	if err := git.CommitChanges(h.workDir, summary); err != nil {
+	if err := git.CommitChanges(h.gitDir, summary); err != nil {
 		if errors.Is(err, git.ErrNothingToCommit) {
 			committed = false
 		} else {
@@ -40,20 +60,28 @@ func (h *Handler) OnPass(ticketName, taskDesc, summary string) error {
 		}
 	}
 
-	if err := config.RecordCompleted(h.workDir, ticketName, taskDesc, summary); err != nil {
-		return err
+	if committed {
+		sha, err := git.HeadCommit(h.gitDir)
+		if err != nil {
+			return err
+		}
+		if err := config.AppendEvent(h.workDir, domain.Event{
+			Type: domain.EventCommit, Ticket: ticketName, Task: taskDesc, Commit: sha, Summary: summary,
+		}); err != nil {
+			return err
+		}
 	}
 
-	prevTicket := h.state.CurrentTicket
-	prevTask := h.state.CurrentTask
-	prevFailures := h.state.FailureCount
-
-	statemachine.RecordPass(h.state, h.sprint)
+	if err := config.RecordCompleted(h.workDir, ticketName, taskDesc, summary, usage); err != nil {
+		return err
+	}
+	if err := config.AppendEvent(h.workDir, domain.Event{
+		Type: domain.EventCompleted, Ticket: ticketName, Task: taskDesc, Summary: summary, Usage: usage,
+	}); err != nil {
+		return err
+	}
 
-	if err := config.SaveState(h.workDir, h.state); err != nil {
-		h.state.CurrentTicket = prevTicket
-		h.state.CurrentTask = prevTask
-		h.state.FailureCount = prevFailures
+	if err := h.update(ticket, func() { statemachine.RecordPass(h.state, ticket) }); err != nil {
 		return err
 	}
 
@@ -63,22 +91,33 @@ func (h *Handler) OnPass(ticketName, taskDesc, summary string) error {
 	return nil
 }
 
-// OnFail resets changes, records failure, increments failure count, and persists.
-func (h *Handler) OnFail(ticketName, taskDesc, summary string) error {
-	if err := git.ResetToHead(h.workDir); err != nil {
+// OnFail resets changes, records the failed attempt, increments failure count,
+// and persists. Each step is appended to the event journal. The attempt's
+// usage may be nil when unknown.
+func (h *Handler) OnFail(ticketName string, attempt domain.FailedAttempt) error {
+	ticket, err := h.ticket(ticketName)
+	if err != nil {
 		return err
 	}
 
-	if err := config.RecordFailed(h.workDir, ticketName, taskDesc, summary); err != nil {
+	if err := git.ResetToHead(h.gitDir); err != nil {
+		return err
+	}
+	if err := config.AppendEvent(h.workDir, domain.Event{Type: domain.EventReset, Ticket: ticketName, Task: attempt.Task}); err != nil {
 		return err
 	}
 
-	prevFailures := h.state.FailureCount
-
-	statemachine.RecordFail(h.state)
+	if err := config.RecordFailed(h.workDir, ticketName, attempt); err != nil {
+		return err
+	}
+	if err := config.AppendEvent(h.workDir, domain.Event{
+		Type: domain.EventFailed, Ticket: ticketName, Task: attempt.Task, Summary: attempt.Summary,
+		Usage: attempt.Usage, TimedOut: attempt.TimedOut,
+	}); err != nil {
+		return err
+	}
 
-	if err := config.SaveState(h.workDir, h.state); err != nil {
-		h.state.FailureCount = prevFailures
+	if err := h.update(ticket, func() { statemachine.RecordFail(h.state, ticket) }); err != nil {
 		return err
 	}
 
@@ -86,13 +125,64 @@ func (h *Handler) OnFail(ticketName, taskDesc, summary string) error {
 	return nil
 }
 
-// OnStuck outputs the stuck message and preserves state for manual intervention.
-func (h *Handler) OnStuck() error {
-	output.PrintSprintStuck(h.sprint, h.state)
+// OnStuck outputs the stuck message, journals it with the last failure summary,
+// and preserves state for manual intervention.
+func (h *Handler) OnStuck(ticketName, summary string) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	output.PrintSprintStuck(h.sprint, h.state, ticketName)
+	if err := config.AppendEvent(h.workDir, domain.Event{Type: domain.EventStuck, Ticket: ticketName, Summary: summary}); err != nil {
+		return err
+	}
 	return config.SaveState(h.workDir, h.state)
 }
 
-// IsStuck returns true if the failure count has reached the stuck threshold.
-func (h *Handler) IsStuck() bool {
-	return statemachine.IsStuck(h.state)
+// IsStuck returns true if the ticket's failure count has reached the current
+// task's effective max_attempts.
+func (h *Handler) IsStuck(ticketName string) bool {
+	ticket := statemachine.FindTicket(h.sprint, ticketName)
+	if ticket == nil {
+		return false
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return statemachine.IsStuck(h.state, h.sprint, ticket)
+}
+
+// view runs fn with exclusive access to the shared state.
+func (h *Handler) view(fn func(state *domain.State)) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	fn(h.state)
+}
+
+// update applies mutate to the ticket's progress, persists the state and
+// journals the new progress, restoring the previous progress if saving fails.
+func (h *Handler) update(ticket *domain.Ticket, mutate func()) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	key := ticket.Key()
+	prev, existed := h.state.Tickets[key]
+	mutate()
+
+	if err := config.SaveState(h.workDir, h.state); err != nil {
+		if existed {
+			h.state.Tickets[key] = prev
+		} else {
+			delete(h.state.Tickets, key)
+		}
+		return err
+	}
+	return config.AppendProgress(h.workDir, h.state, key, ticket.Name)
+}
+
+func (h *Handler) ticket(name string) (*domain.Ticket, error) {
+	ticket := statemachine.FindTicket(h.sprint, name)
+	if ticket == nil {
+		return nil, fmt.Errorf("unknown ticket: %s", name)
+	}
+	return ticket, nil
 }
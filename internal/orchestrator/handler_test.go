@@ -23,21 +23,21 @@ func TestOnPass_CommitsAndAdvances(t *testing.T) {
 			{Name: "TICKET-1", Tasks: []domain.Task{{Description: "task 1"}, {Description: "task 2"}}},
 		},
 	}
-	state := &domain.State{CurrentTicket: 0, CurrentTask: 0, FailureCount: 0}
+	state := &domain.State{}
 
 	writeFile(t, dir, "test.txt", "content")
 
 	h := orchestrator.NewHandler(dir, state, sprint)
-	err := h.OnPass("TICKET-1", "task 1", "Implement feature")
+	err := h.OnPass("TICKET-1", "task 1", "Implement feature", nil)
 	if err != nil {
 		t.Fatalf("OnPass failed: %v", err)
 	}
 
-	if state.CurrentTask != 1 {
-		t.Errorf("expected task 1, got %d", state.CurrentTask)
+	if got := statemachine.Progress(state, &sprint.Tickets[0]); got.CurrentTask != 1 {
+		t.Errorf("expected task 1, got %d", got.CurrentTask)
 	}
-	if state.FailureCount != 0 {
-		t.Errorf("expected failure count 0, got %d", state.FailureCount)
+	if got := statemachine.Progress(state, &sprint.Tickets[0]); got.FailureCount != 0 {
+		t.Errorf("expected failure count 0, got %d", got.FailureCount)
 	}
 
 	assertCommitExists(t, dir, "Implement feature")
@@ -57,8 +57,8 @@ func TestOnPass_CommitsAndAdvances(t *testing.T) {
 	if err != nil {
 		t.Fatalf("LoadState failed: %v", err)
 	}
-	if savedState.CurrentTask != 1 {
-		t.Errorf("saved state task mismatch: got %d", savedState.CurrentTask)
+	if got := statemachine.Progress(savedState, &sprint.Tickets[0]).CurrentTask; got != 1 {
+		t.Errorf("saved state task mismatch: got %d", got)
 	}
 }
 
@@ -71,16 +71,16 @@ func TestOnPass_NoChangesStillAdvances(t *testing.T) {
 			{Name: "TICKET-1", Tasks: []domain.Task{{Description: "task 1"}, {Description: "task 2"}}},
 		},
 	}
-	state := &domain.State{CurrentTicket: 0, CurrentTask: 0, FailureCount: 0}
+	state := &domain.State{}
 
 	h := orchestrator.NewHandler(dir, state, sprint)
-	err := h.OnPass("TICKET-1", "task 1", "Verification passed")
+	err := h.OnPass("TICKET-1", "task 1", "Verification passed", nil)
 	if err != nil {
 		t.Fatalf("OnPass failed: %v", err)
 	}
 
-	if state.CurrentTask != 1 {
-		t.Errorf("expected task 1, got %d", state.CurrentTask)
+	if got := statemachine.Progress(state, &sprint.Tickets[0]).CurrentTask; got != 1 {
+		t.Errorf("expected task 1, got %d", got)
 	}
 
 	history, err := config.LoadTicketHistory(dir, "TICKET-1")
@@ -92,6 +92,49 @@ func TestOnPass_NoChangesStillAdvances(t *testing.T) {
 	}
 }
 
+func TestOnPass_JournalsCommitAndProgress(t *testing.T) {
+	dir := t.TempDir()
+	testutil.InitGitRepo(t, dir)
+
+	sprint := &domain.Sprint{
+		Tickets: []domain.Ticket{
+			{Name: "TICKET-1", Tasks: []domain.Task{{Description: "task 1"}, {Description: "task 2"}}},
+		},
+	}
+	state := &domain.State{}
+
+	writeFile(t, dir, "test.txt", "content")
+
+	h := orchestrator.NewHandler(dir, state, sprint)
+	if err := h.OnPass("TICKET-1", "task 1", "Implement feature", nil); err != nil {
+		t.Fatalf("OnPass failed: %v", err)
+	}
+
+	events, err := config.LoadEvents(dir)
+	if err != nil {
+		t.Fatalf("LoadEvents failed: %v", err)
+	}
+	var types []string
+	for _, e := range events {
+		types = append(types, e.Type)
+	}
+	want := []string{domain.EventCommit, domain.EventCompleted, domain.EventStateSaved}
+	if strings.Join(types, ",") != strings.Join(want, ",") {
+		t.Fatalf("event types = %v, want %v", types, want)
+	}
+	if events[0].Commit == "" {
+		t.Error("commit event has no SHA")
+	}
+
+	replayed, histories := config.Replay(events)
+	if got := statemachine.Progress(replayed, &sprint.Tickets[0]).CurrentTask; got != 1 {
+		t.Errorf("replayed task = %d, want 1", got)
+	}
+	if len(histories) != 1 || len(histories[0].Completed) != 1 {
+		t.Errorf("replayed histories = %+v, want one completed task", histories)
+	}
+}
+
 func TestOnFail_ResetsAndRecords(t *testing.T) {
 	dir := t.TempDir()
 	testutil.InitGitRepo(t, dir)
@@ -101,7 +144,7 @@ func TestOnFail_ResetsAndRecords(t *testing.T) {
 			{Name: "TICKET-1", Tasks: []domain.Task{{Description: "task 1"}}},
 		},
 	}
-	state := &domain.State{CurrentTicket: 0, CurrentTask: 0, FailureCount: 0}
+	state := &domain.State{}
 
 	testFile := filepath.Join(dir, "uncommitted.txt")
 	if err := os.WriteFile(testFile, []byte("changes"), 0o600); err != nil {
@@ -109,16 +152,16 @@ func TestOnFail_ResetsAndRecords(t *testing.T) {
 	}
 
 	h := orchestrator.NewHandler(dir, state, sprint)
-	err := h.OnFail("TICKET-1", "task 1", "Tests failed")
+	err := h.OnFail("TICKET-1", domain.FailedAttempt{Task: "task 1", Summary: "Tests failed"})
 	if err != nil {
 		t.Fatalf("OnFail failed: %v", err)
 	}
 
-	if state.FailureCount != 1 {
-		t.Errorf("expected failure count 1, got %d", state.FailureCount)
+	if got := statemachine.Progress(state, &sprint.Tickets[0]); got.FailureCount != 1 {
+		t.Errorf("expected failure count 1, got %d", got.FailureCount)
 	}
-	if state.CurrentTask != 0 {
-		t.Errorf("expected to stay on task 0, got %d", state.CurrentTask)
+	if got := statemachine.Progress(state, &sprint.Tickets[0]); got.CurrentTask != 0 {
+		t.Errorf("expected to stay on task 0, got %d", got.CurrentTask)
 	}
 
 	if _, err := os.Stat(testFile); !os.IsNotExist(err) {
@@ -140,8 +183,8 @@ func TestOnFail_ResetsAndRecords(t *testing.T) {
 	if err != nil {
 		t.Fatalf("LoadState failed: %v", err)
 	}
-	if savedState.FailureCount != 1 {
-		t.Errorf("saved failure count mismatch: got %d", savedState.FailureCount)
+	if got := statemachine.Progress(savedState, &sprint.Tickets[0]).FailureCount; got != 1 {
+		t.Errorf("saved failure count mismatch: got %d", got)
 	}
 }
 
@@ -154,23 +197,23 @@ func TestOnFail_IncrementsToStuck(t *testing.T) {
 			{Name: "TICKET-1", Tasks: []domain.Task{{Description: "task 1"}}},
 		},
 	}
-	state := &domain.State{CurrentTicket: 0, CurrentTask: 0, FailureCount: 2}
+	state := &domain.State{Tickets: map[string]domain.TicketState{"TICKET-1": {FailingTask: "task 1", FailureCount: 2}}}
 
 	h := orchestrator.NewHandler(dir, state, sprint)
 
-	if h.IsStuck() {
+	if h.IsStuck("TICKET-1") {
 		t.Error("should not be stuck with FailureCount=2")
 	}
 
-	err := h.OnFail("TICKET-1", "task 1", "third failure")
+	err := h.OnFail("TICKET-1", domain.FailedAttempt{Task: "task 1", Summary: "third failure"})
 	if err != nil {
 		t.Fatalf("OnFail failed: %v", err)
 	}
 
-	if state.FailureCount != 3 {
-		t.Errorf("expected failure count 3, got %d", state.FailureCount)
+	if got := statemachine.Progress(state, &sprint.Tickets[0]).FailureCount; got != 3 {
+		t.Errorf("expected failure count 3, got %d", got)
 	}
-	if !h.IsStuck() {
+	if !h.IsStuck("TICKET-1") {
 		t.Error("expected IsStuck() to return true after 3 failures")
 	}
 }
@@ -184,10 +227,10 @@ func TestOnStuck_PreservesState(t *testing.T) {
 			{Name: "TICKET-1", Tasks: []domain.Task{{Description: "task 1"}}},
 		},
 	}
-	state := &domain.State{CurrentTicket: 0, CurrentTask: 0, FailureCount: statemachine.StuckThreshold}
+	state := &domain.State{Tickets: map[string]domain.TicketState{"TICKET-1": {FailingTask: "task 1", FailureCount: statemachine.StuckThreshold}}}
 
 	h := orchestrator.NewHandler(dir, state, sprint)
-	err := h.OnStuck()
+	err := h.OnStuck("TICKET-1", "")
 	if err != nil {
 		t.Fatalf("OnStuck failed: %v", err)
 	}
@@ -196,18 +239,21 @@ func TestOnStuck_PreservesState(t *testing.T) {
 	if err != nil {
 		t.Fatalf("LoadState failed: %v", err)
 	}
-	if savedState.FailureCount != statemachine.StuckThreshold {
-		t.Errorf("expected FailureCount=%d preserved, got %d", statemachine.StuckThreshold, savedState.FailureCount)
+	got := savedState.Tickets["TICKET-1"]
+	if got.FailureCount != statemachine.StuckThreshold {
+		t.Errorf("expected FailureCount=%d preserved, got %d", statemachine.StuckThreshold, got.FailureCount)
 	}
-	if savedState.CurrentTicket != 0 || savedState.CurrentTask != 0 {
+	if got.FailingTask != "task 1" || len(got.Done) != 0 {
 		t.Error("state position should be preserved")
 	}
 }
 
-// TestIsStuck_DelegatesToStateMachine uses minimal setup (empty workDir and sprint)
-// because IsStuck only reads state.FailureCount and doesn't use other Handler fields.
+// TestIsStuck_DelegatesToStateMachine uses minimal setup (empty workDir)
+// because IsStuck only reads the ticket's failure count and doesn't use other Handler fields.
 func TestIsStuck_DelegatesToStateMachine(t *testing.T) {
-	sprint := &domain.Sprint{}
+	sprint := &domain.Sprint{Tickets: []domain.Ticket{
+		{Name: "TICKET-1", Tasks: []domain.Task{{Description: "task 1"}}},
+	}}
 
 	tests := []struct {
 		name         string
@@ -223,15 +269,89 @@ func TestIsStuck_DelegatesToStateMachine(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			state := &domain.State{FailureCount: tt.failureCount}
+			state := &domain.State{Tickets: map[string]domain.TicketState{"TICKET-1": {FailingTask: "task 1", FailureCount: tt.failureCount}}}
 			h := orchestrator.NewHandler("", state, sprint)
-			if got := h.IsStuck(); got != tt.want {
+			if got := h.IsStuck("TICKET-1"); got != tt.want {
 				t.Errorf("IsStuck() = %v, want %v", got, tt.want)
 			}
 		})
 	}
 }
 
+func TestIsStuck_HonoursMaxAttempts(t *testing.T) {
+	sprint := &domain.Sprint{
+		MaxAttempts: 5,
+		Tickets: []domain.Ticket{
+			{Name: "TICKET-1", Tasks: []domain.Task{{Description: "flaky suite"}}},
+			{Name: "TICKET-2", Tasks: []domain.Task{{Description: "expensive", MaxAttempts: 1}}},
+		},
+	}
+	state := &domain.State{Tickets: map[string]domain.TicketState{
+		"TICKET-1": {FailingTask: "flaky suite", FailureCount: statemachine.StuckThreshold},
+		"TICKET-2": {FailingTask: "expensive", FailureCount: 1},
+	}}
+	h := orchestrator.NewHandler("", state, sprint)
+
+	if h.IsStuck("TICKET-1") {
+		t.Error("IsStuck(TICKET-1) = true, want false below sprint max_attempts")
+	}
+	if !h.IsStuck("TICKET-2") {
+		t.Error("IsStuck(TICKET-2) = false, want true at task max_attempts")
+	}
+}
+
+func TestIsStuck_UnknownTicket(t *testing.T) {
+	h := orchestrator.NewHandler("", &domain.State{}, &domain.Sprint{})
+	if h.IsStuck("missing") {
+		t.Error("IsStuck() = true, want false for unknown ticket")
+	}
+}
+
+func TestOnPass_UnknownTicket(t *testing.T) {
+	dir := t.TempDir()
+	testutil.InitGitRepo(t, dir)
+
+	h := orchestrator.NewHandler(dir, &domain.State{}, &domain.Sprint{})
+	if err := h.OnPass("missing", "task", "summary", nil); err == nil {
+		t.Error("OnPass() error = nil, want error for unknown ticket")
+	}
+}
+
+func TestInDir_RunsGitInWorktreeAndSharesState(t *testing.T) {
+	dir := t.TempDir()
+	testutil.InitGitRepo(t, dir)
+	wt := t.TempDir()
+	testutil.InitGitRepo(t, wt)
+
+	sprint := &domain.Sprint{
+		Tickets: []domain.Ticket{
+			{Name: "TICKET-1", Tasks: []domain.Task{{Description: "task 1"}, {Description: "task 2"}}},
+		},
+	}
+	state := &domain.State{}
+
+	writeFile(t, wt, "feature.txt", "content")
+
+	h := orchestrator.NewHandler(dir, state, sprint).InDir(wt)
+	if err := h.OnPass("TICKET-1", "task 1", "Worktree commit", nil); err != nil {
+		t.Fatalf("OnPass failed: %v", err)
+	}
+
+	assertCommitExists(t, wt, "Worktree commit")
+	if got := statemachine.Progress(state, &sprint.Tickets[0]).CurrentTask; got != 1 {
+		t.Errorf("expected shared state to advance to task 1, got %d", got)
+	}
+	if _, err := config.LoadState(dir); err != nil {
+		t.Fatalf("LoadState failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".kamaji", "state.yaml")); err != nil {
+		t.Error("state should be saved in the main work dir")
+	}
+	if _, err := os.Stat(filepath.Join(wt, ".kamaji")); !os.IsNotExist(err) {
+		t.Error("worktree should not receive .kamaji state")
+	}
+}
+
 func writeFile(t *testing.T, dir, name, content string) {
 	t.Helper()
 	path := filepath.Join(dir, name)
@@ -0,0 +1,107 @@
+package orchestrator
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sqve/kamaji/internal/output"
+)
+
+// errNoAnswer is returned to an agent whose question got no answer.
+var errNoAnswer = errors.New("no answer available; decide yourself and note the assumption in your summary")
+
+// human answers ask_human questions from the terminal. Tickets running in
+// parallel share it, so questions are asked one at a time.
+type human struct {
+	in          io.Reader
+	interactive bool           // Only lines typed while a question is shown answer it
+	read        sync.Once      // Starts reading input
+	turn        chan struct{}  // Holds a token while a question is on screen
+	lines       chan typedLine // Closed once input runs out
+}
+
+// typedLine is a line of input with the time it was read.
+type typedLine struct {
+	text string
+	at   time.Time
+}
+
+// newHuman reads answers from in, one per line. A terminal is read from the
+// start so lines typed while no question is shown can be told apart and
+// ignored. Other input, such as a file of scripted answers, is read once the
+// first question is asked and answers the questions in order.
+func newHuman(in io.Reader) *human {
+	h := &human{in: in, interactive: isTerminal(in), turn: make(chan struct{}, 1), lines: make(chan typedLine, 16)}
+	if h.interactive {
+		h.read.Do(func() { go h.readLines() })
+	}
+	return h
+}
+
+func isTerminal(in io.Reader) bool {
+	f, ok := in.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+func (h *human) readLines() {
+	scanner := bufio.NewScanner(h.in)
+	for scanner.Scan() {
+		h.lines <- typedLine{text: scanner.Text(), at: time.Now()}
+	}
+	close(h.lines)
+}
+
+// ask shows the question and waits up to timeout for an answer. It gives up
+// when ctx is cancelled or stop is closed, such as when the agent exits.
+// Waiting for another ticket's question to be answered does not count
+// against the timeout.
+func (h *human) ask(ctx context.Context, stop <-chan struct{}, timeout time.Duration, ticket, question string) (string, error) {
+	select {
+	case h.turn <- struct{}{}:
+		defer func() { <-h.turn }()
+	case <-ctx.Done():
+		return "", ctx.Err()
+	case <-stop:
+		return "", errNoAnswer
+	}
+
+	shown := time.Now()
+	h.read.Do(func() { go h.readLines() })
+	output.PrintQuestion(ticket, question)
+	expiry := time.NewTimer(timeout)
+	defer expiry.Stop()
+	for {
+		select {
+		case line, ok := <-h.lines:
+			if !ok {
+				return "", errNoAnswer
+			}
+			if h.interactive && line.at.Before(shown) {
+				continue // Typed before the question, or left over from an earlier answer
+			}
+			answer := strings.TrimSpace(line.text)
+			if answer == "" {
+				return "", errNoAnswer
+			}
+			return answer, nil
+		case <-expiry.C:
+			output.PrintQuestionExpired(timeout)
+			return "", fmt.Errorf("no answer within %s; decide yourself and note the assumption in your summary", timeout)
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-stop:
+			return "", errNoAnswer
+		}
+	}
+}
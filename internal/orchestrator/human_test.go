@@ -0,0 +1,81 @@
+package orchestrator
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHuman_IgnoresLinesTypedBeforeQuestion(t *testing.T) {
+	r, w := io.Pipe()
+	defer func() { _ = w.Close() }()
+	h := &human{in: r, interactive: true, turn: make(chan struct{}, 1), lines: make(chan typedLine, 16)}
+	h.read.Do(func() { go h.readLines() })
+
+	if _, err := io.WriteString(w, "typed early\n"); err != nil {
+		t.Fatalf("write error = %v", err)
+	}
+	time.Sleep(20 * time.Millisecond) // Let the stray line be read
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		_, _ = io.WriteString(w, "the answer\n")
+	}()
+	answer, err := h.ask(context.Background(), nil, time.Minute, "TEST-1", "Cookies or tokens?")
+	if err != nil {
+		t.Fatalf("ask() error = %v", err)
+	}
+	if answer != "the answer" {
+		t.Errorf("ask() = %q, want %q", answer, "the answer")
+	}
+}
+
+func TestHuman_ScriptedAnswersInOrder(t *testing.T) {
+	r, w := io.Pipe()
+	h := newHuman(r)
+	go func() {
+		_, _ = io.WriteString(w, "first\nsecond\n")
+		_ = w.Close()
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	for _, want := range []string{"first", "second"} {
+		answer, err := h.ask(context.Background(), nil, time.Minute, "TEST-1", "Question?")
+		if err != nil {
+			t.Fatalf("ask() error = %v", err)
+		}
+		if answer != want {
+			t.Errorf("ask() = %q, want %q", answer, want)
+		}
+	}
+	if _, err := h.ask(context.Background(), nil, time.Minute, "TEST-1", "Question?"); err != errNoAnswer {
+		t.Errorf("ask() error = %v, want errNoAnswer once input runs out", err)
+	}
+}
+
+func TestHuman_DeclinesUnansweredQuestionAfterTimeout(t *testing.T) {
+	r, w := io.Pipe()
+	defer func() { _ = w.Close() }()
+	h := &human{in: r, interactive: true, turn: make(chan struct{}, 1), lines: make(chan typedLine, 16)}
+	h.read.Do(func() { go h.readLines() })
+
+	_, err := h.ask(context.Background(), nil, 50*time.Millisecond, "TEST-1", "Cookies or tokens?")
+	if err == nil || !strings.Contains(err.Error(), "no answer within 50ms; decide yourself") {
+		t.Fatalf("ask() error = %v, want the question declined after the timeout", err)
+	}
+
+	// The next question gets its turn and a late answer is not carried over.
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		_, _ = io.WriteString(w, "tokens\n")
+	}()
+	answer, err := h.ask(context.Background(), nil, time.Minute, "TEST-2", "Which store?")
+	if err != nil {
+		t.Fatalf("ask() error = %v", err)
+	}
+	if answer != "tokens" {
+		t.Errorf("ask() = %q, want %q", answer, "tokens")
+	}
+}
@@ -0,0 +1,53 @@
+package orchestrator
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/sqve/kamaji/internal/config"
+	"github.com/sqve/kamaji/internal/domain"
+	"github.com/sqve/kamaji/internal/mcp"
+	"github.com/sqve/kamaji/internal/output"
+	"github.com/sqve/kamaji/internal/statemachine"
+)
+
+// ProposeTask queues a follow-up task for any ticket in the sprint. It is not
+// added to the sprint until accepted with kamaji proposals accept.
+func (c contextTools) ProposeTask(args mcp.ProposeTaskArgs) (int, error) {
+	ticket := &c.r.sprint.Tickets[c.ticketIndex]
+	if args.Ticket != "" {
+		if ticket = statemachine.FindTicket(c.r.sprint, args.Ticket); ticket == nil {
+			return 0, fmt.Errorf("unknown ticket %q", args.Ticket)
+		}
+	}
+	for _, task := range ticket.Tasks {
+		if task.Description == args.Description {
+			return 0, errors.New("the ticket already has a task with this description")
+		}
+	}
+
+	var from string
+	c.r.handler.view(func(state *domain.State) {
+		if info := statemachine.TicketTask(state, c.r.sprint, c.ticketIndex); info != nil {
+			from = info.Task.Description
+		}
+	})
+	proposal, err := config.AddProposal(c.r.cfg.WorkDir, domain.Proposal{
+		Ticket:      ticket.Name,
+		Description: args.Description,
+		Steps:       args.Steps,
+		Verify:      args.Verify,
+		Reason:      args.Reason,
+		Task:        from,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	_ = config.AppendEvent(c.r.cfg.WorkDir, domain.Event{
+		Type: domain.EventProposal, Ticket: ticket.Name,
+		Status: domain.ProposalPending, Summary: args.Description,
+	})
+	output.PrintInfo(fmt.Sprintf("Proposed task #%d for %s: %s", proposal.ID, ticket.Name, args.Description))
+	return proposal.ID, nil
+}
@@ -1,6 +1,9 @@
 package orchestrator
 
-import "github.com/sqve/kamaji/internal/mcp"
+import (
+	"github.com/sqve/kamaji/internal/domain"
+	"github.com/sqve/kamaji/internal/mcp"
+)
 
 // Re-export status constants from mcp for convenience.
 const (
@@ -14,6 +17,9 @@ type TaskResult struct {
 	Status   string
 	Summary  string
 	NoSignal bool
+	TimedOut bool
+	Usage    *domain.Usage // Tokens and cost the session reported, nil if it reported none
+	Plan     string        // Plan a planning session submitted
 }
 
 // PassResult creates a pass result with the given summary.
@@ -41,6 +47,46 @@ func NoSignalResult() TaskResult {
 	}
 }
 
+// TimeoutResult creates a fail result for sessions killed by a timeout.
+func TimeoutResult(reason string) TaskResult {
+	return TaskResult{
+		Status:   StatusFail,
+		Summary:  reason,
+		TimedOut: true,
+	}
+}
+
+// PlanResult creates a pass result for a planning session that submitted plan.
+func PlanResult(plan string) TaskResult {
+	return TaskResult{
+		Status:  StatusPass,
+		Summary: "plan submitted",
+		Plan:    plan,
+	}
+}
+
+// ReviewResult converts a submit_review signal to a result that passes when the
+// reviewer approved, with the reviewer's reasons as its summary.
+func ReviewResult(sig mcp.Signal) TaskResult {
+	status := mcp.StatusFail
+	if sig.Status == mcp.VerdictApprove {
+		status = mcp.StatusPass
+	}
+	return TaskResult{
+		Status:  status,
+		Summary: sig.Summary,
+	}
+}
+
+// ExitResult creates a result for agents that cannot signal, from the error
+// their process exited with.
+func ExitResult(err error) TaskResult {
+	if err != nil {
+		return FailResult("agent exited: " + err.Error())
+	}
+	return PassResult("agent exited successfully")
+}
+
 // ResultFromSignal converts an MCP signal to a TaskResult.
 // Invalid status values are normalized to fail.
 func ResultFromSignal(sig mcp.Signal) TaskResult {
@@ -63,3 +109,14 @@ func (r TaskResult) Passed() bool {
 func (r TaskResult) Failed() bool {
 	return r.Status == StatusFail
 }
+
+// addUsage adds a further session's usage to the result.
+func (r *TaskResult) addUsage(usage *domain.Usage) {
+	if usage == nil {
+		return
+	}
+	if r.Usage == nil {
+		r.Usage = &domain.Usage{}
+	}
+	r.Usage.Add(*usage)
+}
@@ -1,6 +1,7 @@
 package orchestrator_test
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/sqve/kamaji/internal/mcp"
@@ -85,6 +86,49 @@ func TestNoSignalResult_FailedReturnsTrue(t *testing.T) {
 	}
 }
 
+func TestTimeoutResult_CreatesFailWithTimedOutFlag(t *testing.T) {
+	result := orchestrator.TimeoutResult("timed out after 5m0s")
+
+	if !result.Failed() {
+		t.Error("Failed() = false, want true")
+	}
+	if !result.TimedOut {
+		t.Error("TimedOut = false, want true")
+	}
+	if result.NoSignal {
+		t.Error("NoSignal = true, want false")
+	}
+	if result.Summary != "timed out after 5m0s" {
+		t.Errorf("Summary = %q, want %q", result.Summary, "timed out after 5m0s")
+	}
+}
+
+func TestExitResult(t *testing.T) {
+	if result := orchestrator.ExitResult(nil); !result.Passed() {
+		t.Errorf("ExitResult(nil) = %+v, want pass", result)
+	}
+
+	result := orchestrator.ExitResult(errors.New("exit status 1"))
+	if !result.Failed() {
+		t.Error("Failed() = false, want true")
+	}
+	if result.Summary != "agent exited: exit status 1" {
+		t.Errorf("Summary = %q, want %q", result.Summary, "agent exited: exit status 1")
+	}
+}
+
+func TestReviewResult(t *testing.T) {
+	approved := orchestrator.ReviewResult(mcp.Signal{Tool: mcp.SignalToolSubmitReview, Status: mcp.VerdictApprove, Summary: "looks right"})
+	if !approved.Passed() || approved.Summary != "looks right" {
+		t.Errorf("ReviewResult(approve) = %+v, want pass with reasons", approved)
+	}
+
+	rejected := orchestrator.ReviewResult(mcp.Signal{Tool: mcp.SignalToolSubmitReview, Status: mcp.VerdictReject, Summary: "no tests"})
+	if !rejected.Failed() || rejected.Summary != "no tests" {
+		t.Errorf("ReviewResult(reject) = %+v, want fail with reasons", rejected)
+	}
+}
+
 func TestResultFromSignal_PassSignal(t *testing.T) {
 	signal := mcp.Signal{
 		Tool:    mcp.SignalToolTaskComplete,
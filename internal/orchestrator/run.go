@@ -3,45 +3,33 @@ package orchestrator
 import (
 	"context"
 	"errors"
+	"fmt"
+	"io"
 	"os"
+	"strings"
+	"sync"
+	"time"
 
+	"github.com/sqve/kamaji/internal/agent"
 	"github.com/sqve/kamaji/internal/config"
 	"github.com/sqve/kamaji/internal/domain"
 	"github.com/sqve/kamaji/internal/git"
 	"github.com/sqve/kamaji/internal/mcp"
 	"github.com/sqve/kamaji/internal/output"
-	"github.com/sqve/kamaji/internal/process"
 	"github.com/sqve/kamaji/internal/prompt"
 	"github.com/sqve/kamaji/internal/statemachine"
+	"github.com/sqve/kamaji/internal/verify"
 )
 
-// ProcessSpawner abstracts process creation for testing.
-type ProcessSpawner interface {
-	Spawn(cfg process.SpawnConfig) (*process.SpawnResult, error)
-}
-
-// defaultSpawner uses the real process.SpawnClaude.
-type defaultSpawner struct{}
-
-func (defaultSpawner) Spawn(cfg process.SpawnConfig) (*process.SpawnResult, error) {
-	return process.SpawnClaude(cfg)
-}
-
-// commandSpawner runs an arbitrary command instead of Claude.
-type commandSpawner struct {
-	cmd string
-}
-
-func (s commandSpawner) Spawn(cfg process.SpawnConfig) (*process.SpawnResult, error) {
-	return process.SpawnCommand(s.cmd, cfg)
-}
-
 // RunConfig configures the Run function.
 type RunConfig struct {
-	WorkDir    string         // Required: project directory
-	SprintPath string         // Required: path to kamaji.yaml
-	Spawner    ProcessSpawner // Optional: defaults to real spawner
-	SpawnerCmd string         // Optional: override spawner with command
+	WorkDir       string        // Required: project directory
+	SprintPath    string        // Required: path to kamaji.yaml
+	Agent         agent.Adapter // Optional: defaults to the agent configured in kamaji.yaml
+	SpawnerCmd    string        // Optional: run this command as a custom agent instead
+	Parallel      int           // Optional: max tickets run at once in worktrees, defaults to 1
+	AcceptChanges bool          // Optional: drop progress on tickets and tasks removed from kamaji.yaml instead of refusing
+	Input         io.Reader     // Optional: where ask_human answers are read, defaults to os.Stdin
 }
 
 // RunResult contains the outcome of a sprint execution.
@@ -52,7 +40,9 @@ type RunResult struct {
 	StuckReason string // last failure summary if stuck
 }
 
-// Run executes a sprint, processing tasks sequentially until completion or stuck.
+// Run executes a sprint until completion or stuck. Tickets run in dependency
+// order; with Parallel > 1, independent tickets run concurrently, each in its
+// own git worktree with its own agent and MCP server.
 func Run(ctx context.Context, cfg RunConfig) (*RunResult, error) {
 	if cfg.WorkDir == "" {
 		return nil, errors.New("WorkDir is required")
@@ -61,6 +51,12 @@ func Run(ctx context.Context, cfg RunConfig) (*RunResult, error) {
 		return nil, errors.New("SprintPath is required")
 	}
 
+	unlock, err := config.AcquireRunLock(cfg.WorkDir)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
 	sprint, err := config.LoadSprint(cfg.SprintPath)
 	if err != nil {
 		return nil, err
@@ -71,71 +67,282 @@ func Run(ctx context.Context, cfg RunConfig) (*RunResult, error) {
 		return nil, err
 	}
 
+	changed, err := reconcileState(sprint, state, cfg.AcceptChanges, false)
+	if err != nil {
+		return nil, err
+	}
+	if changed {
+		if err := config.SaveState(cfg.WorkDir, state); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := config.AppendEvent(cfg.WorkDir, domain.Event{
+		Type: domain.EventSprintStart, Summary: sprint.Name, State: state,
+	}); err != nil {
+		return nil, err
+	}
+
 	if len(sprint.Tickets) == 0 {
 		output.PrintInfo("Sprint has no tasks")
 		return &RunResult{Success: true}, nil
 	}
 
-	server := mcp.NewServer(mcp.WithPort(0))
-	port, err := server.Start()
+	adapter := cfg.Agent
+	if adapter == nil {
+		agentCfg := sprint.Agent
+		if cfg.SpawnerCmd != "" {
+			agentCfg = domain.Agent{Name: domain.AgentCustom, Command: []string{cfg.SpawnerCmd}}
+		}
+		if adapter, err = agent.New(agentCfg); err != nil {
+			return nil, err
+		}
+	}
+
+	input := cfg.Input
+	if input == nil {
+		input = os.Stdin
+	}
+	r := &runner{
+		cfg:     cfg,
+		sprint:  sprint,
+		handler: NewHandler(cfg.WorkDir, state, sprint),
+		agent:   adapter,
+		human:   newHuman(input),
+	}
+	result, err := r.run(ctx)
 	if err != nil {
-		return nil, err
+		_ = config.AppendEvent(cfg.WorkDir, domain.Event{Type: domain.EventInterrupted, Summary: err.Error()})
+	} else if result.Success {
+		_ = config.AppendEvent(cfg.WorkDir, domain.Event{Type: domain.EventSprintComplete})
 	}
-	//nolint:contextcheck // Fresh context needed since caller context may be cancelled
-	defer func() { _ = server.Shutdown(context.Background()) }()
+	return result, err
+}
 
-	handler := NewHandler(cfg.WorkDir, state, sprint)
+// ErrSprintChanged is returned when saved progress refers to tickets or tasks
+// that are no longer in kamaji.yaml.
+var ErrSprintChanged = errors.New("kamaji.yaml changed under saved state")
+
+// reconcileState checks saved progress against the sprint, first migrating
+// state saved by earlier releases and moving progress to newly set ids.
+// Progress follows tickets and tasks by key, so inserted and reordered tasks
+// only warn. Progress on removed or renamed tickets and tasks is refused
+// unless accept is set, in which case it is dropped. changed reports whether
+// state needs saving. A dry run, which never saves, reports what would change.
+func reconcileState(sprint *domain.Sprint, state *domain.State, accept, dryRun bool) (changed bool, err error) {
+	migrated, adopted, dropping := "Migrated", "Moved", "Dropping"
+	if dryRun {
+		migrated, adopted, dropping = "Would migrate", "Would move", "Would drop"
+	}
+	if statemachine.MigrateState(state, sprint) {
+		output.PrintInfo(migrated + " .kamaji/state.yaml from an earlier kamaji release")
+		changed = true
+	}
+	if statemachine.AdoptIDs(state, sprint) {
+		output.PrintInfo(adopted + " saved progress to the ids set in kamaji.yaml")
+		changed = true
+	}
 
-	spawner := cfg.Spawner
-	if spawner == nil {
-		if cfg.SpawnerCmd != "" {
-			spawner = commandSpawner{cmd: cfg.SpawnerCmd}
-		} else {
-			spawner = defaultSpawner{}
-		}
+	drift := statemachine.DetectDrift(state, sprint)
+	for _, note := range drift.Remapped {
+		output.PrintWarning(note)
+	}
+	if len(drift.Orphaned) == 0 {
+		return changed, nil
 	}
 
-	var tasksRun int
+	if !accept {
+		return false, fmt.Errorf("%w:\n  %s\nRestore kamaji.yaml, or rerun with --accept-changes to drop this progress",
+			ErrSprintChanged, strings.Join(drift.Orphaned, "\n  "))
+	}
+	for _, note := range drift.Orphaned {
+		output.PrintWarning(dropping + " progress: " + note)
+	}
+	statemachine.PruneDrift(state, sprint)
+	return true, nil
+}
+
+// runner schedules tickets onto workers as their dependencies complete.
+type runner struct {
+	cfg     RunConfig
+	sprint  *domain.Sprint
+	handler *Handler // Owns state; all state access goes through handler.view
+	agent   agent.Adapter
+	human   *human
+
+	mu       sync.Mutex
+	tasksRun int
+}
+
+// ticketOutcome reports how a worker finished a ticket.
+type ticketOutcome struct {
+	index       int
+	stuck       bool
+	stuckReason string
+	err         error
+}
+
+func (r *runner) parallel() bool {
+	return r.cfg.Parallel > 1
+}
+
+func (r *runner) run(ctx context.Context) (*RunResult, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	workers := max(r.cfg.Parallel, 1)
+	running := make(map[int]bool)
+	outcomes := make(chan ticketOutcome)
+
+	var (
+		firstErr    error
+		stuck       bool
+		stuckReason string
+	)
 
 	for {
-		select {
-		case <-ctx.Done():
-			return &RunResult{TasksRun: tasksRun}, ctx.Err()
-		default:
+		if firstErr == nil && !stuck {
+			if err := ctx.Err(); err != nil {
+				firstErr = err
+			}
 		}
 
-		taskInfo := statemachine.NextTask(state, sprint)
-		if taskInfo == nil {
-			output.PrintSprintComplete(sprint, state)
-			return &RunResult{Success: true, TasksRun: tasksRun}, nil
+		// Stop launching tickets once anything fails; in-flight tickets finish.
+		if firstErr == nil && !stuck {
+			var ready []int
+			r.handler.view(func(state *domain.State) {
+				ready = statemachine.ReadyTickets(state, r.sprint)
+			})
+			for _, idx := range ready {
+				if len(running) >= workers {
+					break
+				}
+				if running[idx] {
+					continue
+				}
+				running[idx] = true
+				go func() { outcomes <- r.runTicket(ctx, idx) }()
+			}
+		}
+
+		if len(running) == 0 {
+			break
 		}
 
-		// Create branch only at the start of a new ticket. CurrentTask==0 means we're
-		// on the first task, and FailureCount==0 means this is not a retry. On retry,
-		// the branch already exists from the initial attempt. When advancing to a new
-		// ticket, statemachine resets both counters, triggering branch creation again.
-		if state.CurrentTask == 0 && state.FailureCount == 0 {
-			if err := createTicketBranch(cfg.WorkDir, sprint.BaseBranch, taskInfo.Ticket); err != nil {
-				return nil, err
+		outcome := <-outcomes
+		delete(running, outcome.index)
+
+		switch {
+		case outcome.err != nil:
+			if firstErr == nil {
+				firstErr = outcome.err
+				cancel()
 			}
+		case outcome.stuck && !stuck:
+			stuck = true
+			stuckReason = outcome.stuckReason
 		}
+	}
 
-		output.PrintTaskStart(taskInfo, sprint)
+	r.mu.Lock()
+	tasksRun := r.tasksRun
+	r.mu.Unlock()
 
-		result, err := runTask(ctx, &taskContext{
-			cfg:      cfg,
-			spawner:  spawner,
-			sprint:   sprint,
-			state:    state,
+	if firstErr != nil {
+		return &RunResult{TasksRun: tasksRun}, firstErr
+	}
+	if stuck {
+		return &RunResult{TasksRun: tasksRun, Stuck: true, StuckReason: stuckReason}, nil
+	}
+
+	usage := r.usage()
+	var complete bool
+	r.handler.view(func(state *domain.State) {
+		complete = statemachine.SprintComplete(state, r.sprint)
+		if complete {
+			output.PrintSprintComplete(r.sprint, state, usage)
+		}
+	})
+	if !complete {
+		return &RunResult{TasksRun: tasksRun}, errors.New("no runnable tickets: remaining tickets depend on incomplete tickets")
+	}
+	return &RunResult{Success: true, TasksRun: tasksRun}, nil
+}
+
+// usage sums the usage recorded in the history of the sprint's tickets.
+func (r *runner) usage() domain.Usage {
+	var histories []*domain.TicketHistory
+	for i := range r.sprint.Tickets {
+		h, err := config.LoadTicketHistory(r.cfg.WorkDir, r.sprint.Tickets[i].Name)
+		if err != nil {
+			output.PrintWarning(err.Error())
+			continue
+		}
+		histories = append(histories, h)
+	}
+	return config.GetAllHistoriesSummary(histories).Usage
+}
+
+// runTicket executes the ticket's remaining tasks until it completes or gets stuck.
+func (r *runner) runTicket(ctx context.Context, ticketIndex int) ticketOutcome {
+	ticket := &r.sprint.Tickets[ticketIndex]
+
+	dir, err := r.prepareTicket(ticket)
+	if err != nil {
+		return ticketOutcome{index: ticketIndex, err: err}
+	}
+	handler := r.handler.InDir(dir)
+
+	tools := contextTools{r: r, ticketIndex: ticketIndex}
+	server := mcp.NewServer(mcp.WithPort(0), mcp.WithContextProvider(tools), mcp.WithTaskProposer(tools))
+	port, err := server.Start()
+	if err != nil {
+		return ticketOutcome{index: ticketIndex, err: err}
+	}
+	//nolint:contextcheck // Fresh context needed since caller context may be cancelled
+	defer func() { _ = server.Shutdown(context.Background()) }()
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return ticketOutcome{index: ticketIndex, err: err}
+		}
+
+		var taskInfo *statemachine.TaskInfo
+		var failures int
+		r.handler.view(func(state *domain.State) {
+			taskInfo = statemachine.TicketTask(state, r.sprint, ticketIndex)
+			failures = statemachine.Progress(state, ticket).FailureCount
+		})
+		if taskInfo == nil {
+			if r.parallel() {
+				if err := git.RemoveWorktree(r.cfg.WorkDir, dir); err != nil {
+					output.PrintWarning(err.Error())
+				}
+			}
+			return ticketOutcome{index: ticketIndex}
+		}
+
+		output.PrintTaskStart(taskInfo, r.sprint)
+
+		tc := &taskContext{
+			cfg:      r.cfg,
+			agent:    r.agent,
+			sprint:   r.sprint,
+			workDir:  dir,
 			port:     port,
 			taskInfo: taskInfo,
+			failures: failures,
 			server:   server,
-		})
+			human:    r.human,
+		}
+		result, err := runTask(ctx, tc)
 		if err != nil {
-			return &RunResult{TasksRun: tasksRun}, err
+			return ticketOutcome{index: ticketIndex, err: err}
 		}
 
-		tasksRun++
+		r.mu.Lock()
+		r.tasksRun++
+		r.mu.Unlock()
 
 		output.PrintSignal(mcp.Signal{
 			Tool:    mcp.SignalToolTaskComplete,
@@ -143,125 +350,603 @@ func Run(ctx context.Context, cfg RunConfig) (*RunResult, error) {
 			Summary: result.Summary,
 		})
 
+		if result.Passed() && taskInfo.Task.VerifyCmd != "" {
+			result, err = verifyTask(ctx, dir, taskInfo.Task, statemachine.VerifyTimeout(r.sprint, taskInfo), result)
+			if err != nil {
+				return ticketOutcome{index: ticketIndex, err: err}
+			}
+			status := mcp.StatusPass
+			if !result.Passed() {
+				status = mcp.StatusFail
+			}
+			if err := config.AppendEvent(r.cfg.WorkDir, domain.Event{
+				Type: domain.EventVerify, Ticket: ticket.Name, Task: taskInfo.Task.Description, Status: status, Summary: result.Summary,
+			}); err != nil {
+				return ticketOutcome{index: ticketIndex, err: err}
+			}
+		}
+
+		if result.Passed() && statemachine.Review(r.sprint, taskInfo) {
+			result, err = reviewTask(ctx, tc, result)
+			if err != nil {
+				return ticketOutcome{index: ticketIndex, err: err}
+			}
+		}
+
 		if result.Passed() {
-			if err := handler.OnPass(taskInfo.Ticket.Name, taskInfo.Task.Description, result.Summary); err != nil {
-				return nil, err
+			if err := handler.OnPass(ticket.Name, taskInfo.Task.Description, result.Summary, result.Usage); err != nil {
+				return ticketOutcome{index: ticketIndex, err: err}
 			}
-		} else {
-			if err := handler.OnFail(taskInfo.Ticket.Name, taskInfo.Task.Description, result.Summary); err != nil {
-				return nil, err
+			continue
+		}
+
+		if err := handler.OnFail(ticket.Name, domain.FailedAttempt{
+			Task: taskInfo.Task.Description, Summary: result.Summary, TimedOut: result.TimedOut, Usage: result.Usage,
+		}); err != nil {
+			return ticketOutcome{index: ticketIndex, err: err}
+		}
+
+		if handler.IsStuck(ticket.Name) {
+			if err := handler.OnStuck(ticket.Name, result.Summary); err != nil {
+				return ticketOutcome{index: ticketIndex, err: err}
 			}
+			return ticketOutcome{index: ticketIndex, stuck: true, stuckReason: result.Summary}
+		}
 
-			if handler.IsStuck() {
-				if err := handler.OnStuck(); err != nil {
-					return nil, err
-				}
-				return &RunResult{TasksRun: tasksRun, Stuck: true, StuckReason: result.Summary}, nil
+		if err := r.waitBeforeRetry(ctx, taskInfo); err != nil {
+			return ticketOutcome{index: ticketIndex, err: err}
+		}
+	}
+}
+
+// waitBeforeRetry sleeps for the task's retry delay, returning early with the
+// context's error if it is cancelled.
+func (r *runner) waitBeforeRetry(ctx context.Context, taskInfo *statemachine.TaskInfo) error {
+	var failures int
+	r.handler.view(func(state *domain.State) {
+		failures = statemachine.Progress(state, taskInfo.Ticket).FailureCount
+	})
+
+	delay := statemachine.RetryDelay(r.sprint, taskInfo, failures)
+	if delay <= 0 {
+		return nil
+	}
+
+	output.PrintRetryDelay(delay)
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// prepareTicket returns the directory the ticket's agent works in. Sequential
+// runs check out the ticket branch in the work dir; parallel runs give each
+// ticket its own worktree under .kamaji/worktrees.
+func (r *runner) prepareTicket(ticket *domain.Ticket) (string, error) {
+	if r.parallel() {
+		dir := config.WorktreeDir(r.cfg.WorkDir, ticket.Name)
+		created, err := git.AddWorktree(r.cfg.WorkDir, dir, r.sprint.BaseBranch, ticket.Branch)
+		if err != nil {
+			return "", err
+		}
+		if created {
+			output.PrintTicketStart(ticket)
+			output.PrintBranchCreated(ticket.Branch)
+			if err := r.journalBranch(ticket); err != nil {
+				return "", err
+			}
+		}
+		if err := r.mergeDependencies(dir, ticket); err != nil {
+			return "", err
+		}
+		return dir, nil
+	}
+
+	var progress statemachine.TicketProgress
+	r.handler.view(func(state *domain.State) {
+		progress = statemachine.Progress(state, ticket)
+	})
+
+	// Create branch only at the start of a new ticket. Once any task has passed
+	// or failed, the branch already exists from the first attempt.
+	if !progress.Started() {
+		created, err := createTicketBranch(r.cfg.WorkDir, r.sprint.BaseBranch, ticket)
+		if err != nil {
+			return "", err
+		}
+		if created {
+			if err := r.journalBranch(ticket); err != nil {
+				return "", err
 			}
 		}
 	}
+
+	// An existing branch may have HEAD elsewhere: a resumed run, a ticket
+	// moved back by goto or reset, or one reopened by an accepted proposal.
+	if err := git.CheckoutBranch(r.cfg.WorkDir, ticket.Branch); err != nil {
+		return "", err
+	}
+	if err := r.mergeDependencies(r.cfg.WorkDir, ticket); err != nil {
+		return "", err
+	}
+	return r.cfg.WorkDir, nil
+}
+
+// mergeDependencies brings completed dependency branches into the ticket
+// branch checked out in dir. It runs every time a ticket is prepared and skips
+// branches already merged, so a merge that stopped an earlier run is retried
+// rather than lost when that run's branch or worktree is reused.
+func (r *runner) mergeDependencies(dir string, ticket *domain.Ticket) error {
+	for _, name := range ticket.DependsOn {
+		dep := statemachine.FindTicket(r.sprint, name)
+		if dep == nil || dep.Branch == "" {
+			continue
+		}
+		merged, err := git.IsAncestor(dir, dep.Branch)
+		if err != nil {
+			return err
+		}
+		if merged {
+			continue
+		}
+		if err := git.MergeBranch(dir, dep.Branch); err != nil {
+			return err
+		}
+		output.PrintInfo("Merged dependency: " + dep.Branch)
+		if err := config.AppendEvent(r.cfg.WorkDir, domain.Event{
+			Type: domain.EventDependencyMerged, Ticket: ticket.Name, Branch: dep.Branch,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
-func createTicketBranch(workDir, baseBranch string, ticket *domain.Ticket) error {
+// journalBranch records the creation of the ticket's branch.
+func (r *runner) journalBranch(ticket *domain.Ticket) error {
+	return config.AppendEvent(r.cfg.WorkDir, domain.Event{
+		Type: domain.EventBranchCreated, Ticket: ticket.Name, Branch: ticket.Branch,
+	})
+}
+
+func createTicketBranch(workDir, baseBranch string, ticket *domain.Ticket) (bool, error) {
 	output.PrintTicketStart(ticket)
 
 	err := git.CreateBranch(workDir, baseBranch, ticket.Branch)
 	if err != nil {
 		if errors.Is(err, git.ErrBranchExists) {
 			output.PrintInfo("Using existing branch: " + ticket.Branch)
-			return nil
+			return false, nil
 		}
-		return err
+		return false, err
 	}
 
 	output.PrintBranchCreated(ticket.Branch)
-	return nil
+	return true, nil
+}
+
+// maxVerifyOutput bounds how much verify output is fed back into the retry prompt.
+const maxVerifyOutput = 2000
+
+// verifyTask runs the task's verify command after the agent reports a pass.
+// A failing check, including one that outruns timeout, turns the result into a
+// failure carrying the command output, so the next attempt sees why
+// verification rejected the work.
+func verifyTask(ctx context.Context, workDir string, task *domain.Task, timeout time.Duration, result TaskResult) (TaskResult, error) {
+	output.PrintVerifyStart(task.VerifyCmd)
+
+	vr, err := verify.Run(ctx, workDir, task.VerifyCmd, task.Done, timeout)
+	if err != nil {
+		return TaskResult{}, err
+	}
+
+	if vr.Passed {
+		output.PrintVerifyPassed()
+		return result, nil
+	}
+
+	output.PrintVerifyFailed(vr.Reason)
+
+	summary := fmt.Sprintf("verification failed: %s (%s)", task.VerifyCmd, vr.Reason)
+	if out := tail(strings.TrimSpace(vr.Output), maxVerifyOutput); out != "" {
+		summary += "\n" + out
+	}
+	failed := FailResult(summary)
+	failed.Usage = result.Usage
+	return failed, nil
+}
+
+// tail returns the last maxLen runes of s, marking truncation with a leading ellipsis.
+func tail(s string, maxLen int) string {
+	runes := []rune(s)
+	if len(runes) <= maxLen {
+		return s
+	}
+	return "..." + string(runes[len(runes)-maxLen:])
 }
 
 // taskContext groups parameters needed for task execution.
 type taskContext struct {
 	cfg      RunConfig
-	spawner  ProcessSpawner
+	agent    agent.Adapter
 	sprint   *domain.Sprint
-	state    *domain.State
+	workDir  string // Where the agent runs, a worktree when tickets run in parallel
 	port     int
 	taskInfo *statemachine.TaskInfo
+	failures int // Failed attempts so far, which picks the model from an escalation list
 	server   *mcp.Server
+	human    *human
 }
 
+// runTask runs one attempt at the task. When planning applies, a read-only
+// planning session comes first and its plan is passed to the session that
+// makes the change. Each session's parsed output is saved to its own
+// transcript and the result carries the usage both reported.
 func runTask(ctx context.Context, tc *taskContext) (TaskResult, error) {
-	promptText, err := prompt.AssembleContext(tc.sprint, tc.state, tc.cfg.WorkDir)
+	var plan string
+	var usage *domain.Usage
+	if statemachine.Plan(tc.sprint, tc.taskInfo) {
+		if !tc.agent.MCP() {
+			output.PrintWarning(tc.agent.Name() + " cannot submit a plan, skipping planning")
+		} else {
+			result, err := runPlanning(ctx, tc)
+			if err != nil || result.Plan == "" {
+				return result, err
+			}
+			plan, usage = result.Plan, result.Usage
+		}
+	}
+
+	promptText, err := prompt.AssembleTaskContext(tc.sprint, tc.taskInfo, tc.cfg.WorkDir, tc.workDir, plan)
 	if err != nil {
 		return TaskResult{}, err
 	}
+	result, err := runAttempt(ctx, tc, promptText, tc.taskInfo.Task.Key(), agent.SessionTask)
+	if err != nil {
+		return TaskResult{}, err
+	}
+	result.addUsage(usage)
+	return result, nil
+}
 
-	spawnResult, err := tc.spawner.Spawn(process.SpawnConfig{
-		Prompt:  promptText,
-		MCPPort: tc.port,
-		WorkDir: tc.cfg.WorkDir,
-		Stdout:  output.NewInfoWriter(os.Stdout),
-		Stderr:  output.NewErrorWriter(os.Stderr),
-	})
+// runPlanning runs the planning session and records the plan it submitted.
+// Anything the session changed is discarded, so the implementation session
+// starts from a clean tree. A session that ends without a plan returns its
+// result as the attempt's.
+func runPlanning(ctx context.Context, tc *taskContext) (TaskResult, error) {
+	promptText, err := prompt.AssemblePlanContext(tc.sprint, tc.taskInfo, tc.cfg.WorkDir, tc.workDir)
 	if err != nil {
 		return TaskResult{}, err
 	}
-	defer func() {
-		if spawnResult.ConfigPath != "" {
-			_ = os.Remove(spawnResult.ConfigPath)
+	output.PrintInfo("Planning")
+	result, err := runAttempt(ctx, tc, promptText, "plan "+tc.taskInfo.Task.Key(), agent.SessionPlan)
+	if err != nil {
+		return TaskResult{}, err
+	}
+	if err := git.ResetToHead(tc.workDir); err != nil {
+		return TaskResult{}, fmt.Errorf("discarding planning changes: %w", err)
+	}
+	if result.Plan == "" {
+		if result.NoSignal {
+			result.Summary = "planning session exited without a plan"
 		}
-	}()
+		return result, nil
+	}
+
+	task := tc.taskInfo.Task.Description
+	if err := config.RecordPlan(tc.cfg.WorkDir, tc.taskInfo.Ticket.Name, task, result.Plan); err != nil {
+		output.PrintWarning("Failed to record plan: " + err.Error())
+	}
+	tc.journal(domain.Event{Type: domain.EventPlan, Summary: result.Plan})
+	output.PrintInfo("Plan:\n" + result.Plan)
+	return result, nil
+}
+
+// reviewTask has a fresh read-only session judge a reported pass from the
+// task, the staged diff and the verify criteria. A rejection, or a review
+// that ends without a verdict, turns the pass into a failed attempt whose
+// reasons reach the next prompt through the ticket history.
+func reviewTask(ctx context.Context, tc *taskContext, result TaskResult) (TaskResult, error) {
+	if !tc.agent.MCP() {
+		output.PrintWarning(tc.agent.Name() + " cannot submit a review, skipping review")
+		return result, nil
+	}
+	diff, err := git.StagedDiff(tc.workDir)
+	if err != nil {
+		return TaskResult{}, err
+	}
+
+	rules := prompt.TaskRules(tc.sprint, tc.taskInfo.Ticket, tc.taskInfo.Task)
+	promptText := prompt.BuildReviewPrompt(tc.taskInfo, rules, result.Summary, diff)
+	output.PrintInfo("Reviewing")
+	review, err := runAttempt(ctx, tc, promptText, "review "+tc.taskInfo.Task.Key(), agent.SessionReview)
+	if err != nil {
+		return TaskResult{}, err
+	}
+	result.addUsage(review.Usage)
 
+	after, err := git.StagedDiff(tc.workDir)
+	if err != nil {
+		return TaskResult{}, err
+	}
+	verdict, summary := mcp.VerdictReject, "review rejected: "+review.Summary
+	switch {
+	case after != diff:
+		summary = "review session changed the work tree"
+	case review.NoSignal || review.TimedOut:
+		summary = "review ended without a verdict: " + review.Summary
+	case review.Passed():
+		verdict, summary = mcp.VerdictApprove, review.Summary
+	}
+	tc.journal(domain.Event{Type: domain.EventReview, Status: verdict, Summary: summary})
+	if verdict == mcp.VerdictApprove {
+		return result, nil
+	}
+	failed := FailResult(summary)
+	failed.Usage = result.Usage
+	return failed, nil
+}
+
+// runAttempt runs one agent session, saving its parsed output to a transcript
+// named after transcriptKey. The result carries the usage the session
+// reported.
+func runAttempt(ctx context.Context, tc *taskContext, promptText, transcriptKey, kind string) (TaskResult, error) {
+	transcript, err := config.CreateTranscript(tc.cfg.WorkDir, tc.taskInfo.Ticket.Name, transcriptKey)
+	if err != nil {
+		return TaskResult{}, err
+	}
+	// Agent output is only written by the process's copy goroutine, which
+	// finishes before Wait returns, so the parser needs no locking. Progress
+	// reports arrive on the session's goroutine, so writes to the transcript
+	// are serialized.
+	var mu sync.Mutex
+	record := func(e domain.TranscriptEvent) {
+		mu.Lock()
+		defer mu.Unlock()
+		_ = transcript.Write(e)
+		output.PrintTranscriptEvent(e)
+	}
+	var usage *domain.Usage
+	stream := agent.NewStreamParser(tc.agent, func(e domain.TranscriptEvent) {
+		if e.Usage != nil {
+			if usage == nil {
+				usage = &domain.Usage{}
+			}
+			usage.Add(*e.Usage)
+		}
+		record(e)
+	})
+
+	result, err := runSession(ctx, tc, promptText, kind, stream, record)
+	stream.Flush()
+	if cerr := transcript.Close(); cerr != nil {
+		output.PrintWarning(cerr.Error())
+	}
+	if err != nil {
+		return TaskResult{}, err
+	}
+	result.Usage = usage
+	return result, nil
+}
+
+// runSession spawns the agent with its output teed into stream and waits for
+// its signal, the process to exit or a timeout. Planning and review sessions
+// end when the agent submits a plan or a review. Progress reports are passed
+// to record for the transcript. Signals from earlier sessions are discarded.
+func runSession(ctx context.Context, tc *taskContext, promptText, kind string, stream io.Writer, record func(domain.TranscriptEvent)) (TaskResult, error) {
+	timeout, idle := statemachine.Timeouts(tc.sprint, tc.taskInfo)
+	wd := startWatchdog(timeout, idle)
+	defer wd.Stop()
+
+	session, token, err := tc.server.NewSession()
+	if err != nil {
+		return TaskResult{}, err
+	}
+	defer tc.server.EndSession()
+
+	model := statemachine.Model(tc.sprint, tc.taskInfo, tc.failures)
+	if model != "" {
+		output.PrintInfo("Model: " + model)
+	}
+	spawned, err := agent.Spawn(tc.agent, agent.Session{
+		Prompt:   promptText,
+		MCPPort:  tc.port,
+		MCPToken: token,
+		WorkDir:  tc.workDir,
+		Model:    model,
+		Effort:   statemachine.Effort(tc.sprint, tc.taskInfo),
+		Kind:     kind,
+		Stdout:   io.MultiWriter(stream, wd),
+		Stderr:   io.MultiWriter(output.NewErrorWriter(os.Stderr), wd),
+	})
+	if err != nil {
+		return TaskResult{}, err
+	}
+	tc.journal(domain.Event{Type: domain.EventSpawn, Model: model})
+	defer spawned.Cleanup()
+
+	var exitErr error
 	done := make(chan struct{})
 	go func() {
-		_ = spawnResult.Process.Wait()
+		exitErr = spawned.Process.Wait()
 		close(done)
 	}()
 
-	ticketName := tc.taskInfo.Ticket.Name
 	for {
 		select {
 		case <-ctx.Done():
-			_ = spawnResult.Process.Kill()
+			_ = spawned.Process.Kill()
 			<-done
 			return TaskResult{}, ctx.Err()
+		case <-wd.Expired():
+			output.PrintWarning("Agent " + wd.Reason() + ", stopping session")
+			tc.journal(domain.Event{Type: domain.EventTimeout, Summary: wd.Reason()})
+			_ = spawned.Process.Kill()
+			<-done
+			return TimeoutResult(wd.Reason()), nil
+		case q := <-tc.server.Questions():
+			// Waiting on a human does not count against the session's limits.
+			wd.Pause()
+			tc.answer(ctx, done, q)
+			wd.Resume()
 		case sig, ok := <-tc.server.Signals():
 			if !ok {
-				_ = spawnResult.Process.Kill()
+				_ = spawned.Process.Kill()
 				<-done
 				return NoSignalResult(), nil
 			}
-			if sig.Tool == mcp.SignalToolNoteInsight {
-				_ = config.RecordInsight(tc.cfg.WorkDir, ticketName, sig.Summary)
-				output.PrintSignal(sig)
+			if tc.discardStale(sig, session) {
 				continue
 			}
-			select {
-			case <-done:
-			case <-ctx.Done():
-				_ = spawnResult.Process.Kill()
-				<-done
-				return TaskResult{}, ctx.Err()
+			// Any signal, a progress report included, shows the agent is alive.
+			wd.Touch()
+			result, final := tc.handleSignal(sig, kind, record)
+			if !final {
+				continue
+			}
+			// The agent reported its result, so a session that then fails to
+			// exit is stopped without discarding that result. Signals sent
+			// meanwhile are still taken so the agent is not left waiting on
+			// them; a repeated result does not replace the first.
+			signals := tc.server.Signals()
+			for {
+				select {
+				case <-done:
+					return result, nil
+				case <-wd.Expired():
+					_ = spawned.Process.Kill()
+					<-done
+					return result, nil
+				case <-ctx.Done():
+					_ = spawned.Process.Kill()
+					<-done
+					return TaskResult{}, ctx.Err()
+				case sig, ok := <-signals:
+					if !ok {
+						signals = nil // Closed on shutdown; keep waiting for the exit
+						continue
+					}
+					if !tc.discardStale(sig, session) {
+						_, _ = tc.handleSignal(sig, kind, record)
+					}
+				}
 			}
-			return ResultFromSignal(sig), nil
 		case <-done:
 			// Process exited. Drain any pending signals to capture insights and
 			// task completion that arrived concurrently with process exit.
+			// Agents without MCP cannot signal, so their exit status decides.
+			noSignal := NoSignalResult()
+			if !tc.agent.MCP() {
+				noSignal = ExitResult(exitErr)
+			}
 			for {
 				select {
 				case sig, ok := <-tc.server.Signals():
 					if !ok {
-						return NoSignalResult(), nil
+						return noSignal, nil
 					}
-					if sig.Tool == mcp.SignalToolNoteInsight {
-						_ = config.RecordInsight(tc.cfg.WorkDir, ticketName, sig.Summary)
-						output.PrintSignal(sig)
+					if tc.discardStale(sig, session) {
 						continue
 					}
-					return ResultFromSignal(sig), nil
+					if result, final := tc.handleSignal(sig, kind, record); final {
+						return result, nil
+					}
 				default:
-					return NoSignalResult(), nil
+					return noSignal, nil
 				}
 			}
 		}
 	}
 }
+
+// discardStale reports whether sig came from a session other than the current
+// one, such as an earlier attempt's agent that outlived its session, and
+// journals it as discarded if so.
+func (tc *taskContext) discardStale(sig mcp.Signal, session uint64) bool {
+	if sig.Session == session {
+		return false
+	}
+	output.PrintWarning(fmt.Sprintf("Discarded %s from an earlier agent session", sig.Tool))
+	tc.journal(domain.Event{Type: domain.EventStaleSignal, Tool: sig.Tool, Status: sig.Status, Summary: sig.Summary})
+	return true
+}
+
+// answer asks the human the agent's question and returns the answer as the
+// tool result, recording the exchange in the ticket history. Questions that
+// get no answer, including in time, are declined so the agent can carry on.
+func (tc *taskContext) answer(ctx context.Context, done <-chan struct{}, q mcp.Question) {
+	ticketName := tc.taskInfo.Ticket.Name
+	answer, err := tc.human.ask(ctx, done, statemachine.QuestionTimeout(tc.sprint), ticketName, q.Text)
+	if err != nil {
+		q.Decline(err.Error())
+		return
+	}
+	q.Answer(answer)
+	if err := config.RecordQuestion(tc.cfg.WorkDir, ticketName, tc.taskInfo.Task.Description, q.Text, answer); err != nil {
+		output.PrintWarning("Failed to record question: " + err.Error())
+	}
+	tc.journal(domain.Event{Type: domain.EventQuestion, Summary: q.Text, Answer: answer})
+}
+
+// handleSignal journals a signal and returns the result it ends the session
+// with, if any. Insights are recorded without ending the session, plans only
+// end planning sessions and reviews only end review sessions, which in turn
+// ignore task_complete. Progress reports only go to the transcript through
+// record, keeping heartbeats out of the journal.
+func (tc *taskContext) handleSignal(sig mcp.Signal, kind string, record func(domain.TranscriptEvent)) (TaskResult, bool) {
+	if sig.Tool == mcp.SignalToolReportProgress {
+		percent := sig.Percent
+		record(domain.TranscriptEvent{Time: time.Now().UTC(), Kind: domain.TranscriptProgress, Text: sig.Summary, Percent: &percent})
+		return TaskResult{}, false
+	}
+	tc.journalSignal(sig)
+	switch sig.Tool {
+	case mcp.SignalToolNoteInsight:
+		_ = config.RecordInsight(tc.cfg.WorkDir, tc.taskInfo.Ticket.Name, sig.Summary)
+		output.PrintSignal(sig)
+		return TaskResult{}, false
+	case mcp.SignalToolSubmitPlan:
+		if kind != agent.SessionPlan {
+			return TaskResult{}, false
+		}
+		return PlanResult(sig.Summary), true
+	case mcp.SignalToolSubmitReview:
+		if kind != agent.SessionReview {
+			return TaskResult{}, false
+		}
+		output.PrintSignal(sig)
+		return ReviewResult(sig), true
+	default:
+		if kind == agent.SessionReview {
+			return TaskResult{}, false
+		}
+		// Planning is read-only, so a pass there would mark the task done
+		// with nothing to commit. Only a plan or a failure ends planning.
+		if kind == agent.SessionPlan && sig.Status == mcp.StatusPass {
+			return FailResult("planning session reported a pass instead of submitting a plan"), true
+		}
+		return ResultFromSignal(sig), true
+	}
+}
+
+// journal appends a best-effort event for the task to the journal. Like
+// insights, journal entries made mid-session never fail the task.
+func (tc *taskContext) journal(event domain.Event) {
+	event.Ticket = tc.taskInfo.Ticket.Name
+	event.Task = tc.taskInfo.Task.Description
+	_ = config.AppendEvent(tc.cfg.WorkDir, event)
+}
+
+// journalSignal records an MCP signal, and the insight it carries for
+// note_insight so replay can rebuild the ticket history.
+func (tc *taskContext) journalSignal(sig mcp.Signal) {
+	tc.journal(domain.Event{Type: domain.EventSignal, Tool: sig.Tool, Status: sig.Status, Summary: sig.Summary})
+	if sig.Tool == mcp.SignalToolNoteInsight {
+		tc.journal(domain.Event{Type: domain.EventInsight, Summary: sig.Summary})
+	}
+}
@@ -0,0 +1,140 @@
+package orchestrator
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// watchdog enforces the wall-clock and idle limits of an agent session. It is
+// an io.Writer so agent output can be teed into it; every write counts as
+// activity. Expired is closed once a limit is hit. The limits can be paused
+// while the session waits on a human.
+type watchdog struct {
+	lastActivity atomic.Int64 // UnixNano of the most recent activity
+	expired      chan struct{}
+	stop         chan struct{}
+	pause        chan bool // true pauses the limits, false resumes them
+	stopOnce     sync.Once
+	reason       string // Set before expired is closed
+}
+
+// startWatchdog begins timing a session. Zero limits are not enforced; with
+// no limits at all Expired never closes.
+func startWatchdog(timeout, idle time.Duration) *watchdog {
+	w := &watchdog{
+		expired: make(chan struct{}),
+		stop:    make(chan struct{}),
+		pause:   make(chan bool),
+	}
+	w.Touch()
+	if timeout > 0 || idle > 0 {
+		go w.run(timeout, idle)
+	} else {
+		w.Stop() // Nothing to time, so Pause and Resume return at once
+	}
+	return w
+}
+
+func (w *watchdog) run(timeout, idle time.Duration) {
+	var deadline <-chan time.Time
+	var deadlineTimer *time.Timer
+	deadlineAt := time.Now().Add(timeout)
+	if timeout > 0 {
+		deadlineTimer = time.NewTimer(timeout)
+		defer deadlineTimer.Stop()
+		deadline = deadlineTimer.C
+	}
+
+	var idleTimer *time.Timer
+	var idleCheck <-chan time.Time
+	if idle > 0 {
+		idleTimer = time.NewTimer(idle)
+		defer idleTimer.Stop()
+		idleCheck = idleTimer.C
+	}
+
+	var pausedAt time.Time
+	for {
+		select {
+		case <-w.stop:
+			return
+		case paused := <-w.pause:
+			if paused {
+				pausedAt = time.Now()
+				deadline, idleCheck = nil, nil
+				continue
+			}
+			// Time spent paused does not count against either limit.
+			if deadlineTimer != nil {
+				deadlineAt = deadlineAt.Add(time.Since(pausedAt))
+				deadlineTimer.Reset(time.Until(deadlineAt))
+				deadline = deadlineTimer.C
+			}
+			if idleTimer != nil {
+				w.Touch()
+				idleTimer.Reset(idle)
+				idleCheck = idleTimer.C
+			}
+		case <-deadline:
+			w.fire(fmt.Sprintf("timed out after %s", timeout))
+			return
+		case <-idleCheck:
+			quiet := time.Since(time.Unix(0, w.lastActivity.Load()))
+			if quiet >= idle {
+				w.fire(fmt.Sprintf("timed out after %s without output", idle))
+				return
+			}
+			idleTimer.Reset(idle - quiet)
+		}
+	}
+}
+
+// Pause stops the limits from counting until Resume.
+func (w *watchdog) Pause() {
+	w.setPaused(true)
+}
+
+// Resume continues the limits where Pause left them, restarting the idle wait.
+func (w *watchdog) Resume() {
+	w.setPaused(false)
+}
+
+func (w *watchdog) setPaused(paused bool) {
+	select {
+	case w.pause <- paused:
+	case <-w.stop:
+	case <-w.expired:
+	}
+}
+
+func (w *watchdog) fire(reason string) {
+	w.reason = reason
+	close(w.expired)
+}
+
+// Touch records activity, postponing the idle limit.
+func (w *watchdog) Touch() {
+	w.lastActivity.Store(time.Now().UnixNano())
+}
+
+func (w *watchdog) Write(p []byte) (int, error) {
+	w.Touch()
+	return len(p), nil
+}
+
+// Expired is closed when a limit is hit.
+func (w *watchdog) Expired() <-chan struct{} {
+	return w.expired
+}
+
+// Reason describes the limit that was hit. Only valid once Expired is closed.
+func (w *watchdog) Reason() string {
+	return w.reason
+}
+
+// Stop releases the watchdog's timers. Safe to call more than once.
+func (w *watchdog) Stop() {
+	w.stopOnce.Do(func() { close(w.stop) })
+}
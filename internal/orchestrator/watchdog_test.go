@@ -0,0 +1,108 @@
+package orchestrator
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWatchdog(t *testing.T) {
+	tests := []struct {
+		name       string
+		timeout    time.Duration
+		idle       time.Duration
+		during     func(w *watchdog) // Runs before waiting for expiry
+		wait       time.Duration
+		wantReason string // Empty when the watchdog must not fire
+	}{
+		{
+			name:       "fires on timeout",
+			timeout:    50 * time.Millisecond,
+			wait:       time.Second,
+			wantReason: "timed out after 50ms",
+		},
+		{
+			name:       "fires when idle",
+			idle:       50 * time.Millisecond,
+			wait:       time.Second,
+			wantReason: "timed out after 50ms without output",
+		},
+		{
+			name: "activity resets idle",
+			idle: 100 * time.Millisecond,
+			during: func(w *watchdog) {
+				for range 10 {
+					time.Sleep(25 * time.Millisecond)
+					_, _ = w.Write([]byte("output"))
+				}
+			},
+			wait: 50 * time.Millisecond,
+		},
+		{
+			name:       "timeout ignores activity",
+			timeout:    100 * time.Millisecond,
+			idle:       time.Hour,
+			during:     func(w *watchdog) { w.Touch() },
+			wait:       time.Second,
+			wantReason: "timed out after 100ms",
+		},
+		{
+			name:    "no firing after stop",
+			timeout: 50 * time.Millisecond,
+			idle:    50 * time.Millisecond,
+			during:  func(w *watchdog) { w.Stop() },
+			wait:    150 * time.Millisecond,
+		},
+		{
+			name: "no limits never fire",
+			wait: 100 * time.Millisecond,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := startWatchdog(tt.timeout, tt.idle)
+			defer w.Stop()
+			if tt.during != nil {
+				tt.during(w)
+			}
+
+			select {
+			case <-w.Expired():
+				if tt.wantReason == "" {
+					t.Fatalf("watchdog fired: %s", w.Reason())
+				}
+				if w.Reason() != tt.wantReason {
+					t.Errorf("Reason() = %q, want %q", w.Reason(), tt.wantReason)
+				}
+			case <-time.After(tt.wait):
+				if tt.wantReason != "" {
+					t.Fatalf("watchdog did not fire within %s", tt.wait)
+				}
+			}
+		})
+	}
+}
+
+func TestWatchdog_FiresOnceActivityStops(t *testing.T) {
+	w := startWatchdog(0, 50*time.Millisecond)
+	defer w.Stop()
+
+	start := time.Now()
+	for range 4 {
+		time.Sleep(25 * time.Millisecond)
+		w.Touch()
+	}
+
+	select {
+	case <-w.Expired():
+		if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+			t.Errorf("fired after %s, want no sooner than the last activity plus the idle limit", elapsed)
+		}
+		if !strings.Contains(w.Reason(), "without output") {
+			t.Errorf("Reason() = %q, want the idle reason", w.Reason())
+		}
+	case <-time.After(time.Second):
+		t.Fatal("watchdog did not fire after activity stopped")
+	}
+}
@@ -0,0 +1,148 @@
+package output
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/sqve/kamaji/internal/config"
+	"github.com/sqve/kamaji/internal/domain"
+)
+
+// HistoryReport is the JSON shape of `kamaji history`.
+type HistoryReport struct {
+	Tickets []*domain.TicketHistory `json:"tickets"`
+	Totals  domain.HistorySummary   `json:"totals"`
+}
+
+// NewHistoryReport pairs histories with their totals. Empty entry lists are
+// normalized so JSON consumers always see arrays.
+func NewHistoryReport(histories []*domain.TicketHistory, totals domain.HistorySummary) HistoryReport {
+	tickets := make([]*domain.TicketHistory, 0, len(histories))
+	for _, h := range histories {
+		c := *h
+		if c.Completed == nil {
+			c.Completed = []domain.CompletedTask{}
+		}
+		if c.FailedAttempts == nil {
+			c.FailedAttempts = []domain.FailedAttempt{}
+		}
+		if c.Insights == nil {
+			c.Insights = []string{}
+		}
+		if c.Plans == nil {
+			c.Plans = []domain.TaskPlan{}
+		}
+		if c.Questions == nil {
+			c.Questions = []domain.Question{}
+		}
+		if c.ManualActions == nil {
+			c.ManualActions = []domain.ManualAction{}
+		}
+		tickets = append(tickets, &c)
+	}
+	return HistoryReport{Tickets: tickets, Totals: totals}
+}
+
+// FormatTicketHistory renders one ticket's history, omitting empty sections.
+func FormatTicketHistory(h *domain.TicketHistory) string {
+	var b strings.Builder
+	if config.IsPlain() {
+		b.WriteString(h.Ticket)
+	} else {
+		b.WriteString(boldStyle.Render(h.Ticket))
+	}
+
+	if len(h.Completed) == 0 && len(h.FailedAttempts) == 0 && len(h.Insights) == 0 && len(h.Plans) == 0 &&
+		len(h.Questions) == 0 && len(h.ManualActions) == 0 {
+		b.WriteString("\n  No entries")
+		return b.String()
+	}
+
+	if len(h.Completed) > 0 {
+		b.WriteString("\n  Completed:")
+		for _, c := range h.Completed {
+			writeHistoryEntry(&b, c.Task+": "+c.Summary)
+		}
+	}
+	if len(h.FailedAttempts) > 0 {
+		b.WriteString("\n  Failed attempts:")
+		for _, f := range h.FailedAttempts {
+			writeHistoryEntry(&b, f.Task+": "+f.Summary)
+		}
+	}
+	if len(h.Insights) > 0 {
+		b.WriteString("\n  Insights:")
+		for _, insight := range h.Insights {
+			writeHistoryEntry(&b, insight)
+		}
+	}
+	if len(h.Plans) > 0 {
+		b.WriteString("\n  Plans:")
+		for _, p := range h.Plans {
+			writeHistoryEntry(&b, p.Task+":\n"+p.Plan)
+		}
+	}
+	if len(h.Questions) > 0 {
+		b.WriteString("\n  Questions:")
+		for _, q := range h.Questions {
+			writeHistoryEntry(&b, q.Task+": "+q.Question+"\nAnswer: "+q.Answer)
+		}
+	}
+	if len(h.ManualActions) > 0 {
+		b.WriteString("\n  Manual actions:")
+		for _, a := range h.ManualActions {
+			writeHistoryEntry(&b, FormatManualAction(a))
+		}
+	}
+	return b.String()
+}
+
+// FormatManualAction renders a manual action as "action task: detail",
+// leaving out the parts that are empty.
+func FormatManualAction(a domain.ManualAction) string {
+	text := a.Action
+	if a.Task != "" {
+		text += " " + a.Task
+	}
+	if a.Detail != "" {
+		text += ": " + a.Detail
+	}
+	return text
+}
+
+// writeHistoryEntry writes a list item, indenting continuation lines such as
+// captured verification output.
+func writeHistoryEntry(b *strings.Builder, text string) {
+	b.WriteString("\n    - ")
+	b.WriteString(strings.ReplaceAll(strings.TrimRight(text, "\n"), "\n", "\n      "))
+}
+
+// FormatHistoryTotals renders aggregate history counts. Plans, questions,
+// manual actions and usage are only mentioned when there are any.
+func FormatHistoryTotals(s domain.HistorySummary) string {
+	totals := fmt.Sprintf("Totals: %d tickets, %d completed, %d failed, %d insights",
+		s.TicketCount, s.TotalCompleted, s.TotalFailed, s.TotalInsights)
+	if s.TotalPlans > 0 {
+		totals += fmt.Sprintf(", %d plans", s.TotalPlans)
+	}
+	if s.TotalQuestions > 0 {
+		totals += fmt.Sprintf(", %d questions", s.TotalQuestions)
+	}
+	if s.TotalManual > 0 {
+		totals += fmt.Sprintf(", %d manual actions", s.TotalManual)
+	}
+	if !s.Usage.IsZero() {
+		totals += fmt.Sprintf(", %s tokens, $%.2f", formatCount(s.Usage.Tokens()), s.Usage.CostUSD)
+	}
+	return totals
+}
+
+// PrintHistory outputs each ticket's history followed by the totals.
+func PrintHistory(histories []*domain.TicketHistory, totals domain.HistorySummary) {
+	for _, h := range histories {
+		_, _ = fmt.Fprintln(os.Stdout, FormatTicketHistory(h))
+		_, _ = fmt.Fprintln(os.Stdout)
+	}
+	_, _ = fmt.Fprintln(os.Stdout, FormatHistoryTotals(totals))
+}
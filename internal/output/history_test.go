@@ -0,0 +1,87 @@
+package output
+
+import (
+	"testing"
+
+	"github.com/sqve/kamaji/internal/config"
+	"github.com/sqve/kamaji/internal/domain"
+	"github.com/sqve/kamaji/internal/testutil"
+)
+
+func TestFormatTicketHistory(t *testing.T) {
+	config.SetPlain(true)
+	defer config.ResetPlain()
+
+	history := &domain.TicketHistory{
+		Ticket:         "login-form",
+		Completed:      []domain.CompletedTask{{Task: "Create form", Summary: "Added LoginForm"}},
+		FailedAttempts: []domain.FailedAttempt{{Task: "Add tests", Summary: "verification failed\nFAIL login_test"}},
+		Insights:       []string{"Uses Zustand"},
+		Plans:          []domain.TaskPlan{{Task: "Add tests", Plan: "1. Cover errors\n2. Run suite"}},
+		Questions:      []domain.Question{{Task: "Add tests", Question: "Mock the API?", Answer: "No"}},
+	}
+
+	got := FormatTicketHistory(history)
+
+	testutil.AssertContains(t, got, "login-form\n  Completed:\n    - Create form: Added LoginForm")
+	testutil.AssertContains(t, got, "  Failed attempts:\n    - Add tests: verification failed\n      FAIL login_test")
+	testutil.AssertContains(t, got, "  Insights:\n    - Uses Zustand")
+	testutil.AssertContains(t, got, "  Plans:\n    - Add tests:\n      1. Cover errors\n      2. Run suite")
+	testutil.AssertContains(t, got, "  Questions:\n    - Add tests: Mock the API?\n      Answer: No")
+}
+
+func TestFormatTicketHistory_Empty(t *testing.T) {
+	config.SetPlain(true)
+	defer config.ResetPlain()
+
+	got := FormatTicketHistory(&domain.TicketHistory{Ticket: "empty"})
+
+	testutil.AssertContains(t, got, "No entries")
+	testutil.AssertNotContains(t, got, "Completed:")
+}
+
+func TestFormatHistoryTotals(t *testing.T) {
+	got := FormatHistoryTotals(domain.HistorySummary{TicketCount: 2, TotalCompleted: 3, TotalFailed: 1, TotalInsights: 4})
+
+	want := "Totals: 2 tickets, 3 completed, 1 failed, 4 insights"
+	if got != want {
+		t.Errorf("FormatHistoryTotals() = %q, want %q", got, want)
+	}
+}
+
+func TestFormatHistoryTotals_ManualActions(t *testing.T) {
+	got := FormatHistoryTotals(domain.HistorySummary{TicketCount: 1, TotalManual: 2})
+
+	testutil.AssertContains(t, got, "0 insights, 2 manual actions")
+}
+
+func TestFormatManualAction(t *testing.T) {
+	tests := []struct {
+		action domain.ManualAction
+		want   string
+	}{
+		{domain.ManualAction{Action: "skip", Task: "Add API", Detail: "after 3 failed attempts"}, "skip Add API: after 3 failed attempts"},
+		{domain.ManualAction{Action: "skip", Task: "Add API"}, "skip Add API"},
+		{domain.ManualAction{Action: "reset", Detail: "from task 2"}, "reset: from task 2"},
+	}
+
+	for _, tt := range tests {
+		if got := FormatManualAction(tt.action); got != tt.want {
+			t.Errorf("FormatManualAction(%+v) = %q, want %q", tt.action, got, tt.want)
+		}
+	}
+}
+
+func TestNewHistoryReport_NormalizesEmptyLists(t *testing.T) {
+	original := &domain.TicketHistory{Ticket: "a"}
+
+	report := NewHistoryReport([]*domain.TicketHistory{original}, domain.HistorySummary{TicketCount: 1})
+
+	got := report.Tickets[0]
+	if got.Completed == nil || got.FailedAttempts == nil || got.Insights == nil || got.ManualActions == nil {
+		t.Errorf("expected non-nil entry lists, got %+v", got)
+	}
+	if original.Completed != nil {
+		t.Error("NewHistoryReport should not modify the input history")
+	}
+}
@@ -0,0 +1,93 @@
+package output
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/sqve/kamaji/internal/domain"
+)
+
+// maxEventSummary bounds the summary shown per timeline line.
+const maxEventSummary = 80
+
+// FormatEvent renders a journal event as a single timeline line:
+// "time type ticket: detail", leaving out the parts that are empty.
+func FormatEvent(e domain.Event) string {
+	text := e.Time.Local().Format("2006-01-02 15:04:05") + " " + e.Type
+	if e.Ticket != "" {
+		text += " " + e.Ticket
+	}
+	if detail := eventDetail(e); detail != "" {
+		text += ": " + detail
+	}
+	return text
+}
+
+func eventDetail(e domain.Event) string {
+	var parts []string
+	add := func(s string) {
+		if s != "" {
+			parts = append(parts, s)
+		}
+	}
+
+	switch e.Type {
+	case domain.EventBranchCreated, domain.EventDependencyMerged:
+		add(e.Branch)
+	case domain.EventCommit:
+		add(shortSHA(e.Commit))
+		add(e.Task)
+	case domain.EventSignal, domain.EventStaleSignal:
+		add(strings.TrimSpace(e.Tool + " " + e.Status))
+	case domain.EventSpawn:
+		add(e.Task)
+		if e.Model != "" {
+			add("(" + e.Model + ")")
+		}
+	case domain.EventManualAction:
+		if e.Action != nil {
+			add(FormatManualAction(*e.Action))
+		}
+		return strings.Join(parts, " ")
+	case domain.EventStateSaved:
+		if e.Progress == nil {
+			add("progress cleared")
+		} else {
+			add(fmt.Sprintf("%d done, %d failures", len(e.Progress.Done), e.Progress.FailureCount))
+			if n := len(e.Progress.Skipped); n > 0 {
+				add(fmt.Sprintf("(%d skipped)", n))
+			}
+		}
+		return strings.Join(parts, " ")
+	case domain.EventSprintStart:
+		add(e.Summary)
+		if e.State != nil {
+			add(fmt.Sprintf("(%d tickets with progress)", len(e.State.Tickets)))
+		}
+		return strings.Join(parts, " ")
+	default:
+		add(e.Task)
+		add(e.Status)
+	}
+
+	if summary, _, _ := strings.Cut(e.Summary, "\n"); summary != "" {
+		add("- " + truncate(summary, maxEventSummary))
+	}
+	return strings.Join(parts, " ")
+}
+
+// shortSHA abbreviates a commit hash the way git log --oneline does.
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
+
+// PrintEvents outputs the journal as a timeline, one event per line.
+func PrintEvents(events []domain.Event) {
+	for _, e := range events {
+		_, _ = fmt.Fprintln(os.Stdout, FormatEvent(e))
+	}
+}
@@ -0,0 +1,71 @@
+package output
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sqve/kamaji/internal/domain"
+)
+
+func TestFormatEvent(t *testing.T) {
+	tests := []struct {
+		name  string
+		event domain.Event
+		want  string
+	}{
+		{
+			name:  "branch",
+			event: domain.Event{Type: domain.EventBranchCreated, Ticket: "login", Branch: "feat/login"},
+			want:  "branch_created login: feat/login",
+		},
+		{
+			name:  "commit shortens sha",
+			event: domain.Event{Type: domain.EventCommit, Ticket: "login", Task: "Add form", Commit: "0123456789abcdef", Summary: "Added form"},
+			want:  "commit login: 0123456 Add form - Added form",
+		},
+		{
+			name:  "signal shows first summary line",
+			event: domain.Event{Type: domain.EventSignal, Ticket: "login", Tool: "task_complete", Status: "fail", Summary: "tests failed\nFAIL x"},
+			want:  "signal login: task_complete fail - tests failed",
+		},
+		{
+			name:  "stale signal shows tool",
+			event: domain.Event{Type: domain.EventStaleSignal, Ticket: "login", Tool: "note_insight", Summary: "Uses Zustand"},
+			want:  "stale_signal login: note_insight - Uses Zustand",
+		},
+		{
+			name:  "spawn shows model",
+			event: domain.Event{Type: domain.EventSpawn, Ticket: "login", Task: "Add form", Model: "opus"},
+			want:  "spawn login: Add form (opus)",
+		},
+		{
+			name:  "manual action",
+			event: domain.Event{Type: domain.EventManualAction, Ticket: "login", Action: &domain.ManualAction{Action: "skip", Task: "Add form", Detail: "after 3 failed attempts"}},
+			want:  "manual_action login: skip Add form: after 3 failed attempts",
+		},
+		{
+			name:  "cleared progress",
+			event: domain.Event{Type: domain.EventStateSaved, Ticket: "login", Key: "login"},
+			want:  "state_saved login: progress cleared",
+		},
+		{
+			name:  "sprint complete has no detail",
+			event: domain.Event{Type: domain.EventSprintComplete},
+			want:  "sprint_complete",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.event.Time = time.Date(2026, 1, 2, 3, 4, 5, 0, time.Local)
+
+			got := FormatEvent(tt.event)
+
+			prefix := "2026-01-02 03:04:05 "
+			if !strings.HasPrefix(got, prefix) || got[len(prefix):] != tt.want {
+				t.Errorf("FormatEvent = %q, want %q", got, prefix+tt.want)
+			}
+		})
+	}
+}
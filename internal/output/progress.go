@@ -3,6 +3,7 @@ package output
 import (
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/sqve/kamaji/internal/config"
@@ -61,6 +62,39 @@ func PrintBranchCreated(branch string) {
 	PrintInfo("Created branch: " + branch)
 }
 
+// PrintBranchPlanned outputs the branch a dry run would create.
+func PrintBranchPlanned(branch, base string) {
+	PrintInfo(fmt.Sprintf("Would create branch: %s from %s", branch, base))
+}
+
+// PrintMergePlanned outputs a dependency branch a dry run would merge.
+func PrintMergePlanned(branch string) {
+	PrintInfo("Would merge dependency: " + branch)
+}
+
+// PrintQuestion outputs an agent's ask_human question and prompts for the answer.
+func PrintQuestion(ticket, question string) {
+	PrintWarning(fmt.Sprintf("Question from %s: %s", ticket, question))
+	_, _ = fmt.Fprint(os.Stdout, "Answer: ")
+}
+
+// PrintQuestionExpired ends the answer prompt of a question that got no answer
+// in time.
+func PrintQuestionExpired(timeout time.Duration) {
+	_, _ = fmt.Fprintln(os.Stdout)
+	PrintWarning(fmt.Sprintf("No answer within %s, the agent decides itself", timeout))
+}
+
+// PrintPrompt outputs an agent prompt verbatim.
+func PrintPrompt(text string) {
+	_, _ = fmt.Fprintln(os.Stdout, text)
+}
+
+// PrintDryRunComplete outputs the dry run summary.
+func PrintDryRunComplete(tasks int) {
+	PrintSuccess(fmt.Sprintf("Dry run complete: %d tasks would run, nothing was changed", tasks))
+}
+
 // PrintCommitCreated outputs commit success.
 func PrintCommitCreated(message string) {
 	summary := truncate(message, 50)
@@ -72,6 +106,26 @@ func PrintResetPerformed() {
 	PrintInfo("Reset to HEAD (discarding changes)")
 }
 
+// PrintVerifyStart outputs the verify command about to run.
+func PrintVerifyStart(command string) {
+	PrintInfo("Verifying: " + command)
+}
+
+// PrintVerifyPassed outputs verification success.
+func PrintVerifyPassed() {
+	PrintSuccess("Verification passed")
+}
+
+// PrintVerifyFailed outputs verification failure with its reason.
+func PrintVerifyFailed(reason string) {
+	PrintError("Verification failed: " + reason)
+}
+
+// PrintRetryDelay outputs the wait before the next attempt.
+func PrintRetryDelay(delay time.Duration) {
+	PrintInfo("Retrying in " + delay.String())
+}
+
 func truncate(s string, maxLen int) string {
 	if maxLen <= 0 {
 		return ""
@@ -95,14 +149,13 @@ func SprintStatus(sprint *domain.Sprint, state *domain.State) string {
 	totalTickets := len(sprint.Tickets)
 
 	var currentLine string
-	if state.CurrentTicket < totalTickets {
-		ticket := sprint.Tickets[state.CurrentTicket]
-		taskNum := state.CurrentTask + 1
-		taskTotal := len(ticket.Tasks)
+	if info := statemachine.NextTask(state, sprint); info != nil {
 		currentLine = fmt.Sprintf("Current: Ticket %d (%s) > Task %d/%d",
-			state.CurrentTicket+1, ticket.Name, taskNum, taskTotal)
-	} else {
+			info.TicketIndex+1, info.Ticket.Name, info.TaskIndex+1, len(info.Ticket.Tasks))
+	} else if statemachine.SprintComplete(state, sprint) {
 		currentLine = "Current: Complete"
+	} else {
+		currentLine = "Current: Blocked on incomplete dependencies"
 	}
 
 	return fmt.Sprintf("Sprint: %q\nProgress: %d/%d tickets, %d/%d tasks\n%s",
@@ -114,44 +167,45 @@ func PrintSprintStatus(sprint *domain.Sprint, state *domain.State) {
 	_, _ = fmt.Fprintln(os.Stdout, SprintStatus(sprint, state))
 }
 
-// PrintSprintComplete outputs sprint completion message.
-func PrintSprintComplete(sprint *domain.Sprint, state *domain.State) {
+// PrintSprintComplete outputs sprint completion message, followed by the
+// sprint's token usage and cost when any was reported.
+func PrintSprintComplete(sprint *domain.Sprint, state *domain.State, usage domain.Usage) {
 	if sprint == nil || state == nil {
 		return
 	}
 	_, _, totalTasks := calculateProgress(sprint, state)
 	msg := fmt.Sprintf("Sprint %q complete: %d tickets, %d tasks", sprint.Name, len(sprint.Tickets), totalTasks)
 	PrintSuccess(msg)
+	if !usage.IsZero() {
+		PrintInfo("Usage: " + FormatUsage(usage))
+	}
 }
 
-// PrintSprintStuck outputs stuck state message.
-func PrintSprintStuck(sprint *domain.Sprint, state *domain.State) {
+// PrintSprintStuck outputs stuck state message for the given ticket.
+func PrintSprintStuck(sprint *domain.Sprint, state *domain.State, ticketName string) {
 	if sprint == nil || state == nil {
 		return
 	}
-	if state.CurrentTicket >= len(sprint.Tickets) {
+	ticket := statemachine.FindTicket(sprint, ticketName)
+	if ticket == nil || statemachine.TicketComplete(state, ticket) {
 		PrintError("Sprint stuck after completion")
 		return
 	}
 
-	ticket := sprint.Tickets[state.CurrentTicket]
-	taskDesc := "unknown"
-	if state.CurrentTask < len(ticket.Tasks) {
-		taskDesc = ticket.Tasks[state.CurrentTask].Description
-	}
+	progress := statemachine.Progress(state, ticket)
+	taskDesc := ticket.Tasks[progress.CurrentTask].Description
 
-	msg := fmt.Sprintf("Sprint stuck after %d failures on: %s", state.FailureCount, taskDesc)
+	msg := fmt.Sprintf("Sprint stuck after %d failures on: %s", progress.FailureCount, taskDesc)
 	PrintError(msg)
 }
 
 func calculateProgress(sprint *domain.Sprint, state *domain.State) (ticketsDone, tasksDone, totalTasks int) {
-	for i, ticket := range sprint.Tickets {
+	for i := range sprint.Tickets {
+		ticket := &sprint.Tickets[i]
 		totalTasks += len(ticket.Tasks)
-		if i < state.CurrentTicket {
+		tasksDone += statemachine.Progress(state, ticket).TasksDone
+		if statemachine.TicketComplete(state, ticket) {
 			ticketsDone++
-			tasksDone += len(ticket.Tasks)
-		} else if i == state.CurrentTicket {
-			tasksDone += state.CurrentTask
 		}
 	}
 	return ticketsDone, tasksDone, totalTasks
@@ -3,6 +3,7 @@ package output
 import (
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/sqve/kamaji/internal/config"
 	"github.com/sqve/kamaji/internal/domain"
@@ -221,6 +222,30 @@ func TestGitFeedback(t *testing.T) {
 		})
 		testutil.AssertContains(t, output, "Reset to HEAD")
 	})
+
+	t.Run("PrintRetryDelay", func(t *testing.T) {
+		output := testutil.CaptureStdout(t, func() {
+			PrintRetryDelay(90 * time.Second)
+		})
+		testutil.AssertContains(t, output, "Retrying in 1m30s")
+	})
+}
+
+func TestDryRunFeedback(t *testing.T) {
+	config.SetPlain(true)
+	defer config.ResetPlain()
+
+	output := testutil.CaptureStdout(t, func() {
+		PrintBranchPlanned("feat/api", "main")
+		PrintMergePlanned("feat/schema")
+		PrintPrompt("<task>\n</task>\n")
+		PrintDryRunComplete(2)
+	})
+
+	testutil.AssertContains(t, output, "Would create branch: feat/api from main")
+	testutil.AssertContains(t, output, "Would merge dependency: feat/schema")
+	testutil.AssertContains(t, output, "<task>\n</task>\n")
+	testutil.AssertContains(t, output, "Dry run complete: 2 tasks would run, nothing was changed")
 }
 
 func TestTruncate(t *testing.T) {
@@ -341,7 +366,7 @@ func TestSprintStatus(t *testing.T) {
 	}{
 		{
 			name:  "at start",
-			state: &domain.State{CurrentTicket: 0, CurrentTask: 0},
+			state: &domain.State{},
 			mustHave: []string{
 				`Sprint: "Feature Sprint"`,
 				"Progress: 0/3 tickets, 0/6 tasks",
@@ -350,7 +375,7 @@ func TestSprintStatus(t *testing.T) {
 		},
 		{
 			name:  "middle of first ticket",
-			state: &domain.State{CurrentTicket: 0, CurrentTask: 2},
+			state: progressState(sprint, map[string]int{"auth-flow": 2}),
 			mustHave: []string{
 				"Progress: 0/3 tickets, 2/6 tasks",
 				"Current: Ticket 1 (auth-flow) > Task 3/3",
@@ -358,7 +383,7 @@ func TestSprintStatus(t *testing.T) {
 		},
 		{
 			name:  "second ticket",
-			state: &domain.State{CurrentTicket: 1, CurrentTask: 1},
+			state: progressState(sprint, map[string]int{"auth-flow": 3, "dashboard": 1}),
 			mustHave: []string{
 				"Progress: 1/3 tickets, 4/6 tasks",
 				"Current: Ticket 2 (dashboard) > Task 2/2",
@@ -366,7 +391,7 @@ func TestSprintStatus(t *testing.T) {
 		},
 		{
 			name:  "completed",
-			state: &domain.State{CurrentTicket: 3, CurrentTask: 0},
+			state: progressState(sprint, map[string]int{"auth-flow": 3, "dashboard": 2, "settings": 1}),
 			mustHave: []string{
 				"Progress: 3/3 tickets, 6/6 tasks",
 				"Current: Complete",
@@ -389,9 +414,9 @@ func TestSprintStatus(t *testing.T) {
 func TestCalculateProgress(t *testing.T) {
 	sprint := &domain.Sprint{
 		Tickets: []domain.Ticket{
-			{Tasks: []domain.Task{{}, {}, {}}},
-			{Tasks: []domain.Task{{}, {}}},
-			{Tasks: []domain.Task{{}}},
+			{Name: "a", Tasks: []domain.Task{{Description: "a1"}, {Description: "a2"}, {Description: "a3"}}},
+			{Name: "b", Tasks: []domain.Task{{Description: "b1"}, {Description: "b2"}}},
+			{Name: "c", Tasks: []domain.Task{{Description: "c1"}}},
 		},
 	}
 
@@ -404,28 +429,28 @@ func TestCalculateProgress(t *testing.T) {
 	}{
 		{
 			name:        "at start",
-			state:       &domain.State{CurrentTicket: 0, CurrentTask: 0},
+			state:       &domain.State{},
 			wantTickets: 0,
 			wantTasks:   0,
 			wantTotal:   6,
 		},
 		{
 			name:        "mid first ticket",
-			state:       &domain.State{CurrentTicket: 0, CurrentTask: 2},
+			state:       progressState(sprint, map[string]int{"a": 2}),
 			wantTickets: 0,
 			wantTasks:   2,
 			wantTotal:   6,
 		},
 		{
 			name:        "first ticket done",
-			state:       &domain.State{CurrentTicket: 1, CurrentTask: 0},
+			state:       progressState(sprint, map[string]int{"a": 3}),
 			wantTickets: 1,
 			wantTasks:   3,
 			wantTotal:   6,
 		},
 		{
 			name:        "all done",
-			state:       &domain.State{CurrentTicket: 3, CurrentTask: 0},
+			state:       progressState(sprint, map[string]int{"a": 3, "b": 2, "c": 1}),
 			wantTickets: 3,
 			wantTasks:   6,
 			wantTotal:   6,
@@ -448,26 +473,58 @@ func TestCalculateProgress(t *testing.T) {
 	}
 }
 
+func TestSprintStatus_Blocked(t *testing.T) {
+	sprint := &domain.Sprint{
+		Name: "Blocked Sprint",
+		Tickets: []domain.Ticket{
+			{Name: "api", DependsOn: []string{"schema"}, Tasks: []domain.Task{{Description: "Add endpoint"}}},
+			{Name: "schema", DependsOn: []string{"api"}, Tasks: []domain.Task{{Description: "Add table"}}},
+		},
+	}
+
+	got := SprintStatus(sprint, &domain.State{})
+	testutil.AssertContains(t, got, "Current: Blocked")
+}
+
+// progressState moves each named ticket of sprint to the given task index.
+func progressState(sprint *domain.Sprint, tasks map[string]int) *domain.State {
+	state := &domain.State{}
+	for name, task := range tasks {
+		statemachine.Goto(state, statemachine.FindTicket(sprint, name), task)
+	}
+	return state
+}
+
 func TestPrintSprintComplete(t *testing.T) {
 	sprint := &domain.Sprint{
 		Name: "Test Sprint",
 		Tickets: []domain.Ticket{
-			{Tasks: []domain.Task{{}, {}}},
-			{Tasks: []domain.Task{{}}},
+			{Name: "a", Tasks: []domain.Task{{}, {}}},
+			{Name: "b", Tasks: []domain.Task{{}}},
 		},
 	}
-	state := &domain.State{CurrentTicket: 2, CurrentTask: 0}
+	state := progressState(sprint, map[string]int{"a": 2, "b": 1})
 
 	t.Run("outputs completion message", func(t *testing.T) {
 		config.SetPlain(true)
 		defer config.ResetPlain()
 		output := testutil.CaptureStdout(t, func() {
-			PrintSprintComplete(sprint, state)
+			PrintSprintComplete(sprint, state, domain.Usage{})
 		})
 		testutil.AssertContains(t, output, "Test Sprint")
 		testutil.AssertContains(t, output, "complete")
 		testutil.AssertContains(t, output, "2 tickets")
 		testutil.AssertContains(t, output, "3 tasks")
+		testutil.AssertNotContains(t, output, "Usage")
+	})
+
+	t.Run("outputs usage totals", func(t *testing.T) {
+		config.SetPlain(true)
+		defer config.ResetPlain()
+		output := testutil.CaptureStdout(t, func() {
+			PrintSprintComplete(sprint, state, domain.Usage{InputTokens: 1200, OutputTokens: 300, CostUSD: 0.5})
+		})
+		testutil.AssertContains(t, output, "Usage: 1,500 tokens (in 1,200, out 300, cache write 0, cache read 0), $0.50")
 	})
 }
 
@@ -489,13 +546,11 @@ func TestPrintSprintStuck(t *testing.T) {
 	}
 
 	t.Run("stuck on task", func(t *testing.T) {
-		state := &domain.State{
-			CurrentTicket: 0,
-			CurrentTask:   1,
-			FailureCount:  3,
-		}
+		state := &domain.State{Tickets: map[string]domain.TicketState{
+			"auth": {Done: []string{"Setup auth"}, FailingTask: "Add login", FailureCount: 3},
+		}}
 		output := testutil.CaptureStderr(t, func() {
-			PrintSprintStuck(sprint, state)
+			PrintSprintStuck(sprint, state, "auth")
 		})
 		testutil.AssertContains(t, output, "stuck")
 		testutil.AssertContains(t, output, "3 failures")
@@ -503,13 +558,11 @@ func TestPrintSprintStuck(t *testing.T) {
 	})
 
 	t.Run("stuck after completion", func(t *testing.T) {
-		state := &domain.State{
-			CurrentTicket: 1,
-			CurrentTask:   0,
-			FailureCount:  3,
-		}
+		state := &domain.State{Tickets: map[string]domain.TicketState{
+			"auth": {Done: []string{"Setup auth", "Add login"}, FailingTask: "Add login", FailureCount: 3},
+		}}
 		output := testutil.CaptureStderr(t, func() {
-			PrintSprintStuck(sprint, state)
+			PrintSprintStuck(sprint, state, "auth")
 		})
 		testutil.AssertContains(t, output, "stuck after completion")
 	})
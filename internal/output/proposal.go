@@ -0,0 +1,54 @@
+package output
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/sqve/kamaji/internal/config"
+	"github.com/sqve/kamaji/internal/domain"
+)
+
+// FormatProposal renders a proposed task with its reason, steps and verify
+// criteria. The status is shown once the proposal is no longer pending.
+func FormatProposal(p domain.Proposal) string {
+	title := fmt.Sprintf("#%d %s: %s", p.ID, p.Ticket, p.Description)
+	if p.Status != domain.ProposalPending {
+		title += " (" + p.Status + ")"
+	}
+
+	var b strings.Builder
+	if config.IsPlain() {
+		b.WriteString(title)
+	} else {
+		b.WriteString(boldStyle.Render(title))
+	}
+	b.WriteString("\n  Reason: " + p.Reason)
+	if p.Task != "" {
+		b.WriteString("\n  Proposed during: " + p.Task)
+	}
+	if len(p.Steps) > 0 {
+		b.WriteString("\n  Steps:")
+		for _, step := range p.Steps {
+			writeHistoryEntry(&b, step)
+		}
+	}
+	if p.Verify != "" {
+		b.WriteString("\n  Verify: " + p.Verify)
+	}
+	return b.String()
+}
+
+// PrintProposals outputs proposals separated by blank lines.
+func PrintProposals(proposals []domain.Proposal) {
+	if len(proposals) == 0 {
+		PrintInfo("No proposals")
+		return
+	}
+	for i, p := range proposals {
+		if i > 0 {
+			_, _ = fmt.Fprintln(os.Stdout)
+		}
+		_, _ = fmt.Fprintln(os.Stdout, FormatProposal(p))
+	}
+}
@@ -0,0 +1,35 @@
+package output
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sqve/kamaji/internal/config"
+	"github.com/sqve/kamaji/internal/domain"
+	"github.com/sqve/kamaji/internal/testutil"
+)
+
+func TestFormatProposal(t *testing.T) {
+	config.SetPlain(true)
+	defer config.ResetPlain()
+
+	p := domain.Proposal{
+		ID:          2,
+		Ticket:      "login",
+		Description: "Add rate limiting",
+		Steps:       []string{"Limit by IP", "Return 429"},
+		Verify:      "Sixth attempt gets 429",
+		Reason:      "Login has no throttling",
+		Task:        "Add form",
+		Status:      domain.ProposalPending,
+	}
+
+	got := FormatProposal(p)
+	testutil.AssertContains(t, got, "#2 login: Add rate limiting\n  Reason: Login has no throttling\n  Proposed during: Add form")
+	testutil.AssertContains(t, got, "  Steps:\n    - Limit by IP\n    - Return 429\n  Verify: Sixth attempt gets 429")
+
+	p.Status = domain.ProposalRejected
+	if first, _, _ := strings.Cut(FormatProposal(p), "\n"); first != "#2 login: Add rate limiting (rejected)" {
+		t.Errorf("title = %q, want the status", first)
+	}
+}
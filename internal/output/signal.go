@@ -14,6 +14,8 @@ func FormatSignal(sig mcp.Signal) string {
 		return formatTaskComplete(sig)
 	case mcp.SignalToolNoteInsight:
 		return formatNoteInsight(sig)
+	case mcp.SignalToolSubmitReview:
+		return formatSubmitReview(sig)
 	default:
 		return Style(Info, sig.Summary)
 	}
@@ -38,3 +40,10 @@ func formatTaskComplete(sig mcp.Signal) string {
 func formatNoteInsight(sig mcp.Signal) string {
 	return Style(Info, "Insight: "+sig.Summary)
 }
+
+func formatSubmitReview(sig mcp.Signal) string {
+	if sig.Status == mcp.VerdictApprove {
+		return Style(Success, "Review approved: "+sig.Summary)
+	}
+	return Style(Error, "Review rejected: "+sig.Summary)
+}
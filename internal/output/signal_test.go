@@ -44,6 +44,24 @@ func TestFormatSignal_PlainMode(t *testing.T) {
 			},
 			expected: "-> Insight: Found a bug in existing code",
 		},
+		{
+			name: "submit_review approve",
+			signal: mcp.Signal{
+				Tool:    mcp.SignalToolSubmitReview,
+				Status:  mcp.VerdictApprove,
+				Summary: "Covers the task",
+			},
+			expected: "[ok] Review approved: Covers the task",
+		},
+		{
+			name: "submit_review reject",
+			signal: mcp.Signal{
+				Tool:    mcp.SignalToolSubmitReview,
+				Status:  mcp.VerdictReject,
+				Summary: "No tests",
+			},
+			expected: "Error: Review rejected: No tests",
+		},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,162 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/sqve/kamaji/internal/domain"
+	"github.com/sqve/kamaji/internal/statemachine"
+)
+
+// Sprint states reported by StatusReport.
+const (
+	StatusInProgress = "in_progress"
+	StatusComplete   = "complete"
+	StatusBlocked    = "blocked"
+	StatusStuck      = "stuck"
+)
+
+// StatusReport summarizes sprint progress for `kamaji status`.
+type StatusReport struct {
+	Sprint       string         `json:"sprint"`
+	Status       string         `json:"status"`
+	TicketsDone  int            `json:"tickets_done"`
+	TicketsTotal int            `json:"tickets_total"`
+	TasksDone    int            `json:"tasks_done"`
+	TasksTotal   int            `json:"tasks_total"`
+	Skipped      []SkippedTask  `json:"skipped,omitempty"` // Done tasks that were skipped rather than passed
+	Current      *CurrentStatus `json:"current,omitempty"` // Nil when complete or blocked
+}
+
+// SkippedTask names a task that was skipped with `kamaji skip`.
+type SkippedTask struct {
+	Ticket string `json:"ticket"`
+	Task   string `json:"task"`
+}
+
+// CurrentStatus describes the task the sprint would run next.
+type CurrentStatus struct {
+	Ticket       string `json:"ticket"`
+	Branch       string `json:"branch"`
+	TicketNumber int    `json:"ticket_number"` // 1-based
+	Task         string `json:"task"`
+	TaskNumber   int    `json:"task_number"` // 1-based
+	TaskTotal    int    `json:"task_total"`
+	FailureCount int    `json:"failure_count"` // Consecutive failures on this task
+	MaxAttempts  int    `json:"max_attempts"`
+	LastFailure  string `json:"last_failure,omitempty"`
+}
+
+// NewStatusReport builds a report from the sprint and state. history is the
+// current ticket's history and may be nil; it supplies the last failure
+// summary while the current task has consecutive failures.
+func NewStatusReport(sprint *domain.Sprint, state *domain.State, history *domain.TicketHistory) StatusReport {
+	ticketsDone, tasksDone, totalTasks := calculateProgress(sprint, state)
+	report := StatusReport{
+		Sprint:       sprint.Name,
+		TicketsDone:  ticketsDone,
+		TicketsTotal: len(sprint.Tickets),
+		TasksDone:    tasksDone,
+		TasksTotal:   totalTasks,
+	}
+	for i := range sprint.Tickets {
+		ticket := &sprint.Tickets[i]
+		for _, task := range statemachine.SkippedTasks(state, ticket) {
+			report.Skipped = append(report.Skipped, SkippedTask{Ticket: ticket.Name, Task: task.Description})
+		}
+	}
+
+	info := statemachine.NextTask(state, sprint)
+	switch {
+	case info != nil:
+		report.Status = StatusInProgress
+	case statemachine.SprintComplete(state, sprint):
+		report.Status = StatusComplete
+		return report
+	default:
+		report.Status = StatusBlocked
+		return report
+	}
+
+	progress := statemachine.Progress(state, info.Ticket)
+	current := &CurrentStatus{
+		Ticket:       info.Ticket.Name,
+		Branch:       info.Ticket.Branch,
+		TicketNumber: info.TicketIndex + 1,
+		Task:         info.Task.Description,
+		TaskNumber:   info.TaskIndex + 1,
+		TaskTotal:    len(info.Ticket.Tasks),
+		FailureCount: progress.FailureCount,
+		MaxAttempts:  statemachine.MaxAttempts(sprint, info),
+	}
+	if current.FailureCount > 0 && history != nil {
+		current.LastFailure = lastFailure(history, info.Task.Description)
+	}
+	if statemachine.IsStuck(state, sprint, info.Ticket) {
+		report.Status = StatusStuck
+	}
+	report.Current = current
+
+	return report
+}
+
+func lastFailure(history *domain.TicketHistory, task string) string {
+	for i := len(history.FailedAttempts) - 1; i >= 0; i-- {
+		if history.FailedAttempts[i].Task == task {
+			return history.FailedAttempts[i].Summary
+		}
+	}
+	return ""
+}
+
+// FormatStatusReport renders a report as human-readable lines.
+func FormatStatusReport(r StatusReport) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Sprint: %q\n", r.Sprint)
+	fmt.Fprintf(&b, "Progress: %d/%d tickets, %d/%d tasks", r.TicketsDone, r.TicketsTotal, r.TasksDone, r.TasksTotal)
+	if len(r.Skipped) > 0 {
+		fmt.Fprintf(&b, " (%d skipped)", len(r.Skipped))
+	}
+	b.WriteString("\n")
+	for _, s := range r.Skipped {
+		fmt.Fprintf(&b, "Skipped: %s > %s\n", s.Ticket, s.Task)
+	}
+
+	switch r.Status {
+	case StatusComplete:
+		b.WriteString("Current: Complete")
+		return b.String()
+	case StatusBlocked:
+		b.WriteString("Current: Blocked on incomplete dependencies")
+		return b.String()
+	}
+
+	c := r.Current
+	fmt.Fprintf(&b, "Current: Ticket %d (%s) > Task %d/%d\n", c.TicketNumber, c.Ticket, c.TaskNumber, c.TaskTotal)
+	fmt.Fprintf(&b, "Task: %s\n", c.Task)
+	fmt.Fprintf(&b, "Failures: %d/%d", c.FailureCount, c.MaxAttempts)
+	if r.Status == StatusStuck {
+		b.WriteString(" (stuck)")
+	}
+	if c.LastFailure != "" {
+		fmt.Fprintf(&b, "\nLast failure: %s", c.LastFailure)
+	}
+	return b.String()
+}
+
+// PrintStatusReport outputs a report as text.
+func PrintStatusReport(r StatusReport) {
+	_, _ = fmt.Fprintln(os.Stdout, FormatStatusReport(r))
+}
+
+// PrintJSON writes v to stdout as indented JSON.
+func PrintJSON(v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding JSON: %w", err)
+	}
+	_, err = fmt.Fprintln(os.Stdout, string(data))
+	return err
+}
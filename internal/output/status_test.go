@@ -0,0 +1,152 @@
+package output
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/sqve/kamaji/internal/domain"
+	"github.com/sqve/kamaji/internal/testutil"
+)
+
+func statusSprint() *domain.Sprint {
+	return &domain.Sprint{
+		Name: "Feature Sprint",
+		Tickets: []domain.Ticket{
+			{Name: "auth", Branch: "feat/auth", Tasks: []domain.Task{
+				{Description: "Setup auth"},
+				{Description: "Add login"},
+			}},
+			{Name: "dashboard", Branch: "feat/dashboard", MaxAttempts: 5, Tasks: []domain.Task{
+				{Description: "Create layout"},
+			}},
+		},
+	}
+}
+
+func TestNewStatusReport_InProgressWithFailures(t *testing.T) {
+	state := &domain.State{Tickets: map[string]domain.TicketState{
+		"auth": {Done: []string{"Setup auth"}, FailingTask: "Add login", FailureCount: 2},
+	}}
+	history := &domain.TicketHistory{
+		Ticket: "auth",
+		FailedAttempts: []domain.FailedAttempt{
+			{Task: "Add login", Summary: "first failure"},
+			{Task: "Setup auth", Summary: "older task"},
+			{Task: "Add login", Summary: "tests still failing"},
+		},
+	}
+
+	report := NewStatusReport(statusSprint(), state, history)
+
+	if report.Status != StatusInProgress {
+		t.Errorf("Status = %q, want %q", report.Status, StatusInProgress)
+	}
+	if report.TasksDone != 1 || report.TasksTotal != 3 {
+		t.Errorf("Tasks = %d/%d, want 1/3", report.TasksDone, report.TasksTotal)
+	}
+	if report.Current == nil {
+		t.Fatal("Current = nil, want current task")
+	}
+	want := CurrentStatus{
+		Ticket:       "auth",
+		Branch:       "feat/auth",
+		TicketNumber: 1,
+		Task:         "Add login",
+		TaskNumber:   2,
+		TaskTotal:    2,
+		FailureCount: 2,
+		MaxAttempts:  3,
+		LastFailure:  "tests still failing",
+	}
+	if *report.Current != want {
+		t.Errorf("Current = %+v, want %+v", *report.Current, want)
+	}
+
+	text := FormatStatusReport(report)
+	testutil.AssertContains(t, text, "Current: Ticket 1 (auth) > Task 2/2")
+	testutil.AssertContains(t, text, "Task: Add login")
+	testutil.AssertContains(t, text, "Failures: 2/3")
+	testutil.AssertContains(t, text, "Last failure: tests still failing")
+	testutil.AssertNotContains(t, text, "(stuck)")
+}
+
+func TestNewStatusReport_Stuck(t *testing.T) {
+	state := &domain.State{Tickets: map[string]domain.TicketState{
+		"auth":      {Done: []string{"Setup auth", "Add login"}},
+		"dashboard": {FailingTask: "Create layout", FailureCount: 5},
+	}}
+
+	report := NewStatusReport(statusSprint(), state, nil)
+
+	if report.Status != StatusStuck {
+		t.Errorf("Status = %q, want %q", report.Status, StatusStuck)
+	}
+	if report.Current.MaxAttempts != 5 {
+		t.Errorf("MaxAttempts = %d, want 5", report.Current.MaxAttempts)
+	}
+	testutil.AssertContains(t, FormatStatusReport(report), "Failures: 5/5 (stuck)")
+}
+
+func TestNewStatusReport_Complete(t *testing.T) {
+	state := progressState(statusSprint(), map[string]int{"auth": 2, "dashboard": 1})
+
+	report := NewStatusReport(statusSprint(), state, nil)
+
+	if report.Status != StatusComplete {
+		t.Errorf("Status = %q, want %q", report.Status, StatusComplete)
+	}
+	if report.Current != nil {
+		t.Errorf("Current = %+v, want nil", report.Current)
+	}
+	testutil.AssertContains(t, FormatStatusReport(report), "Current: Complete")
+}
+
+func TestNewStatusReport_SkippedTasks(t *testing.T) {
+	state := &domain.State{Tickets: map[string]domain.TicketState{
+		"auth": {Done: []string{"Setup auth", "Add login"}, Skipped: []string{"Add login"}},
+	}}
+
+	report := NewStatusReport(statusSprint(), state, nil)
+
+	want := []SkippedTask{{Ticket: "auth", Task: "Add login"}}
+	if !reflect.DeepEqual(report.Skipped, want) {
+		t.Errorf("Skipped = %+v, want %+v", report.Skipped, want)
+	}
+	if report.TasksDone != 2 {
+		t.Errorf("TasksDone = %d, want 2", report.TasksDone)
+	}
+	text := FormatStatusReport(report)
+	testutil.AssertContains(t, text, "Progress: 1/2 tickets, 2/3 tasks (1 skipped)")
+	testutil.AssertContains(t, text, "Skipped: auth > Add login")
+}
+
+func TestPrintJSON_StatusReport(t *testing.T) {
+	report := NewStatusReport(statusSprint(), &domain.State{}, nil)
+
+	var err error
+	out := testutil.CaptureStdout(t, func() {
+		err = PrintJSON(report)
+	})
+	if err != nil {
+		t.Fatalf("PrintJSON() error = %v", err)
+	}
+
+	var decoded map[string]any
+	if err := json.Unmarshal([]byte(out), &decoded); err != nil {
+		t.Fatalf("invalid JSON %q: %v", out, err)
+	}
+	if decoded["status"] != StatusInProgress {
+		t.Errorf("status = %v, want %q", decoded["status"], StatusInProgress)
+	}
+	current, ok := decoded["current"].(map[string]any)
+	if !ok {
+		t.Fatalf("current = %v, want object", decoded["current"])
+	}
+	if current["task"] != "Setup auth" {
+		t.Errorf("current.task = %v, want %q", current["task"], "Setup auth")
+	}
+	if _, ok := current["last_failure"]; ok {
+		t.Error("last_failure should be omitted when empty")
+	}
+}
@@ -0,0 +1,93 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/sqve/kamaji/internal/domain"
+)
+
+// maxToolDetail bounds the tool input or error shown on one line.
+const maxToolDetail = 100
+
+// toolInputKeys are the tool input fields that best describe a call, in order
+// of preference.
+var toolInputKeys = []string{"command", "file_path", "path", "pattern", "url", "query", "description"}
+
+// FormatTranscriptEvent renders an agent transcript event for the terminal.
+// Returns false for events not worth showing, such as successful tool output.
+func FormatTranscriptEvent(e domain.TranscriptEvent) (string, bool) {
+	switch e.Kind {
+	case domain.TranscriptOutput:
+		return Style(Info, e.Text), true
+	case domain.TranscriptText:
+		text := strings.TrimSpace(e.Text)
+		if text == "" {
+			return "", false
+		}
+		return text, true
+	case domain.TranscriptToolUse:
+		if detail := toolDetail(e.Input); detail != "" {
+			return Style(Info, e.Tool+": "+detail), true
+		}
+		return Style(Info, e.Tool), true
+	case domain.TranscriptToolResult:
+		if !e.IsError {
+			return "", false
+		}
+		first, _, _ := strings.Cut(strings.TrimSpace(e.Text), "\n")
+		return Style(Warning, "Tool error: "+truncate(first, maxToolDetail)), true
+	case domain.TranscriptResult:
+		summary := fmt.Sprintf("Session finished: %d turns in %s", e.NumTurns, (time.Duration(e.DurationMS) * time.Millisecond).Round(time.Second))
+		if e.IsError {
+			return Style(Warning, summary+" with an error"), true
+		}
+		return Style(Info, summary), true
+	case domain.TranscriptProgress:
+		if e.Percent != nil {
+			return Style(Info, fmt.Sprintf("Progress %d%%: %s", *e.Percent, e.Text)), true
+		}
+		return Style(Info, "Progress: "+e.Text), true
+	case domain.TranscriptSystem:
+		if e.Text != "init" {
+			return "", false
+		}
+		if e.Model != "" {
+			return Style(Info, "Session started: "+e.Model), true
+		}
+		return Style(Info, "Session started"), true
+	default:
+		return "", false
+	}
+}
+
+// toolDetail picks the most descriptive field of a tool input, falling back
+// to the compact JSON.
+func toolDetail(input json.RawMessage) string {
+	if len(input) == 0 {
+		return ""
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(input, &fields); err == nil {
+		for _, key := range toolInputKeys {
+			if v, ok := fields[key].(string); ok && v != "" {
+				first, _, _ := strings.Cut(v, "\n")
+				return truncate(first, maxToolDetail)
+			}
+		}
+		if len(fields) == 0 {
+			return ""
+		}
+	}
+	return truncate(string(input), maxToolDetail)
+}
+
+// PrintTranscriptEvent outputs a transcript event if it is worth showing.
+func PrintTranscriptEvent(e domain.TranscriptEvent) {
+	if text, ok := FormatTranscriptEvent(e); ok {
+		_, _ = fmt.Fprintln(os.Stdout, text)
+	}
+}
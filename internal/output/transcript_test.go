@@ -0,0 +1,87 @@
+package output
+
+import (
+	"testing"
+
+	"github.com/sqve/kamaji/internal/config"
+	"github.com/sqve/kamaji/internal/domain"
+)
+
+func TestFormatTranscriptEvent(t *testing.T) {
+	config.SetPlain(true)
+	defer config.ResetPlain()
+
+	tests := []struct {
+		name   string
+		event  domain.TranscriptEvent
+		want   string
+		hidden bool
+	}{
+		{
+			name:  "text",
+			event: domain.TranscriptEvent{Kind: domain.TranscriptText, Text: "Reading the code\n"},
+			want:  "Reading the code",
+		},
+		{
+			name:  "tool use shows command",
+			event: domain.TranscriptEvent{Kind: domain.TranscriptToolUse, Tool: "Bash", Input: []byte(`{"command":"go test ./...","timeout":60}`)},
+			want:  "-> Bash: go test ./...",
+		},
+		{
+			name:  "tool use falls back to json",
+			event: domain.TranscriptEvent{Kind: domain.TranscriptToolUse, Tool: "TodoWrite", Input: []byte(`{"todos":[]}`)},
+			want:  `-> TodoWrite: {"todos":[]}`,
+		},
+		{
+			name:  "progress",
+			event: domain.TranscriptEvent{Kind: domain.TranscriptProgress, Text: "Wrote the handler", Percent: intPtr(40)},
+			want:  "-> Progress 40%: Wrote the handler",
+		},
+		{
+			name:  "tool use without input",
+			event: domain.TranscriptEvent{Kind: domain.TranscriptToolUse, Tool: "LS", Input: []byte(`{}`)},
+			want:  "-> LS",
+		},
+		{
+			name:   "successful tool result is hidden",
+			event:  domain.TranscriptEvent{Kind: domain.TranscriptToolResult, Text: "ok"},
+			hidden: true,
+		},
+		{
+			name:  "failed tool result",
+			event: domain.TranscriptEvent{Kind: domain.TranscriptToolResult, Text: "exit status 1\nmore", IsError: true},
+			want:  "Warning: Tool error: exit status 1",
+		},
+		{
+			name:  "result",
+			event: domain.TranscriptEvent{Kind: domain.TranscriptResult, NumTurns: 4, DurationMS: 61200},
+			want:  "-> Session finished: 4 turns in 1m1s",
+		},
+		{
+			name:  "init",
+			event: domain.TranscriptEvent{Kind: domain.TranscriptSystem, Text: "init", Model: "opus"},
+			want:  "-> Session started: opus",
+		},
+		{
+			name:  "raw output",
+			event: domain.TranscriptEvent{Kind: domain.TranscriptOutput, Text: "plain"},
+			want:  "-> plain",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := FormatTranscriptEvent(tt.event)
+			if ok == tt.hidden {
+				t.Fatalf("FormatTranscriptEvent shown = %v, want %v", ok, !tt.hidden)
+			}
+			if got != tt.want {
+				t.Errorf("FormatTranscriptEvent = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func intPtr(v int) *int {
+	return &v
+}
@@ -0,0 +1,74 @@
+package output
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/sqve/kamaji/internal/config"
+	"github.com/sqve/kamaji/internal/domain"
+)
+
+// FormatUsage renders token usage and cost, such as
+// "1,500 tokens (in 1,000, out 200, cache write 100, cache read 200), $0.42".
+func FormatUsage(u domain.Usage) string {
+	return fmt.Sprintf("%s tokens (in %s, out %s, cache write %s, cache read %s), $%.2f",
+		formatCount(u.Tokens()), formatCount(u.InputTokens), formatCount(u.OutputTokens),
+		formatCount(u.CacheCreationTokens), formatCount(u.CacheReadTokens), u.CostUSD)
+}
+
+// formatCount renders n with thousands separators.
+func formatCount(n int64) string {
+	s := strconv.FormatInt(n, 10)
+	neg := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(s, "-")
+
+	var b strings.Builder
+	if neg {
+		b.WriteByte('-')
+	}
+	for i, r := range s {
+		if i > 0 && (len(s)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// FormatSprintStats renders the sprint's usage followed by each ticket's usage
+// and share of the cost, broken down per task.
+func FormatSprintStats(stats domain.SprintStats) string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("Sprint: %s, %s", attempts(stats.Attempts), FormatUsage(stats.Usage)))
+
+	for _, t := range stats.Tickets {
+		b.WriteString("\n\n")
+		name := t.Ticket
+		if !config.IsPlain() {
+			name = boldStyle.Render(name)
+		}
+		b.WriteString(fmt.Sprintf("%s: %s, %s", name, attempts(t.Attempts), FormatUsage(t.Usage)))
+		if stats.Usage.CostUSD > 0 {
+			b.WriteString(fmt.Sprintf(" (%.0f%% of cost)", 100*t.Usage.CostUSD/stats.Usage.CostUSD))
+		}
+		for _, task := range t.Tasks {
+			b.WriteString(fmt.Sprintf("\n  %s: %s, %s tokens, $%.2f",
+				task.Task, attempts(task.Attempts), formatCount(task.Usage.Tokens()), task.Usage.CostUSD))
+		}
+	}
+	return b.String()
+}
+
+func attempts(n int) string {
+	if n == 1 {
+		return "1 attempt"
+	}
+	return fmt.Sprintf("%d attempts", n)
+}
+
+// PrintSprintStats outputs the sprint's usage per ticket and task.
+func PrintSprintStats(stats domain.SprintStats) {
+	_, _ = fmt.Fprintln(os.Stdout, FormatSprintStats(stats))
+}
@@ -0,0 +1,71 @@
+package output
+
+import (
+	"testing"
+
+	"github.com/sqve/kamaji/internal/config"
+	"github.com/sqve/kamaji/internal/domain"
+	"github.com/sqve/kamaji/internal/testutil"
+)
+
+func TestFormatCount(t *testing.T) {
+	tests := []struct {
+		n    int64
+		want string
+	}{
+		{0, "0"},
+		{999, "999"},
+		{1000, "1,000"},
+		{1234567, "1,234,567"},
+		{-4500, "-4,500"},
+	}
+
+	for _, tt := range tests {
+		if got := formatCount(tt.n); got != tt.want {
+			t.Errorf("formatCount(%d) = %q, want %q", tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestFormatUsage(t *testing.T) {
+	got := FormatUsage(domain.Usage{InputTokens: 1000, OutputTokens: 200, CacheCreationTokens: 100, CacheReadTokens: 200, CostUSD: 0.421})
+
+	want := "1,500 tokens (in 1,000, out 200, cache write 100, cache read 200), $0.42"
+	if got != want {
+		t.Errorf("FormatUsage = %q, want %q", got, want)
+	}
+}
+
+func TestFormatSprintStats(t *testing.T) {
+	config.SetPlain(true)
+	defer config.ResetPlain()
+
+	stats := domain.SprintStats{
+		Attempts: 3,
+		Usage:    domain.Usage{InputTokens: 3000, CostUSD: 2},
+		Tickets: []domain.TicketStats{
+			{
+				Ticket:   "schema",
+				Attempts: 3,
+				Usage:    domain.Usage{InputTokens: 3000, CostUSD: 1.5},
+				Tasks:    []domain.TaskStats{{Task: "Create tables", Attempts: 3, Usage: domain.Usage{InputTokens: 3000, CostUSD: 1.5}}},
+			},
+		},
+	}
+
+	got := FormatSprintStats(stats)
+
+	testutil.AssertContains(t, got, "Sprint: 3 attempts, 3,000 tokens")
+	testutil.AssertContains(t, got, "schema: 3 attempts, 3,000 tokens (in 3,000, out 0, cache write 0, cache read 0), $1.50 (75% of cost)")
+	testutil.AssertContains(t, got, "\n  Create tables: 3 attempts, 3,000 tokens, $1.50")
+}
+
+func TestFormatSprintStats_NoCost(t *testing.T) {
+	config.SetPlain(true)
+	defer config.ResetPlain()
+
+	got := FormatSprintStats(domain.SprintStats{Tickets: []domain.TicketStats{{Ticket: "a", Attempts: 1}}})
+
+	testutil.AssertContains(t, got, "a: 1 attempt, 0 tokens")
+	testutil.AssertNotContains(t, got, "of cost")
+}
@@ -5,8 +5,13 @@ import (
 	"io"
 	"os"
 	"os/exec"
+	"time"
 )
 
+// waitDelay bounds how long Wait blocks on output pipes after the process
+// exits, since descendants that outlive a killed agent may hold them open.
+const waitDelay = 5 * time.Second
+
 // Process manages a subprocess.
 type Process struct {
 	cmd     *exec.Cmd
@@ -34,6 +39,15 @@ func WithStderr(w io.Writer) Option {
 	}
 }
 
+// WithStdin feeds the given reader to the process's stdin.
+func WithStdin(r io.Reader) Option {
+	return func(p *Process) {
+		if p.cmd != nil {
+			p.cmd.Stdin = r
+		}
+	}
+}
+
 // WithDir sets the working directory.
 func WithDir(dir string) Option {
 	return func(p *Process) {
@@ -57,6 +71,8 @@ func NewProcess(name string, args ...string) *Process {
 	cmd := exec.Command(name, args...)
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
+	cmd.WaitDelay = waitDelay
+	setProcessGroup(cmd)
 	return &Process{cmd: cmd}
 }
 
@@ -85,10 +101,20 @@ func (p *Process) Wait() error {
 	return p.cmd.Wait()
 }
 
-// Kill sends SIGKILL to the process.
+// Kill stops the process and, where the platform allows, everything in its
+// process group, so a stalled tool call does not outlive it.
 func (p *Process) Kill() error {
 	if p.cmd.Process == nil {
 		return nil
 	}
-	return p.cmd.Process.Kill()
+	return killProcessGroup(p.cmd)
+}
+
+// KillGroupOnCancel makes cmd, created with exec.CommandContext, stop its
+// whole process group when the context ends, like Kill, and bounds how long
+// it then waits on output pipes.
+func KillGroupOnCancel(cmd *exec.Cmd) {
+	setProcessGroup(cmd)
+	cmd.Cancel = func() error { return killProcessGroup(cmd) }
+	cmd.WaitDelay = waitDelay
 }
@@ -103,6 +103,21 @@ func TestProcess_WithStdout(t *testing.T) {
 	}
 }
 
+func TestProcess_WithStdin(t *testing.T) {
+	var buf bytes.Buffer
+	p := NewProcess("cat").Apply(WithStdin(strings.NewReader("from stdin")), WithStdout(&buf))
+	if err := p.Start(); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	if err := p.Wait(); err != nil {
+		t.Fatalf("Wait() error = %v", err)
+	}
+
+	if output := buf.String(); output != "from stdin" {
+		t.Errorf("stdout = %q, want %q", output, "from stdin")
+	}
+}
+
 func TestProcess_WithStderr(t *testing.T) {
 	var buf bytes.Buffer
 	p := NewProcess("sh", "-c", "echo error >&2").Apply(WithStderr(&buf))
@@ -0,0 +1,48 @@
+//go:build !windows
+
+package process
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestProcess_KillReachesDescendants(t *testing.T) {
+	pidFile := filepath.Join(t.TempDir(), "pid")
+	p := NewProcess("sh", "-c", "sleep 30 & echo $! > "+pidFile+"; wait")
+	if err := p.Start(); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	var pid int
+	deadline := time.Now().Add(5 * time.Second)
+	for pid == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+		data, _ := os.ReadFile(pidFile) //nolint:gosec // test file path from t.TempDir
+		pid, _ = strconv.Atoi(strings.TrimSpace(string(data)))
+	}
+	if pid == 0 {
+		t.Fatal("child did not report its pid")
+	}
+
+	if err := p.Kill(); err != nil {
+		t.Errorf("Kill() error = %v", err)
+	}
+	_ = p.Wait()
+
+	// The orphaned sleep is reaped by init once killed.
+	for time.Now().Before(deadline) {
+		if err := syscall.Kill(pid, 0); errors.Is(err, syscall.ESRCH) {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	_ = syscall.Kill(pid, syscall.SIGKILL)
+	t.Error("Kill() left the child's own child running")
+}
@@ -0,0 +1,14 @@
+//go:build windows
+
+package process
+
+import "os/exec"
+
+// setProcessGroup is a no-op on Windows, which has no process groups to kill.
+func setProcessGroup(*exec.Cmd) {}
+
+// killProcessGroup kills only the process itself; its descendants are left
+// to exit once their pipes close.
+func killProcessGroup(cmd *exec.Cmd) error {
+	return cmd.Process.Kill()
+}
@@ -24,10 +24,50 @@ func AssembleContext(sprint *domain.Sprint, state *domain.State, kamajiDir strin
 		return "", nil
 	}
 
+	return AssembleTaskContext(sprint, taskInfo, kamajiDir, kamajiDir, "")
+}
+
+// AssembleTaskContext generates context for a specific task rather than the
+// state's next task, as needed when tickets run concurrently. History is read
+// from kamajiDir while context files resolve against workDir. A non-empty plan
+// is included for the agent to follow.
+func AssembleTaskContext(sprint *domain.Sprint, taskInfo *statemachine.TaskInfo, kamajiDir, workDir, plan string) (string, error) {
+	rules, files, history, err := taskInputs(sprint, taskInfo, kamajiDir, workDir)
+	if err != nil {
+		return "", err
+	}
+	return BuildPrompt(taskInfo, rules, files, history, plan), nil
+}
+
+// AssemblePlanContext generates the context for a task's planning session.
+func AssemblePlanContext(sprint *domain.Sprint, taskInfo *statemachine.TaskInfo, kamajiDir, workDir string) (string, error) {
+	rules, files, history, err := taskInputs(sprint, taskInfo, kamajiDir, workDir)
+	if err != nil {
+		return "", err
+	}
+	return BuildPlanPrompt(taskInfo, rules, files, history), nil
+}
+
+// taskInputs loads the rules, context files and history a task's prompt is
+// built from.
+func taskInputs(sprint *domain.Sprint, taskInfo *statemachine.TaskInfo, kamajiDir, workDir string) ([]string, []domain.ContextFile, *domain.TicketHistory, error) {
+	if sprint == nil {
+		return nil, nil, nil, errors.New("sprint is nil")
+	}
+	if taskInfo == nil {
+		return nil, nil, nil, errors.New("taskInfo is nil")
+	}
+
 	history, err := config.LoadTicketHistory(kamajiDir, taskInfo.Ticket.Name)
 	if err != nil {
-		return "", fmt.Errorf("load ticket history: %w", err)
+		return nil, nil, nil, fmt.Errorf("load ticket history: %w", err)
+	}
+
+	entries := append(append([]string{}, taskInfo.Ticket.Context...), taskInfo.Task.Context...)
+	files, err := config.LoadContext(workDir, entries)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("load context files: %w", err)
 	}
 
-	return BuildPrompt(taskInfo, sprint.Rules, history), nil
+	return TaskRules(sprint, taskInfo.Ticket, taskInfo.Task), files, history, nil
 }
@@ -1,10 +1,13 @@
 package prompt
 
 import (
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
 	"github.com/sqve/kamaji/internal/domain"
+	"github.com/sqve/kamaji/internal/statemachine"
 	"github.com/sqve/kamaji/internal/testutil"
 )
 
@@ -20,7 +23,7 @@ insights:
 
 	sprint := &domain.Sprint{
 		Name:  "test",
-		Rules: []string{"rule one"},
+		Rules: []domain.Rule{{Text: "rule one"}},
 		Tickets: []domain.Ticket{{
 			Name:        "ticket-1",
 			Branch:      "feat/ticket-1",
@@ -32,7 +35,7 @@ insights:
 			}},
 		}},
 	}
-	state := &domain.State{CurrentTicket: 0, CurrentTask: 0}
+	state := &domain.State{}
 
 	result, err := AssembleContext(sprint, state, dir)
 	if err != nil {
@@ -65,7 +68,7 @@ func TestAssembleContext_NoHistoryFile(t *testing.T) {
 			}},
 		}},
 	}
-	state := &domain.State{CurrentTicket: 0, CurrentTask: 0}
+	state := &domain.State{}
 
 	result, err := AssembleContext(sprint, state, dir)
 	if err != nil {
@@ -90,7 +93,7 @@ func TestAssembleContext_SprintComplete(t *testing.T) {
 			}},
 		}},
 	}
-	state := &domain.State{CurrentTicket: 1, CurrentTask: 0}
+	state := &domain.State{Tickets: map[string]domain.TicketState{"ticket-1": {Done: []string{"task"}}}}
 
 	result, err := AssembleContext(sprint, state, dir)
 	if err != nil {
@@ -126,3 +129,92 @@ func TestAssembleContext_NilState(t *testing.T) {
 		t.Errorf("expected 'state is nil' error, got: %v", err)
 	}
 }
+
+func TestAssembleTaskContext_UsesGivenTask(t *testing.T) {
+	dir := t.TempDir()
+
+	sprint := &domain.Sprint{
+		Name: "test",
+		Tickets: []domain.Ticket{
+			{Name: "ticket-1", Tasks: []domain.Task{{Description: "first ticket task"}}},
+			{Name: "ticket-2", Tasks: []domain.Task{{Description: "second ticket task"}}},
+		},
+	}
+	taskInfo := statemachine.TicketTask(&domain.State{}, sprint, 1)
+
+	result, err := AssembleTaskContext(sprint, taskInfo, dir, dir, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	testutil.AssertContains(t, result, "second ticket task")
+	testutil.AssertNotContains(t, result, "first ticket task")
+}
+
+func TestAssembleTaskContext_CascadesRules(t *testing.T) {
+	dir := t.TempDir()
+
+	sprint := &domain.Sprint{
+		Name:  "test",
+		Rules: []domain.Rule{{ID: "tests", Text: "Run the full suite"}, {Text: "sprint rule"}},
+		Tickets: []domain.Ticket{{
+			Name:  "ticket-1",
+			Rules: []domain.Rule{{Text: "ticket rule"}},
+			Tasks: []domain.Task{{
+				Description: "task",
+				Steps:       []string{"step one"},
+				Rules:       []domain.Rule{{ID: "tests", Text: "Run only unit tests"}},
+			}},
+		}},
+	}
+	taskInfo := statemachine.TicketTask(&domain.State{}, sprint, 0)
+
+	result, err := AssembleTaskContext(sprint, taskInfo, dir, dir, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	testutil.AssertContains(t, result, "<rules>\nRun only unit tests\nsprint rule\nticket rule\n</rules>")
+	testutil.AssertNotContains(t, result, "Run the full suite")
+	testutil.AssertContains(t, result, "<steps>\n- step one\n</steps>")
+}
+
+func TestAssembleTaskContext_InjectsContextFromWorkDir(t *testing.T) {
+	kamajiDir := t.TempDir()
+	workDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(workDir, "api.md"), []byte("ticket reference"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(workDir, "schema.sql"), []byte("task reference"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	sprint := &domain.Sprint{
+		Name: "test",
+		Tickets: []domain.Ticket{{
+			Name:    "ticket-1",
+			Context: []string{"@api.md"},
+			Tasks:   []domain.Task{{Description: "task", Context: []string{"*.sql", "api.md"}}},
+		}},
+	}
+	taskInfo := statemachine.TicketTask(&domain.State{}, sprint, 0)
+
+	result, err := AssembleTaskContext(sprint, taskInfo, kamajiDir, workDir, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	testutil.AssertContains(t, result, "<file path=\"api.md\">\nticket reference\n</file>\n<file path=\"schema.sql\">\ntask reference\n</file>")
+	if strings.Count(result, `<file path="api.md">`) != 1 {
+		t.Error("duplicate context entries should be embedded once")
+	}
+
+	sprint.Tickets[0].Context = []string{"missing.md"}
+	if _, err := AssembleTaskContext(sprint, taskInfo, kamajiDir, workDir, ""); err == nil {
+		t.Error("expected error for missing context path")
+	}
+}
+
+func TestAssembleTaskContext_NilTaskInfo(t *testing.T) {
+	_, err := AssembleTaskContext(&domain.Sprint{}, nil, t.TempDir(), t.TempDir(), "")
+	if err == nil {
+		t.Error("expected error for nil taskInfo")
+	}
+}
@@ -2,34 +2,80 @@ package prompt
 
 import (
 	"html"
+	"strconv"
 	"strings"
 
 	"github.com/sqve/kamaji/internal/domain"
 	"github.com/sqve/kamaji/internal/statemachine"
 )
 
-// BuildPrompt generates XML prompt structure for agent session injection.
-func BuildPrompt(taskInfo *statemachine.TaskInfo, rules []string, history *domain.TicketHistory) string {
+// BuildPrompt generates XML prompt structure for agent session injection. A
+// plan from a planning session is included for the agent to follow.
+func BuildPrompt(taskInfo *statemachine.TaskInfo, rules []string, files []domain.ContextFile, history *domain.TicketHistory, plan string) string {
+	return build(taskInfo, rules, files, history, plan, false)
+}
+
+// BuildPlanPrompt generates the prompt for a read-only planning session, which
+// returns its plan with submit_plan instead of changing files.
+func BuildPlanPrompt(taskInfo *statemachine.TaskInfo, rules []string, files []domain.ContextFile, history *domain.TicketHistory) string {
+	return build(taskInfo, rules, files, history, "", true)
+}
+
+// contextToolsHint points agents at the read-only MCP context tools.
+const contextToolsHint = "Call get_task, get_ticket_history, get_sprint_overview or search_insights when you need more context.\n"
+
+// maxReviewDiff bounds the diff embedded in a review prompt, in bytes.
+const maxReviewDiff = 128 * 1024
+
+// BuildReviewPrompt generates the prompt for a review session, which judges
+// the staged diff of a reported pass against the task and returns its verdict
+// with submit_review.
+func BuildReviewPrompt(taskInfo *statemachine.TaskInfo, rules []string, summary, diff string) string {
 	if taskInfo == nil {
 		return ""
 	}
 
 	var b strings.Builder
 
-	b.WriteString("<task>\n")
-	writeTicket(&b, taskInfo.Ticket)
-	writeCurrent(&b, taskInfo.Task)
-	writeSteps(&b, taskInfo.Task.Steps)
-	writeVerify(&b, taskInfo.Task.Verify)
-	b.WriteString("</task>\n")
+	writeTask(&b, taskInfo)
+	writeRules(&b, rules)
+	writeSummary(&b, summary)
+	writeDiff(&b, diff)
+	writeReviewInstructions(&b)
+
+	return b.String()
+}
+
+func build(taskInfo *statemachine.TaskInfo, rules []string, files []domain.ContextFile, history *domain.TicketHistory, plan string, planning bool) string {
+	if taskInfo == nil {
+		return ""
+	}
+
+	var b strings.Builder
 
+	writeTask(&b, taskInfo)
+	writeContext(&b, files)
 	writeRules(&b, rules)
 	writeHistory(&b, history)
-	writeInstructions(&b)
+	writePlan(&b, plan)
+	if planning {
+		writePlanInstructions(&b)
+	} else {
+		writeInstructions(&b, plan != "")
+	}
 
 	return b.String()
 }
 
+func writeTask(b *strings.Builder, taskInfo *statemachine.TaskInfo) {
+	b.WriteString("<task>\n")
+	writeTicket(b, taskInfo.Ticket)
+	writeCurrent(b, taskInfo.Task)
+	writeSteps(b, taskInfo.Task.Steps)
+	writeVerify(b, taskInfo.Task)
+	b.WriteString("</task>\n")
+}
+
 func writeTicket(b *strings.Builder, ticket *domain.Ticket) {
 	b.WriteString(`<ticket name="`)
 	b.WriteString(html.EscapeString(ticket.Name))
@@ -62,13 +108,53 @@ func writeSteps(b *strings.Builder, steps []string) {
 	b.WriteString("</steps>\n")
 }
 
-func writeVerify(b *strings.Builder, verify string) {
-	if verify == "" {
+func writeVerify(b *strings.Builder, task *domain.Task) {
+	if task.Verify == "" && task.VerifyCmd == "" {
 		return
 	}
 	b.WriteString("\n<verify>\n")
-	b.WriteString(html.EscapeString(verify))
-	b.WriteString("\n</verify>\n")
+	if task.Verify != "" {
+		b.WriteString(html.EscapeString(task.Verify))
+		b.WriteString("\n")
+	}
+	if task.VerifyCmd != "" {
+		b.WriteString("Kamaji runs this command after you report pass: ")
+		b.WriteString(html.EscapeString(task.VerifyCmd))
+		b.WriteString("\n")
+		if task.Done != nil && task.Done.ExitCode != nil {
+			b.WriteString("Expected exit code: ")
+			b.WriteString(strconv.Itoa(*task.Done.ExitCode))
+			b.WriteString("\n")
+		}
+		if task.Done != nil && task.Done.Output != "" {
+			b.WriteString("Output must match: ")
+			b.WriteString(html.EscapeString(task.Done.Output))
+			b.WriteString("\n")
+		}
+	}
+	b.WriteString("</verify>\n")
+}
+
+func writeContext(b *strings.Builder, files []domain.ContextFile) {
+	if len(files) == 0 {
+		return
+	}
+	b.WriteString("\n<context>\n")
+	for _, f := range files {
+		b.WriteString(`<file path="`)
+		b.WriteString(html.EscapeString(f.Path))
+		if f.Truncated {
+			b.WriteString(`" truncated="true`)
+		}
+		b.WriteString(`">`)
+		b.WriteString("\n")
+		b.WriteString(html.EscapeString(f.Content))
+		if !strings.HasSuffix(f.Content, "\n") {
+			b.WriteString("\n")
+		}
+		b.WriteString("</file>\n")
+	}
+	b.WriteString("</context>\n")
 }
 
 func writeRules(b *strings.Builder, rules []string) {
@@ -83,56 +169,121 @@ func writeRules(b *strings.Builder, rules []string) {
 	b.WriteString("</rules>\n")
 }
 
+// maxHistoryEntries bounds each history list in the prompt. Older entries are
+// left to the get_ticket_history tool.
+const maxHistoryEntries = 10
+
 func writeHistory(b *strings.Builder, history *domain.TicketHistory) {
 	if history == nil {
 		return
 	}
-	if len(history.Completed) == 0 && len(history.FailedAttempts) == 0 && len(history.Insights) == 0 {
+	if len(history.Completed) == 0 && len(history.FailedAttempts) == 0 && len(history.Insights) == 0 && len(history.Questions) == 0 {
 		return
 	}
 
 	b.WriteString("\n<history>\n")
 
-	if len(history.Completed) > 0 {
-		b.WriteString("<completed>\n")
-		for _, c := range history.Completed {
-			b.WriteString("- ")
-			b.WriteString(html.EscapeString(c.Task))
-			b.WriteString(": ")
-			b.WriteString(html.EscapeString(c.Summary))
-			b.WriteString("\n")
-		}
-		b.WriteString("</completed>\n")
+	completed := make([]string, len(history.Completed))
+	for i, c := range history.Completed {
+		completed[i] = c.Task + ": " + c.Summary
 	}
-
-	if len(history.FailedAttempts) > 0 {
-		b.WriteString("\n<failed_attempts>\n")
-		for _, f := range history.FailedAttempts {
-			b.WriteString("- ")
-			b.WriteString(html.EscapeString(f.Task))
-			b.WriteString(": ")
-			b.WriteString(html.EscapeString(f.Summary))
-			b.WriteString("\n")
-		}
-		b.WriteString("</failed_attempts>\n")
+	failed := make([]string, len(history.FailedAttempts))
+	for i, f := range history.FailedAttempts {
+		failed[i] = f.Task + ": " + f.Summary
 	}
 
-	if len(history.Insights) > 0 {
-		b.WriteString("\n<insights>\n")
-		for _, insight := range history.Insights {
-			b.WriteString("- ")
-			b.WriteString(html.EscapeString(insight))
-			b.WriteString("\n")
-		}
-		b.WriteString("</insights>\n")
+	omitted := writeHistoryList(b, "completed", completed, "")
+	omitted += writeHistoryList(b, "failed_attempts", failed, "\n")
+	omitted += writeHistoryList(b, "insights", history.Insights, "\n")
+	questions := make([]string, len(history.Questions))
+	for i, q := range history.Questions {
+		questions[i] = q.Question + " Answer: " + q.Answer
+	}
+	omitted += writeHistoryList(b, "questions", questions, "\n")
+	if omitted > 0 {
+		b.WriteString("\n")
+		b.WriteString(strconv.Itoa(omitted))
+		b.WriteString(" earlier entries are left out; call get_ticket_history for the full history.\n")
 	}
 
 	b.WriteString("</history>\n")
 }
 
-func writeInstructions(b *strings.Builder) {
+// writeHistoryList writes the most recent maxHistoryEntries entries as a
+// tagged list after sep and returns how many older entries it left out.
+func writeHistoryList(b *strings.Builder, tag string, entries []string, sep string) int {
+	if len(entries) == 0 {
+		return 0
+	}
+	omitted := max(len(entries)-maxHistoryEntries, 0)
+	b.WriteString(sep + "<" + tag + ">\n")
+	for _, entry := range entries[omitted:] {
+		b.WriteString("- ")
+		b.WriteString(html.EscapeString(entry))
+		b.WriteString("\n")
+	}
+	b.WriteString("</" + tag + ">\n")
+	return omitted
+}
+
+func writePlan(b *strings.Builder, plan string) {
+	if plan == "" {
+		return
+	}
+	b.WriteString("\n<plan>\n")
+	b.WriteString(html.EscapeString(strings.TrimRight(plan, "\n")))
+	b.WriteString("\n</plan>\n")
+}
+
+func writeInstructions(b *strings.Builder, planned bool) {
 	b.WriteString("\n<instructions>\n")
+	if planned {
+		b.WriteString("Follow the plan, adjusting it where the code proves it wrong.\n")
+	}
 	b.WriteString("Complete the task. Call task_complete(pass/fail, summary) when done.\n")
 	b.WriteString("Use note_insight() to record discoveries useful for future tasks.\n")
+	b.WriteString("Call report_progress(message, percent) after each step of a long task.\n")
+	b.WriteString("Call propose_task() for needed work outside this task instead of doing it.\n")
+	b.WriteString(contextToolsHint)
+	b.WriteString("</instructions>\n")
+}
+
+func writePlanInstructions(b *strings.Builder) {
+	b.WriteString("\n<instructions>\n")
+	b.WriteString("Plan the task without changing any files; changes made now are discarded.\n")
+	b.WriteString("Read the code you need, then call submit_plan(plan) with the files to change and the steps in order.\n")
+	b.WriteString("If the task cannot be done, call task_complete(fail, summary) instead.\n")
+	b.WriteString(contextToolsHint)
+	b.WriteString("</instructions>\n")
+}
+
+func writeSummary(b *strings.Builder, summary string) {
+	if summary == "" {
+		return
+	}
+	b.WriteString("\n<summary>\n")
+	b.WriteString(html.EscapeString(summary))
+	b.WriteString("\n</summary>\n")
+}
+
+func writeDiff(b *strings.Builder, diff string) {
+	b.WriteString("\n<diff")
+	if len(diff) > maxReviewDiff {
+		diff = strings.ToValidUTF8(diff[:maxReviewDiff], "")
+		b.WriteString(` truncated="true"`)
+	}
+	b.WriteString(">\n")
+	b.WriteString(html.EscapeString(diff))
+	if !strings.HasSuffix(diff, "\n") {
+		b.WriteString("\n")
+	}
+	b.WriteString("</diff>\n")
+}
+
+func writeReviewInstructions(b *strings.Builder) {
+	b.WriteString("\n<instructions>\n")
+	b.WriteString("Another agent reported this task done with the summary and diff above. Review the change without changing any files.\n")
+	b.WriteString("Check that it completes the task, meets the verify criteria and rules, and has no bugs or missing pieces.\n")
+	b.WriteString("Call submit_review(approve/reject, reasons). Rejection reasons are given to the next attempt, so make them specific.\n")
 	b.WriteString("</instructions>\n")
 }
@@ -1,6 +1,7 @@
 package prompt
 
 import (
+	"fmt"
 	"strings"
 	"testing"
 
@@ -28,7 +29,7 @@ func TestBuildPrompt_FullData(t *testing.T) {
 		Insights:       []string{"Codebase uses Zustand for state management"},
 	}
 
-	result := BuildPrompt(taskInfo, rules, history)
+	result := BuildPrompt(taskInfo, rules, nil, history, "")
 
 	// Check task section
 	if !strings.Contains(result, `<ticket name="login-form" branch="feat/login-form">`) {
@@ -103,6 +104,12 @@ func TestBuildPrompt_FullData(t *testing.T) {
 	if !strings.Contains(result, "Use note_insight() to record discoveries useful for future tasks.") {
 		t.Error("missing note_insight instruction")
 	}
+	if !strings.Contains(result, "Call report_progress(message, percent) after each step of a long task.") {
+		t.Error("missing report_progress instruction")
+	}
+	if !strings.Contains(result, "Call propose_task() for needed work outside this task instead of doing it.") {
+		t.Error("missing propose_task instruction")
+	}
 }
 
 func TestBuildPrompt_EmptyHistory(t *testing.T) {
@@ -121,14 +128,14 @@ func TestBuildPrompt_EmptyHistory(t *testing.T) {
 	rules := []string{"Rule 1"}
 
 	// Test with nil history
-	result := BuildPrompt(taskInfo, rules, nil)
+	result := BuildPrompt(taskInfo, rules, nil, nil, "")
 	if strings.Contains(result, "<history>") {
 		t.Error("should not contain history tag when history is nil")
 	}
 
 	// Test with empty history struct
 	emptyHistory := &domain.TicketHistory{}
-	result = BuildPrompt(taskInfo, rules, emptyHistory)
+	result = BuildPrompt(taskInfo, rules, nil, emptyHistory, "")
 	if strings.Contains(result, "<history>") {
 		t.Error("should not contain history tag when history is empty")
 	}
@@ -149,7 +156,7 @@ func TestBuildPrompt_EmptySteps(t *testing.T) {
 	}
 	rules := []string{"Rule 1"}
 
-	result := BuildPrompt(taskInfo, rules, nil)
+	result := BuildPrompt(taskInfo, rules, nil, nil, "")
 
 	if strings.Contains(result, "<steps>") {
 		t.Error("should not contain steps tag when steps is nil")
@@ -157,7 +164,7 @@ func TestBuildPrompt_EmptySteps(t *testing.T) {
 
 	// Test with empty slice
 	taskInfo.Task.Steps = []string{}
-	result = BuildPrompt(taskInfo, rules, nil)
+	result = BuildPrompt(taskInfo, rules, nil, nil, "")
 	if strings.Contains(result, "<steps>") {
 		t.Error("should not contain steps tag when steps is empty slice")
 	}
@@ -178,13 +185,40 @@ func TestBuildPrompt_EmptyVerify(t *testing.T) {
 	}
 	rules := []string{"Rule 1"}
 
-	result := BuildPrompt(taskInfo, rules, nil)
+	result := BuildPrompt(taskInfo, rules, nil, nil, "")
 
 	if strings.Contains(result, "<verify>") {
 		t.Error("should not contain verify tag when verify is empty")
 	}
 }
 
+func TestBuildPrompt_VerifyCommand(t *testing.T) {
+	exitCode := 0
+	taskInfo := &statemachine.TaskInfo{
+		Ticket: &domain.Ticket{Name: "test-ticket", Branch: "feat/test"},
+		Task: &domain.Task{
+			Description: "Test task",
+			VerifyCmd:   "go test ./...",
+			Done:        &domain.Done{ExitCode: &exitCode, Output: "ok\\s+"},
+		},
+	}
+
+	result := BuildPrompt(taskInfo, nil, nil, nil, "")
+
+	if !strings.Contains(result, "<verify>") {
+		t.Error("missing verify tag when only verify command is set")
+	}
+	if !strings.Contains(result, "go test ./...") {
+		t.Error("missing verify command")
+	}
+	if !strings.Contains(result, "Expected exit code: 0") {
+		t.Error("missing expected exit code")
+	}
+	if !strings.Contains(result, "Output must match: ok\\s+") {
+		t.Error("missing output pattern")
+	}
+}
+
 func TestBuildPrompt_XMLEscaping(t *testing.T) {
 	taskInfo := &statemachine.TaskInfo{
 		Ticket: &domain.Ticket{
@@ -205,7 +239,7 @@ func TestBuildPrompt_XMLEscaping(t *testing.T) {
 		Insights:       []string{"Insight with <code> & symbols"},
 	}
 
-	result := BuildPrompt(taskInfo, rules, history)
+	result := BuildPrompt(taskInfo, rules, nil, history, "")
 
 	// Check that unsafe characters are escaped
 	if strings.Contains(result, "<html>") {
@@ -232,7 +266,7 @@ func TestBuildPrompt_XMLEscaping(t *testing.T) {
 func TestBuildPrompt_NilTaskInfo(t *testing.T) {
 	rules := []string{"Rule 1"}
 
-	result := BuildPrompt(nil, rules, nil)
+	result := BuildPrompt(nil, rules, nil, nil, "")
 
 	if result != "" {
 		t.Errorf("expected empty string for nil taskInfo, got %q", result)
@@ -254,13 +288,13 @@ func TestBuildPrompt_EmptyRules(t *testing.T) {
 	}
 
 	// Test with nil rules
-	result := BuildPrompt(taskInfo, nil, nil)
+	result := BuildPrompt(taskInfo, nil, nil, nil, "")
 	if strings.Contains(result, "<rules>") {
 		t.Error("should not contain rules tag when rules is nil")
 	}
 
 	// Test with empty slice
-	result = BuildPrompt(taskInfo, []string{}, nil)
+	result = BuildPrompt(taskInfo, []string{}, nil, nil, "")
 	if strings.Contains(result, "<rules>") {
 		t.Error("should not contain rules tag when rules is empty slice")
 	}
@@ -283,7 +317,7 @@ func TestBuildPrompt_PartialHistory(t *testing.T) {
 	history := &domain.TicketHistory{
 		Completed: []domain.CompletedTask{{Task: "Task 1", Summary: "Done"}},
 	}
-	result := BuildPrompt(taskInfo, rules, history)
+	result := BuildPrompt(taskInfo, rules, nil, history, "")
 
 	if !strings.Contains(result, "<history>") {
 		t.Error("should contain history tag")
@@ -299,6 +333,45 @@ func TestBuildPrompt_PartialHistory(t *testing.T) {
 	}
 }
 
+func TestBuildPrompt_QuestionsInHistory(t *testing.T) {
+	taskInfo := &statemachine.TaskInfo{
+		Ticket: &domain.Ticket{Name: "test-ticket", Branch: "feat/test"},
+		Task:   &domain.Task{Description: "Test task"},
+	}
+	history := &domain.TicketHistory{
+		Questions: []domain.Question{{Task: "Earlier task", Question: "Keep v1 API?", Answer: "Yes"}},
+	}
+
+	result := BuildPrompt(taskInfo, nil, nil, history, "")
+
+	if !strings.Contains(result, "<questions>\n- Keep v1 API? Answer: Yes\n</questions>") {
+		t.Error("missing answered question in history")
+	}
+}
+
+func TestBuildPrompt_LongHistoryKeepsRecentEntries(t *testing.T) {
+	taskInfo := &statemachine.TaskInfo{
+		Ticket: &domain.Ticket{Name: "test-ticket", Branch: "feat/test"},
+		Task:   &domain.Task{Description: "Test task"},
+	}
+	history := &domain.TicketHistory{}
+	for i := range maxHistoryEntries + 3 {
+		history.Insights = append(history.Insights, fmt.Sprintf("insight %d", i))
+	}
+
+	result := BuildPrompt(taskInfo, nil, nil, history, "")
+
+	if strings.Contains(result, "- insight 2\n") {
+		t.Error("should leave out the oldest insights")
+	}
+	if !strings.Contains(result, "- insight 3\n") || !strings.Contains(result, fmt.Sprintf("- insight %d\n", maxHistoryEntries+2)) {
+		t.Error("should keep the most recent insights")
+	}
+	if !strings.Contains(result, "3 earlier entries are left out; call get_ticket_history") {
+		t.Error("should point to get_ticket_history for omitted entries")
+	}
+}
+
 func TestBuildPrompt_EmptyDescription(t *testing.T) {
 	taskInfo := &statemachine.TaskInfo{
 		Ticket: &domain.Ticket{
@@ -311,7 +384,7 @@ func TestBuildPrompt_EmptyDescription(t *testing.T) {
 		},
 	}
 
-	result := BuildPrompt(taskInfo, nil, nil)
+	result := BuildPrompt(taskInfo, nil, nil, nil, "")
 
 	// Should not have double newlines in ticket tag
 	if strings.Contains(result, ">\n\n</ticket>") {
@@ -321,3 +394,107 @@ func TestBuildPrompt_EmptyDescription(t *testing.T) {
 		t.Error("missing ticket tag")
 	}
 }
+
+func TestBuildPrompt_Context(t *testing.T) {
+	taskInfo := &statemachine.TaskInfo{
+		Ticket: &domain.Ticket{Name: "test-ticket", Branch: "feat/test"},
+		Task:   &domain.Task{Description: "Test task"},
+	}
+	files := []domain.ContextFile{
+		{Path: "docs/api.md", Content: "Use <Client> helpers\n"},
+		{Path: "big.log", Content: "partial", Truncated: true},
+	}
+
+	result := BuildPrompt(taskInfo, []string{"Rule 1"}, files, nil, "")
+
+	if !strings.Contains(result, "<context>\n<file path=\"docs/api.md\">\nUse &lt;Client&gt; helpers\n</file>\n") {
+		t.Error("missing escaped context file")
+	}
+	if !strings.Contains(result, "<file path=\"big.log\" truncated=\"true\">\npartial\n</file>\n</context>") {
+		t.Error("missing truncated context file")
+	}
+	if strings.Index(result, "<context>") > strings.Index(result, "<rules>") {
+		t.Error("context section should come before rules")
+	}
+
+	if result := BuildPrompt(taskInfo, nil, nil, nil, ""); strings.Contains(result, "<context>") {
+		t.Error("should not contain context tag without files")
+	}
+}
+
+func TestBuildPrompt_Plan(t *testing.T) {
+	taskInfo := &statemachine.TaskInfo{
+		Ticket: &domain.Ticket{Name: "ticket", Branch: "feat/ticket"},
+		Task:   &domain.Task{Description: "Add form"},
+	}
+
+	result := BuildPrompt(taskInfo, nil, nil, nil, "1. Edit <Form>\n")
+	if !strings.Contains(result, "<plan>\n1. Edit &lt;Form&gt;\n</plan>") {
+		t.Error("missing escaped plan section")
+	}
+	if !strings.Contains(result, "Follow the plan") {
+		t.Error("missing instruction to follow the plan")
+	}
+
+	result = BuildPrompt(taskInfo, nil, nil, nil, "")
+	if strings.Contains(result, "<plan>") || strings.Contains(result, "Follow the plan") {
+		t.Error("should not mention a plan without one")
+	}
+}
+
+func TestBuildPlanPrompt(t *testing.T) {
+	taskInfo := &statemachine.TaskInfo{
+		Ticket: &domain.Ticket{Name: "ticket", Branch: "feat/ticket"},
+		Task:   &domain.Task{Description: "Add form", Steps: []string{"Validate input"}},
+	}
+
+	result := BuildPlanPrompt(taskInfo, []string{"Rule 1"}, nil, nil)
+	for _, want := range []string{"<current>\nAdd form\n</current>", "Validate input", "Rule 1", "submit_plan(plan)"} {
+		if !strings.Contains(result, want) {
+			t.Errorf("missing %q", want)
+		}
+	}
+	if strings.Contains(result, "when done") {
+		t.Error("planning prompt should not ask to complete the task")
+	}
+}
+
+func TestBuildReviewPrompt(t *testing.T) {
+	taskInfo := &statemachine.TaskInfo{
+		Ticket: &domain.Ticket{Name: "ticket", Branch: "feat/ticket"},
+		Task:   &domain.Task{Description: "Add form", Verify: "Form validates email"},
+	}
+	diff := "diff --git a/form.go b/form.go\n+func Validate() {}\n"
+
+	result := BuildReviewPrompt(taskInfo, []string{"Rule 1"}, "Added <form>", diff)
+	for _, want := range []string{
+		"<current>\nAdd form\n</current>",
+		"<verify>\nForm validates email\n</verify>",
+		"Rule 1",
+		"<summary>\nAdded &lt;form&gt;\n</summary>",
+		"<diff>\n" + diff + "</diff>",
+		"submit_review(approve/reject, reasons)",
+	} {
+		if !strings.Contains(result, want) {
+			t.Errorf("missing %q", want)
+		}
+	}
+	if strings.Contains(result, "task_complete") {
+		t.Error("review prompt should not ask to complete the task")
+	}
+}
+
+func TestBuildReviewPrompt_TruncatesDiff(t *testing.T) {
+	taskInfo := &statemachine.TaskInfo{
+		Ticket: &domain.Ticket{Name: "ticket"},
+		Task:   &domain.Task{Description: "Add form"},
+	}
+
+	result := BuildReviewPrompt(taskInfo, nil, "", strings.Repeat("x", maxReviewDiff+10))
+	if !strings.Contains(result, `<diff truncated="true">`) {
+		t.Error("missing truncated diff marker")
+	}
+	if strings.Contains(result, strings.Repeat("x", maxReviewDiff+1)) {
+		t.Error("diff was not truncated")
+	}
+}
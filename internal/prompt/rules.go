@@ -0,0 +1,47 @@
+package prompt
+
+import "github.com/sqve/kamaji/internal/domain"
+
+// MergeRules cascades rule levels from least to most specific. Rules without
+// an ID are appended. A rule whose ID matches an inherited rule replaces it in
+// place, or drops it when Remove is set. Removing an unknown ID is a no-op.
+func MergeRules(levels ...[]domain.Rule) []string {
+	var merged []domain.Rule
+	for _, level := range levels {
+		for _, rule := range level {
+			idx := -1
+			if rule.ID != "" {
+				for i := range merged {
+					if merged[i].ID == rule.ID {
+						idx = i
+						break
+					}
+				}
+			}
+
+			switch {
+			case rule.Remove && idx >= 0:
+				merged = append(merged[:idx], merged[idx+1:]...)
+			case rule.Remove:
+			case idx >= 0:
+				merged[idx] = rule
+			default:
+				merged = append(merged, rule)
+			}
+		}
+	}
+
+	if len(merged) == 0 {
+		return nil
+	}
+	texts := make([]string, len(merged))
+	for i, rule := range merged {
+		texts[i] = rule.Text
+	}
+	return texts
+}
+
+// TaskRules returns the effective rules for a task in the sprint.
+func TaskRules(sprint *domain.Sprint, ticket *domain.Ticket, task *domain.Task) []string {
+	return MergeRules(sprint.Rules, ticket.Rules, task.Rules)
+}
@@ -0,0 +1,65 @@
+package prompt
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/sqve/kamaji/internal/domain"
+)
+
+func TestMergeRules_AppendsInOrder(t *testing.T) {
+	got := MergeRules(
+		[]domain.Rule{{Text: "sprint"}},
+		[]domain.Rule{{Text: "ticket"}},
+		[]domain.Rule{{Text: "task"}},
+	)
+
+	want := []string{"sprint", "ticket", "task"}
+	if !slices.Equal(got, want) {
+		t.Errorf("MergeRules() = %v, want %v", got, want)
+	}
+}
+
+func TestMergeRules_OverridesByID(t *testing.T) {
+	got := MergeRules(
+		[]domain.Rule{{ID: "tests", Text: "Run all tests"}, {Text: "Keep commits small"}},
+		[]domain.Rule{{ID: "tests", Text: "Run unit tests only"}},
+	)
+
+	want := []string{"Run unit tests only", "Keep commits small"}
+	if !slices.Equal(got, want) {
+		t.Errorf("MergeRules() = %v, want %v", got, want)
+	}
+}
+
+func TestMergeRules_RemovesByID(t *testing.T) {
+	got := MergeRules(
+		[]domain.Rule{{ID: "docs", Text: "Update docs"}, {Text: "Keep commits small"}},
+		[]domain.Rule{{ID: "missing", Remove: true}},
+		[]domain.Rule{{ID: "docs", Remove: true}},
+	)
+
+	want := []string{"Keep commits small"}
+	if !slices.Equal(got, want) {
+		t.Errorf("MergeRules() = %v, want %v", got, want)
+	}
+}
+
+func TestMergeRules_Empty(t *testing.T) {
+	if got := MergeRules(nil, []domain.Rule{{ID: "x", Remove: true}}); got != nil {
+		t.Errorf("MergeRules() = %v, want nil", got)
+	}
+}
+
+func TestTaskRules_CascadesLevels(t *testing.T) {
+	sprint := &domain.Sprint{Rules: []domain.Rule{{ID: "style", Text: "Follow the style guide"}}}
+	ticket := &domain.Ticket{Rules: []domain.Rule{{Text: "Touch only the api package"}}}
+	task := &domain.Task{Rules: []domain.Rule{{ID: "style", Remove: true}}}
+
+	got := TaskRules(sprint, ticket, task)
+
+	want := []string{"Touch only the api package"}
+	if !slices.Equal(got, want) {
+		t.Errorf("TaskRules() = %v, want %v", got, want)
+	}
+}
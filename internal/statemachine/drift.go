@@ -0,0 +1,172 @@
+package statemachine
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/sqve/kamaji/internal/domain"
+)
+
+// Drift describes how saved progress lines up with a sprint that was edited
+// after the progress was recorded.
+type Drift struct {
+	Orphaned []string // Progress on tickets or tasks that are no longer in the sprint
+	Remapped []string // Edits that progress follows by key, such as tasks inserted before done ones
+}
+
+// DetectDrift compares saved progress with the sprint. Progress is keyed by
+// ticket and task keys, so reordering is harmless; only progress whose keys
+// match nothing in the sprint is orphaned.
+func DetectDrift(state *domain.State, sprint *domain.Sprint) Drift {
+	var d Drift
+
+	known := make(map[string]bool, len(sprint.Tickets))
+	for i := range sprint.Tickets {
+		ticket := &sprint.Tickets[i]
+		known[ticket.Key()] = true
+		if _, ok := state.Tickets[ticket.Key()]; !ok {
+			continue
+		}
+
+		ts := ticketState(state, ticket)
+		tasks := make(map[string]bool, len(ticket.Tasks))
+		for j := range ticket.Tasks {
+			tasks[ticket.Tasks[j].Key()] = true
+		}
+		for _, key := range ts.Done {
+			if !tasks[key] {
+				d.Orphaned = append(d.Orphaned, fmt.Sprintf("ticket %q: done task %q is not in the sprint", ticket.Name, key))
+			}
+		}
+
+		p := Progress(state, ticket)
+		if ts.FailureCount > 0 && p.FailureCount == 0 {
+			d.Remapped = append(d.Remapped, fmt.Sprintf("ticket %q: %d failures on task %q no longer apply", ticket.Name, ts.FailureCount, ts.FailingTask))
+		}
+		if p.CurrentTask < len(ticket.Tasks) && p.TasksDone > 0 && lastDone(ts, ticket) > p.CurrentTask {
+			d.Remapped = append(d.Remapped, fmt.Sprintf("ticket %q: task %q comes before done tasks and runs next", ticket.Name, ticket.Tasks[p.CurrentTask].Description))
+		}
+	}
+
+	var orphans []string
+	for key := range state.Tickets {
+		if !known[key] {
+			orphans = append(orphans, key)
+		}
+	}
+	slices.Sort(orphans)
+	for _, key := range orphans {
+		d.Orphaned = append(d.Orphaned, fmt.Sprintf("ticket %q has saved progress but is not in the sprint", key))
+	}
+
+	return d
+}
+
+// AdoptIDs moves progress saved under a ticket's name or a task's description
+// to the id since set on it, so adding an id mid-sprint keeps the progress
+// instead of orphaning it. Progress already under the id, or under a name or
+// description that is still another ticket's or task's key, is left alone.
+// Reports whether anything moved.
+func AdoptIDs(state *domain.State, sprint *domain.Sprint) bool {
+	keys := make(map[string]bool, len(sprint.Tickets))
+	for i := range sprint.Tickets {
+		keys[sprint.Tickets[i].Key()] = true
+	}
+
+	moved := false
+	for i := range sprint.Tickets {
+		ticket := &sprint.Tickets[i]
+		if ticket.ID != "" && !keys[ticket.Name] {
+			_, adopted := state.Tickets[ticket.ID]
+			if ts, ok := state.Tickets[ticket.Name]; ok && !adopted {
+				state.Tickets[ticket.ID] = ts
+				delete(state.Tickets, ticket.Name)
+				moved = true
+			}
+		}
+
+		ts, ok := state.Tickets[ticket.Key()]
+		if !ok {
+			continue
+		}
+		if adoptTaskIDs(&ts, ticket) {
+			state.Tickets[ticket.Key()] = ts
+			moved = true
+		}
+	}
+	return moved
+}
+
+// adoptTaskIDs rewrites done, skipped and failing task descriptions to the ids
+// since set on those tasks.
+func adoptTaskIDs(ts *domain.TicketState, ticket *domain.Ticket) bool {
+	keys := make(map[string]bool, len(ticket.Tasks))
+	for j := range ticket.Tasks {
+		keys[ticket.Tasks[j].Key()] = true
+	}
+
+	moved := false
+	for j := range ticket.Tasks {
+		task := &ticket.Tasks[j]
+		if task.ID == "" || keys[task.Description] {
+			continue
+		}
+		if i := slices.Index(ts.Done, task.Description); i >= 0 && !slices.Contains(ts.Done, task.ID) {
+			ts.Done = slices.Clone(ts.Done)
+			ts.Done[i] = task.ID
+			moved = true
+		}
+		if i := slices.Index(ts.Skipped, task.Description); i >= 0 && !slices.Contains(ts.Skipped, task.ID) {
+			ts.Skipped = slices.Clone(ts.Skipped)
+			ts.Skipped[i] = task.ID
+			moved = true
+		}
+		if ts.FailingTask == task.Description {
+			ts.FailingTask = task.ID
+			moved = true
+		}
+	}
+	return moved
+}
+
+// PruneDrift drops saved progress on tickets and tasks that are no longer in
+// the sprint, along with failures that no longer apply.
+func PruneDrift(state *domain.State, sprint *domain.Sprint) {
+	known := make(map[string]bool, len(sprint.Tickets))
+	for i := range sprint.Tickets {
+		ticket := &sprint.Tickets[i]
+		known[ticket.Key()] = true
+		if _, ok := state.Tickets[ticket.Key()]; !ok {
+			continue
+		}
+
+		ts := ticketState(state, ticket)
+		if Progress(state, ticket).FailureCount == 0 {
+			ts.FailingTask, ts.FailureCount = "", 0
+		}
+		tasks := make(map[string]bool, len(ticket.Tasks))
+		for j := range ticket.Tasks {
+			tasks[ticket.Tasks[j].Key()] = true
+		}
+		ts.Done = slices.DeleteFunc(slices.Clone(ts.Done), func(key string) bool { return !tasks[key] })
+		ts.Skipped = slices.DeleteFunc(slices.Clone(ts.Skipped), func(key string) bool { return !tasks[key] })
+		setTicketState(state, ticket, ts)
+	}
+
+	for key := range state.Tickets {
+		if !known[key] {
+			delete(state.Tickets, key)
+		}
+	}
+}
+
+// lastDone returns the index of the ticket's last done task, or -1.
+func lastDone(ts domain.TicketState, ticket *domain.Ticket) int {
+	done := doneSet(ts)
+	for i := len(ticket.Tasks) - 1; i >= 0; i-- {
+		if done[ticket.Tasks[i].Key()] {
+			return i
+		}
+	}
+	return -1
+}
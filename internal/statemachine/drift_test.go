@@ -0,0 +1,128 @@
+package statemachine
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sqve/kamaji/internal/domain"
+)
+
+func TestDetectDrift_NoChanges(t *testing.T) {
+	sprint := twoTicketSprint()
+	state := stateWith(map[string]domain.TicketState{
+		"ticket-1": {Done: []string{"task-0"}, FailingTask: "task-1", FailureCount: 1},
+	})
+
+	if got := DetectDrift(state, sprint); len(got.Orphaned) != 0 || len(got.Remapped) != 0 {
+		t.Errorf("DetectDrift = %+v, want no drift", got)
+	}
+}
+
+func TestDetectDrift_Orphaned(t *testing.T) {
+	sprint := twoTicketSprint()
+	state := stateWith(map[string]domain.TicketState{
+		"ticket-1": {Done: []string{"task-0", "removed"}},
+		"gone":     {Done: []string{"x"}},
+	})
+
+	got := DetectDrift(state, sprint)
+
+	want := []string{
+		`ticket "ticket-1": done task "removed" is not in the sprint`,
+		`ticket "gone" has saved progress but is not in the sprint`,
+	}
+	if !reflect.DeepEqual(got.Orphaned, want) {
+		t.Errorf("Orphaned = %q, want %q", got.Orphaned, want)
+	}
+}
+
+func TestDetectDrift_Remapped(t *testing.T) {
+	sprint := twoTicketSprint()
+	sprint.Tickets[0].Tasks = []domain.Task{{Description: "inserted"}, {Description: "task-0"}, {Description: "task-1"}}
+	state := stateWith(map[string]domain.TicketState{
+		"ticket-1": {Done: []string{"task-0"}, FailingTask: "task-1", FailureCount: 2},
+	})
+
+	got := DetectDrift(state, sprint)
+
+	want := []string{
+		`ticket "ticket-1": 2 failures on task "task-1" no longer apply`,
+		`ticket "ticket-1": task "inserted" comes before done tasks and runs next`,
+	}
+	if len(got.Orphaned) != 0 {
+		t.Errorf("Orphaned = %q, want none", got.Orphaned)
+	}
+	if !reflect.DeepEqual(got.Remapped, want) {
+		t.Errorf("Remapped = %q, want %q", got.Remapped, want)
+	}
+}
+
+func TestPruneDrift(t *testing.T) {
+	sprint := twoTicketSprint()
+	state := stateWith(map[string]domain.TicketState{
+		"ticket-1": {Done: []string{"removed", "task-0"}, Skipped: []string{"removed", "task-0"}, FailingTask: "removed-too", FailureCount: 2},
+		"gone":     {Done: []string{"x"}},
+	})
+
+	PruneDrift(state, sprint)
+
+	if _, ok := state.Tickets["gone"]; ok {
+		t.Error("progress for removed ticket kept, want dropped")
+	}
+	got := state.Tickets["ticket-1"]
+	want := domain.TicketState{Done: []string{"task-0"}, Skipped: []string{"task-0"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ticket-1 = %+v, want %+v", got, want)
+	}
+	if d := DetectDrift(state, sprint); len(d.Orphaned) != 0 || len(d.Remapped) != 0 {
+		t.Errorf("DetectDrift after prune = %+v, want no drift", d)
+	}
+}
+
+func TestAdoptIDs(t *testing.T) {
+	sprint := twoTicketSprint()
+	sprint.Tickets[0].ID = "T1"
+	sprint.Tickets[0].Tasks[0].ID = "setup"
+	sprint.Tickets[0].Tasks[1].ID = "tests"
+	state := stateWith(map[string]domain.TicketState{
+		"ticket-1": {Done: []string{"task-0"}, Skipped: []string{"task-0"}, FailingTask: "task-1", FailureCount: 2},
+		"ticket-2": {Done: []string{"task-0"}},
+	})
+
+	if d := DetectDrift(state, sprint); len(d.Orphaned) == 0 {
+		t.Fatal("DetectDrift before AdoptIDs found no orphans, want the ticket saved by name")
+	}
+	if !AdoptIDs(state, sprint) {
+		t.Fatal("AdoptIDs = false, want true")
+	}
+
+	want := map[string]domain.TicketState{
+		"T1":       {Done: []string{"setup"}, Skipped: []string{"setup"}, FailingTask: "tests", FailureCount: 2},
+		"ticket-2": {Done: []string{"task-0"}},
+	}
+	if !reflect.DeepEqual(state.Tickets, want) {
+		t.Errorf("Tickets = %+v, want %+v", state.Tickets, want)
+	}
+	if d := DetectDrift(state, sprint); len(d.Orphaned) != 0 || len(d.Remapped) != 0 {
+		t.Errorf("DetectDrift after AdoptIDs = %+v, want no drift", d)
+	}
+	if AdoptIDs(state, sprint) {
+		t.Error("second AdoptIDs = true, want nothing left to move")
+	}
+}
+
+func TestAdoptIDs_KeepsProgressUnderID(t *testing.T) {
+	sprint := twoTicketSprint()
+	sprint.Tickets[0].ID = "T1"
+	state := stateWith(map[string]domain.TicketState{
+		"T1":       {Done: []string{"task-0", "task-1"}},
+		"ticket-1": {Done: []string{"task-0"}},
+	})
+
+	if AdoptIDs(state, sprint) {
+		t.Error("AdoptIDs = true, want progress under the id left alone")
+	}
+	if got := state.Tickets["T1"].Done; len(got) != 2 {
+		t.Errorf("T1 Done = %q, want both tasks", got)
+	}
+}
@@ -1,10 +1,28 @@
 package statemachine
 
-import "github.com/sqve/kamaji/internal/domain"
+import (
+	"math"
+	"slices"
+	"time"
 
-// StuckThreshold allows transient failures while preventing infinite retry loops.
+	"github.com/sqve/kamaji/internal/domain"
+)
+
+// StuckThreshold is the default max_attempts. It allows transient failures
+// while preventing infinite retry loops.
 const StuckThreshold = 3
 
+// DefaultVerifyTimeout limits verify_cmd when neither verify_timeout nor a
+// session timeout is set.
+const DefaultVerifyTimeout = 30 * time.Minute
+
+// DefaultQuestionTimeout is how long an ask_human question waits for an
+// answer when the sprint sets no question_timeout.
+const DefaultQuestionTimeout = 10 * time.Minute
+
+// MaxRetryDelay caps the backed-off delay between attempts.
+const MaxRetryDelay = time.Hour
+
 type TaskInfo struct {
 	TicketIndex int
 	TaskIndex   int
@@ -12,51 +30,397 @@ type TaskInfo struct {
 	Task        *domain.Task
 }
 
-// NextTask returns nil when the sprint is complete.
-func NextTask(state *domain.State, sprint *domain.Sprint) *TaskInfo {
-	if state.CurrentTicket >= len(sprint.Tickets) {
+// TicketProgress is a ticket's saved state resolved against its current tasks.
+type TicketProgress struct {
+	CurrentTask  int // Index of the first task not done, len(ticket.Tasks) once complete
+	FailureCount int // Consecutive failures on the current task
+	TasksDone    int
+	TasksSkipped int // Done tasks that were skipped rather than passed
+}
+
+// Started is true once any task has passed, been skipped or failed.
+func (p TicketProgress) Started() bool {
+	return p.TasksDone > 0 || p.FailureCount > 0
+}
+
+// Progress resolves the ticket's saved state against its tasks. Unstarted
+// tickets have zero progress. Failures recorded on a task that is no longer
+// current are not counted.
+func Progress(state *domain.State, ticket *domain.Ticket) TicketProgress {
+	ts := ticketState(state, ticket)
+	done := doneSet(ts)
+
+	p := TicketProgress{CurrentTask: len(ticket.Tasks)}
+	for i := range ticket.Tasks {
+		switch {
+		case done[ticket.Tasks[i].Key()]:
+			p.TasksDone++
+			if slices.Contains(ts.Skipped, ticket.Tasks[i].Key()) {
+				p.TasksSkipped++
+			}
+		case p.CurrentTask == len(ticket.Tasks):
+			p.CurrentTask = i
+		}
+	}
+
+	if p.CurrentTask < len(ticket.Tasks) && ts.FailingTask == ticket.Tasks[p.CurrentTask].Key() {
+		p.FailureCount = ts.FailureCount
+	}
+	return p
+}
+
+func ticketState(state *domain.State, ticket *domain.Ticket) domain.TicketState {
+	return state.Tickets[ticket.Key()]
+}
+
+// MigrateState moves the single cursor of earlier releases into per-ticket
+// progress. Those releases ran tickets in order, so every ticket before the
+// cursor's is done, as are the tasks before its task. Reports whether there
+// was anything to migrate.
+func MigrateState(state *domain.State, sprint *domain.Sprint) bool {
+	if state.LegacyTicket == nil {
+		return false
+	}
+	current := *state.LegacyTicket
+	for i := range min(current+1, len(sprint.Tickets)) {
+		ticket := &sprint.Tickets[i]
+		if i < current {
+			Goto(state, ticket, len(ticket.Tasks))
+			continue
+		}
+		if state.LegacyTask == 0 && state.LegacyFailures == 0 {
+			break // Not started
+		}
+		Goto(state, ticket, state.LegacyTask)
+		if state.LegacyFailures > 0 && state.LegacyTask < len(ticket.Tasks) {
+			ts := ticketState(state, ticket)
+			ts.FailingTask = ticket.Tasks[state.LegacyTask].Key()
+			ts.FailureCount = state.LegacyFailures
+			setTicketState(state, ticket, ts)
+		}
+	}
+	state.LegacyTicket, state.LegacyTask, state.LegacyFailures = nil, 0, 0
+	return true
+}
+
+func doneSet(ts domain.TicketState) map[string]bool {
+	done := make(map[string]bool, len(ts.Done))
+	for _, key := range ts.Done {
+		done[key] = true
+	}
+	return done
+}
+
+func setTicketState(state *domain.State, ticket *domain.Ticket, ts domain.TicketState) {
+	if state.Tickets == nil {
+		state.Tickets = make(map[string]domain.TicketState)
+	}
+	state.Tickets[ticket.Key()] = ts
+}
+
+// TicketComplete is true once every task in the ticket is done. Empty tickets are complete.
+func TicketComplete(state *domain.State, ticket *domain.Ticket) bool {
+	return Progress(state, ticket).CurrentTask >= len(ticket.Tasks)
+}
+
+// SprintComplete is true once every ticket is complete.
+func SprintComplete(state *domain.State, sprint *domain.Sprint) bool {
+	for i := range sprint.Tickets {
+		if !TicketComplete(state, &sprint.Tickets[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// DependenciesMet is true when every ticket listed in depends_on is complete.
+// Unknown dependency names never complete; validation rejects them up front.
+func DependenciesMet(state *domain.State, sprint *domain.Sprint, ticket *domain.Ticket) bool {
+	for _, dep := range ticket.DependsOn {
+		depTicket := FindTicket(sprint, dep)
+		if depTicket == nil || !TicketComplete(state, depTicket) {
+			return false
+		}
+	}
+	return true
+}
+
+// ReadyTickets returns the indexes of incomplete tickets whose dependencies are
+// complete, in sprint order.
+func ReadyTickets(state *domain.State, sprint *domain.Sprint) []int {
+	var ready []int
+	for i := range sprint.Tickets {
+		ticket := &sprint.Tickets[i]
+		if TicketComplete(state, ticket) || !DependenciesMet(state, sprint, ticket) {
+			continue
+		}
+		ready = append(ready, i)
+	}
+	return ready
+}
+
+// FindTicket returns nil when no ticket has the given name.
+func FindTicket(sprint *domain.Sprint, name string) *domain.Ticket {
+	for i := range sprint.Tickets {
+		if sprint.Tickets[i].Name == name {
+			return &sprint.Tickets[i]
+		}
+	}
+	return nil
+}
+
+// TicketTask returns nil when the ticket is complete or the index is out of range.
+func TicketTask(state *domain.State, sprint *domain.Sprint, ticketIndex int) *TaskInfo {
+	if ticketIndex < 0 || ticketIndex >= len(sprint.Tickets) {
 		return nil
 	}
 
-	ticket := &sprint.Tickets[state.CurrentTicket]
-	if state.CurrentTask >= len(ticket.Tasks) {
+	ticket := &sprint.Tickets[ticketIndex]
+	taskIndex := Progress(state, ticket).CurrentTask
+	if taskIndex >= len(ticket.Tasks) {
 		return nil
 	}
 
 	return &TaskInfo{
-		TicketIndex: state.CurrentTicket,
-		TaskIndex:   state.CurrentTask,
+		TicketIndex: ticketIndex,
+		TaskIndex:   taskIndex,
 		Ticket:      ticket,
-		Task:        &ticket.Tasks[state.CurrentTask],
+		Task:        &ticket.Tasks[taskIndex],
 	}
 }
 
-// Advance is a no-op when state is past the sprint end.
-func Advance(state *domain.State, sprint *domain.Sprint) {
-	if state.CurrentTicket >= len(sprint.Tickets) {
-		return
+// NextTask returns the next task of the first ready ticket.
+// Returns nil when the sprint is complete or every remaining ticket is waiting
+// on an incomplete dependency.
+func NextTask(state *domain.State, sprint *domain.Sprint) *TaskInfo {
+	ready := ReadyTickets(state, sprint)
+	if len(ready) == 0 {
+		return nil
 	}
+	return TicketTask(state, sprint, ready[0])
+}
 
-	ticket := &sprint.Tickets[state.CurrentTicket]
-	state.CurrentTask++
-
-	if state.CurrentTask >= len(ticket.Tasks) {
-		state.CurrentTicket++
-		state.CurrentTask = 0
+// Advance marks the ticket's current task done. It is a no-op when the ticket
+// is already complete.
+func Advance(state *domain.State, ticket *domain.Ticket) {
+	current := Progress(state, ticket).CurrentTask
+	if current >= len(ticket.Tasks) {
+		return
 	}
+	ts := ticketState(state, ticket)
+	ts.Done = append(ts.Done, ticket.Tasks[current].Key())
+	setTicketState(state, ticket, ts)
 }
 
 // RecordPass resets failure count because failures are tracked per-task.
-func RecordPass(state *domain.State, sprint *domain.Sprint) {
-	state.FailureCount = 0
-	Advance(state, sprint)
+func RecordPass(state *domain.State, ticket *domain.Ticket) {
+	ResetFailures(state, ticket)
+	Advance(state, ticket)
 }
 
 // RecordFail stays on the task to allow retries before giving up.
-func RecordFail(state *domain.State) {
-	state.FailureCount++
+func RecordFail(state *domain.State, ticket *domain.Ticket) {
+	p := Progress(state, ticket)
+	if p.CurrentTask >= len(ticket.Tasks) {
+		return
+	}
+	ts := ticketState(state, ticket)
+	ts.FailingTask = ticket.Tasks[p.CurrentTask].Key()
+	ts.FailureCount = p.FailureCount + 1
+	setTicketState(state, ticket, ts)
 }
 
-func IsStuck(state *domain.State) bool {
-	return state.FailureCount >= StuckThreshold
+// Skip moves past the ticket's current task without it passing. The task
+// counts as done but is recorded as skipped.
+func Skip(state *domain.State, ticket *domain.Ticket) {
+	current := Progress(state, ticket).CurrentTask
+	if current >= len(ticket.Tasks) {
+		return
+	}
+	RecordPass(state, ticket)
+	ts := ticketState(state, ticket)
+	ts.Skipped = append(ts.Skipped, ticket.Tasks[current].Key())
+	setTicketState(state, ticket, ts)
+}
+
+// SkippedTasks returns the ticket's done tasks that were skipped, in order.
+func SkippedTasks(state *domain.State, ticket *domain.Ticket) []*domain.Task {
+	ts := ticketState(state, ticket)
+	done := doneSet(ts)
+	var skipped []*domain.Task
+	for i := range ticket.Tasks {
+		if key := ticket.Tasks[i].Key(); done[key] && slices.Contains(ts.Skipped, key) {
+			skipped = append(skipped, &ticket.Tasks[i])
+		}
+	}
+	return skipped
+}
+
+// ResetFailures gives the ticket's current task a fresh set of attempts.
+func ResetFailures(state *domain.State, ticket *domain.Ticket) {
+	ts := ticketState(state, ticket)
+	ts.FailingTask = ""
+	ts.FailureCount = 0
+	setTicketState(state, ticket, ts)
+}
+
+// Goto moves the ticket to the given task with a fresh set of attempts: the
+// tasks before it are done and the rest are not. The index is clamped to the
+// ticket's tasks; len(ticket.Tasks) marks it complete. Tasks that stay done
+// keep being recorded as skipped if they were.
+func Goto(state *domain.State, ticket *domain.Ticket, taskIndex int) {
+	taskIndex = max(0, min(taskIndex, len(ticket.Tasks)))
+	skipped := ticketState(state, ticket).Skipped
+	var ts domain.TicketState
+	for i := range taskIndex {
+		key := ticket.Tasks[i].Key()
+		ts.Done = append(ts.Done, key)
+		if slices.Contains(skipped, key) {
+			ts.Skipped = append(ts.Skipped, key)
+		}
+	}
+	setTicketState(state, ticket, ts)
+}
+
+// Reset forgets all progress on the ticket so it starts over from its first task.
+func Reset(state *domain.State, ticket *domain.Ticket) {
+	delete(state.Tickets, ticket.Key())
+}
+
+// IsStuck is true once the ticket's current task has failed as many times as
+// its effective max_attempts. Complete tickets are never stuck.
+func IsStuck(state *domain.State, sprint *domain.Sprint, ticket *domain.Ticket) bool {
+	progress := Progress(state, ticket)
+	if progress.CurrentTask >= len(ticket.Tasks) {
+		return false
+	}
+	task := &ticket.Tasks[progress.CurrentTask]
+	return progress.FailureCount >= maxAttempts(sprint, ticket, task)
+}
+
+// Timeouts returns the wall-clock and idle limits for a task, taking the most
+// specific value set on the task, ticket or sprint. Zero means no limit.
+func Timeouts(sprint *domain.Sprint, info *TaskInfo) (timeout, idle time.Duration) {
+	timeout = time.Duration(mostSpecific(sprint.Timeout, info.Ticket.Timeout, info.Task.Timeout))
+	idle = time.Duration(mostSpecific(sprint.IdleTimeout, info.Ticket.IdleTimeout, info.Task.IdleTimeout))
+	return timeout, idle
+}
+
+// VerifyTimeout returns how long a task's verify_cmd may run: its
+// verify_timeout, else the task's wall-clock timeout, else
+// DefaultVerifyTimeout.
+func VerifyTimeout(sprint *domain.Sprint, info *TaskInfo) time.Duration {
+	if d := time.Duration(info.Task.VerifyTimeout); d > 0 {
+		return d
+	}
+	if timeout, _ := Timeouts(sprint, info); timeout > 0 {
+		return timeout
+	}
+	return DefaultVerifyTimeout
+}
+
+// QuestionTimeout returns how long an ask_human question waits for an answer,
+// defaulting to DefaultQuestionTimeout.
+func QuestionTimeout(sprint *domain.Sprint) time.Duration {
+	if d := time.Duration(sprint.QuestionTimeout); d > 0 {
+		return d
+	}
+	return DefaultQuestionTimeout
+}
+
+// mostSpecific returns the last non-zero value, ordered from sprint to task.
+func mostSpecific[T comparable](values ...T) T {
+	var zero, result T
+	for _, v := range values {
+		if v != zero {
+			result = v
+		}
+	}
+	return result
+}
+
+// MaxAttempts returns the most specific max_attempts for a task, defaulting
+// to StuckThreshold.
+func MaxAttempts(sprint *domain.Sprint, info *TaskInfo) int {
+	return maxAttempts(sprint, info.Ticket, info.Task)
+}
+
+func maxAttempts(sprint *domain.Sprint, ticket *domain.Ticket, task *domain.Task) int {
+	if n := mostSpecific(sprint.MaxAttempts, ticket.MaxAttempts, task.MaxAttempts); n > 0 {
+		return n
+	}
+	return StuckThreshold
+}
+
+// Model returns the model for the next attempt once a task has failed the
+// given number of times. The most specific level that sets model or
+// escalation decides; an escalation list picks the model by attempt and keeps
+// its last model for any further attempts. Empty means the agent's default.
+func Model(sprint *domain.Sprint, info *TaskInfo, failures int) string {
+	levels := []struct {
+		model      string
+		escalation []string
+	}{
+		{info.Task.Model, info.Task.Escalation},
+		{info.Ticket.Model, info.Ticket.Escalation},
+		{sprint.Model, sprint.Escalation},
+	}
+	for _, l := range levels {
+		if n := len(l.escalation); n > 0 {
+			return l.escalation[min(max(failures, 0), n-1)]
+		}
+		if l.model != "" {
+			return l.model
+		}
+	}
+	return ""
+}
+
+// Effort returns the most specific reasoning effort for a task. Empty means
+// the agent's default.
+func Effort(sprint *domain.Sprint, info *TaskInfo) string {
+	return mostSpecific(sprint.Effort, info.Ticket.Effort, info.Task.Effort)
+}
+
+// Plan reports whether a task's attempts start with a planning session, taking
+// the most specific plan setting so a task can opt out of a sprint default.
+func Plan(sprint *domain.Sprint, info *TaskInfo) bool {
+	return firstSet(info.Task.Plan, info.Ticket.Plan, sprint.Plan)
+}
+
+// Review reports whether a task's passes are reviewed before they are
+// committed, taking the most specific review setting.
+func Review(sprint *domain.Sprint, info *TaskInfo) bool {
+	return firstSet(info.Task.Review, info.Ticket.Review, sprint.Review)
+}
+
+// firstSet returns the first flag that is set, or false when none is.
+func firstSet(flags ...*bool) bool {
+	for _, f := range flags {
+		if f != nil {
+			return *f
+		}
+	}
+	return false
+}
+
+// RetryDelay returns the wait before the next attempt once a task has failed
+// the given number of times. The first retry waits retry_delay and each
+// further retry multiplies it by retry_backoff, capped at MaxRetryDelay.
+func RetryDelay(sprint *domain.Sprint, info *TaskInfo, failures int) time.Duration {
+	delay := time.Duration(mostSpecific(sprint.RetryDelay, info.Ticket.RetryDelay, info.Task.RetryDelay))
+	if delay <= 0 || failures <= 0 {
+		return 0
+	}
+
+	backoff := mostSpecific(sprint.RetryBackoff, info.Ticket.RetryBackoff, info.Task.RetryBackoff)
+	if backoff < 1 {
+		backoff = 1
+	}
+
+	scaled := float64(delay) * math.Pow(backoff, float64(failures-1))
+	if scaled >= float64(MaxRetryDelay) {
+		return MaxRetryDelay
+	}
+	return time.Duration(scaled)
 }
@@ -1,13 +1,15 @@
 package statemachine
 
 import (
+	"reflect"
 	"testing"
+	"time"
 
 	"github.com/sqve/kamaji/internal/domain"
 )
 
-func TestNextTask_ReturnsTaskInfoForValidPosition(t *testing.T) {
-	sprint := &domain.Sprint{
+func twoTicketSprint() *domain.Sprint {
+	return &domain.Sprint{
 		Tickets: []domain.Ticket{
 			{
 				Name: "ticket-1",
@@ -24,7 +26,15 @@ func TestNextTask_ReturnsTaskInfoForValidPosition(t *testing.T) {
 			},
 		},
 	}
-	state := &domain.State{CurrentTicket: 0, CurrentTask: 0}
+}
+
+func stateWith(progress map[string]domain.TicketState) *domain.State {
+	return &domain.State{Tickets: progress}
+}
+
+func TestNextTask_ReturnsTaskInfoForValidPosition(t *testing.T) {
+	sprint := twoTicketSprint()
+	state := &domain.State{}
 
 	info := NextTask(state, sprint)
 
@@ -46,18 +56,8 @@ func TestNextTask_ReturnsTaskInfoForValidPosition(t *testing.T) {
 }
 
 func TestNextTask_ReturnsTaskInfoAtMidTicket(t *testing.T) {
-	sprint := &domain.Sprint{
-		Tickets: []domain.Ticket{
-			{
-				Name: "ticket-1",
-				Tasks: []domain.Task{
-					{Description: "task-0"},
-					{Description: "task-1"},
-				},
-			},
-		},
-	}
-	state := &domain.State{CurrentTicket: 0, CurrentTask: 1}
+	sprint := twoTicketSprint()
+	state := stateWith(map[string]domain.TicketState{"ticket-1": {Done: []string{"task-0"}}})
 
 	info := NextTask(state, sprint)
 
@@ -72,18 +72,29 @@ func TestNextTask_ReturnsTaskInfoAtMidTicket(t *testing.T) {
 	}
 }
 
-func TestNextTask_ReturnsNilWhenPastEnd(t *testing.T) {
-	sprint := &domain.Sprint{
-		Tickets: []domain.Ticket{
-			{
-				Name: "ticket-1",
-				Tasks: []domain.Task{
-					{Description: "task-0"},
-				},
-			},
-		},
+func TestNextTask_MovesToNextIncompleteTicket(t *testing.T) {
+	sprint := twoTicketSprint()
+	state := stateWith(map[string]domain.TicketState{"ticket-1": {Done: []string{"task-0", "task-1"}}})
+
+	info := NextTask(state, sprint)
+
+	if info == nil {
+		t.Fatal("expected TaskInfo, got nil")
 	}
-	state := &domain.State{CurrentTicket: 2, CurrentTask: 0}
+	if info.TicketIndex != 1 {
+		t.Errorf("TicketIndex = %d, want 1", info.TicketIndex)
+	}
+	if info.TaskIndex != 0 {
+		t.Errorf("TaskIndex = %d, want 0", info.TaskIndex)
+	}
+}
+
+func TestNextTask_ReturnsNilWhenComplete(t *testing.T) {
+	sprint := twoTicketSprint()
+	state := stateWith(map[string]domain.TicketState{
+		"ticket-1": {Done: []string{"task-0", "task-1"}},
+		"ticket-2": {Done: []string{"task-0"}},
+	})
 
 	info := NextTask(state, sprint)
 
@@ -92,19 +103,34 @@ func TestNextTask_ReturnsNilWhenPastEnd(t *testing.T) {
 	}
 }
 
-func TestNextTask_ReturnsNilForEmptyTicket(t *testing.T) {
+func TestNextTask_SkipsEmptyTicket(t *testing.T) {
 	sprint := &domain.Sprint{
 		Tickets: []domain.Ticket{
-			{
-				Name:  "empty-ticket",
-				Tasks: []domain.Task{},
-			},
+			{Name: "empty-ticket", Tasks: []domain.Task{}},
+			{Name: "ticket-2", Tasks: []domain.Task{{Description: "task-0"}}},
 		},
 	}
-	state := &domain.State{CurrentTicket: 0, CurrentTask: 0}
+	state := &domain.State{}
 
 	info := NextTask(state, sprint)
 
+	if info == nil {
+		t.Fatal("expected TaskInfo, got nil")
+	}
+	if info.Ticket.Name != "ticket-2" {
+		t.Errorf("Ticket.Name = %q, want %q", info.Ticket.Name, "ticket-2")
+	}
+}
+
+func TestNextTask_ReturnsNilForOnlyEmptyTicket(t *testing.T) {
+	sprint := &domain.Sprint{
+		Tickets: []domain.Ticket{
+			{Name: "empty-ticket", Tasks: []domain.Task{}},
+		},
+	}
+
+	info := NextTask(&domain.State{}, sprint)
+
 	if info != nil {
 		t.Errorf("expected nil for empty ticket, got TaskInfo{TicketIndex: %d, TaskIndex: %d}", info.TicketIndex, info.TaskIndex)
 	}
@@ -114,9 +140,8 @@ func TestNextTask_ReturnsNilForEmptySprint(t *testing.T) {
 	sprint := &domain.Sprint{
 		Tickets: []domain.Ticket{},
 	}
-	state := &domain.State{CurrentTicket: 0, CurrentTask: 0}
 
-	info := NextTask(state, sprint)
+	info := NextTask(&domain.State{}, sprint)
 
 	if info != nil {
 		t.Errorf("expected nil for empty sprint, got TaskInfo{TicketIndex: %d, TaskIndex: %d}", info.TicketIndex, info.TaskIndex)
@@ -126,15 +151,10 @@ func TestNextTask_ReturnsNilForEmptySprint(t *testing.T) {
 func TestNextTask_HandlesOutOfBoundsTaskIndex(t *testing.T) {
 	sprint := &domain.Sprint{
 		Tickets: []domain.Ticket{
-			{
-				Name: "ticket-1",
-				Tasks: []domain.Task{
-					{Description: "task-0"},
-				},
-			},
+			{Name: "ticket-1", Tasks: []domain.Task{{Description: "task-0"}}},
 		},
 	}
-	state := &domain.State{CurrentTicket: 0, CurrentTask: 5}
+	state := stateWith(map[string]domain.TicketState{"ticket-1": {Done: []string{"task-0", "removed"}}})
 
 	info := NextTask(state, sprint)
 
@@ -143,232 +163,614 @@ func TestNextTask_HandlesOutOfBoundsTaskIndex(t *testing.T) {
 	}
 }
 
-func TestAdvance_IncrementsTask(t *testing.T) {
+func TestNextTask_WaitsForDependency(t *testing.T) {
 	sprint := &domain.Sprint{
 		Tickets: []domain.Ticket{
-			{
-				Name: "ticket-1",
-				Tasks: []domain.Task{
-					{Description: "task-0"},
-					{Description: "task-1"},
-					{Description: "task-2"},
-				},
-			},
+			{Name: "api", DependsOn: []string{"schema"}, Tasks: []domain.Task{{Description: "api-0"}}},
+			{Name: "schema", Tasks: []domain.Task{{Description: "schema-0"}}},
 		},
 	}
-	state := &domain.State{CurrentTicket: 0, CurrentTask: 0}
 
-	Advance(state, sprint)
+	state := &domain.State{}
 
-	if state.CurrentTicket != 0 {
-		t.Errorf("CurrentTicket = %d, want 0", state.CurrentTicket)
+	info := NextTask(state, sprint)
+	if info == nil || info.Ticket.Name != "schema" {
+		t.Fatalf("expected schema first, got %+v", info)
 	}
-	if state.CurrentTask != 1 {
-		t.Errorf("CurrentTask = %d, want 1", state.CurrentTask)
+
+	RecordPass(state, info.Ticket)
+
+	info = NextTask(state, sprint)
+	if info == nil || info.Ticket.Name != "api" {
+		t.Fatalf("expected api after schema completes, got %+v", info)
 	}
 }
 
-func TestAdvance_HandlesTicketBoundary(t *testing.T) {
+func TestNextTask_ReturnsNilWhenBlocked(t *testing.T) {
 	sprint := &domain.Sprint{
 		Tickets: []domain.Ticket{
-			{
-				Name: "ticket-1",
-				Tasks: []domain.Task{
-					{Description: "task-0"},
-					{Description: "task-1"},
-					{Description: "task-2"},
-				},
-			},
-			{
-				Name: "ticket-2",
-				Tasks: []domain.Task{
-					{Description: "task-0"},
-				},
-			},
+			{Name: "a", DependsOn: []string{"missing"}, Tasks: []domain.Task{{Description: "a-0"}}},
 		},
 	}
-	state := &domain.State{CurrentTicket: 0, CurrentTask: 2}
-
-	Advance(state, sprint)
 
-	if state.CurrentTicket != 1 {
-		t.Errorf("CurrentTicket = %d, want 1", state.CurrentTicket)
+	if info := NextTask(&domain.State{}, sprint); info != nil {
+		t.Errorf("expected nil when dependency can never complete, got %+v", info)
 	}
-	if state.CurrentTask != 0 {
-		t.Errorf("CurrentTask = %d, want 0", state.CurrentTask)
+	if SprintComplete(&domain.State{}, sprint) {
+		t.Error("SprintComplete = true, want false for blocked ticket")
 	}
 }
 
-func TestAdvance_StopsAtEnd(t *testing.T) {
+func TestReadyTickets_ReturnsIndependentTickets(t *testing.T) {
 	sprint := &domain.Sprint{
 		Tickets: []domain.Ticket{
-			{
-				Name: "ticket-1",
-				Tasks: []domain.Task{
-					{Description: "task-0"},
-				},
-			},
+			{Name: "a", Tasks: []domain.Task{{Description: "a-0"}}},
+			{Name: "b", Tasks: []domain.Task{{Description: "b-0"}}},
+			{Name: "c", DependsOn: []string{"a", "b"}, Tasks: []domain.Task{{Description: "c-0"}}},
+			{Name: "d", Tasks: []domain.Task{}},
 		},
 	}
-	state := &domain.State{CurrentTicket: 0, CurrentTask: 0}
-
-	Advance(state, sprint)
 
-	// Now at position (1, 0) - past end, NextTask returns nil
-	if state.CurrentTicket != 1 {
-		t.Errorf("CurrentTicket = %d, want 1", state.CurrentTicket)
+	ready := ReadyTickets(&domain.State{}, sprint)
+	if len(ready) != 2 || ready[0] != 0 || ready[1] != 1 {
+		t.Errorf("ReadyTickets = %v, want [0 1]", ready)
 	}
-	if state.CurrentTask != 0 {
-		t.Errorf("CurrentTask = %d, want 0", state.CurrentTask)
+
+	state := stateWith(map[string]domain.TicketState{"a": {Done: []string{"a-0"}}})
+	ready = ReadyTickets(state, sprint)
+	if len(ready) != 1 || ready[0] != 1 {
+		t.Errorf("ReadyTickets = %v, want [1]", ready)
 	}
 
-	// Verify NextTask returns nil
-	info := NextTask(state, sprint)
-	if info != nil {
-		t.Error("expected NextTask to return nil after advancing past end")
+	state.Tickets["b"] = domain.TicketState{Done: []string{"b-0"}}
+	ready = ReadyTickets(state, sprint)
+	if len(ready) != 1 || ready[0] != 2 {
+		t.Errorf("ReadyTickets = %v, want [2]", ready)
 	}
 }
 
-func TestAdvance_EmptySprint(t *testing.T) {
-	sprint := &domain.Sprint{
-		Tickets: []domain.Ticket{},
+func TestTicketTask_ReturnsPerTicketPosition(t *testing.T) {
+	sprint := twoTicketSprint()
+	state := stateWith(map[string]domain.TicketState{"ticket-1": {Done: []string{"task-0"}}})
+
+	info := TicketTask(state, sprint, 1)
+	if info == nil {
+		t.Fatal("expected TaskInfo, got nil")
+	}
+	if info.TicketIndex != 1 || info.TaskIndex != 0 {
+		t.Errorf("got (%d, %d), want (1, 0)", info.TicketIndex, info.TaskIndex)
+	}
+
+	if info := TicketTask(state, sprint, 5); info != nil {
+		t.Error("expected nil for out-of-range ticket index")
 	}
-	state := &domain.State{CurrentTicket: 0, CurrentTask: 0}
+}
+
+func TestAdvance_IncrementsTask(t *testing.T) {
+	sprint := twoTicketSprint()
+	state := &domain.State{}
 
-	// Should not panic
-	Advance(state, sprint)
+	Advance(state, &sprint.Tickets[0])
 
-	// State unchanged
-	if state.CurrentTicket != 0 {
-		t.Errorf("CurrentTicket = %d, want 0", state.CurrentTicket)
+	if got := Progress(state, &sprint.Tickets[0]).CurrentTask; got != 1 {
+		t.Errorf("CurrentTask = %d, want 1", got)
 	}
-	if state.CurrentTask != 0 {
-		t.Errorf("CurrentTask = %d, want 0", state.CurrentTask)
+	if _, ok := state.Tickets["ticket-2"]; ok {
+		t.Error("Advance should not touch other tickets")
 	}
 }
 
-func TestRecordPass_ResetsFailureCountAndAdvances(t *testing.T) {
+func TestAdvance_StopsAtEnd(t *testing.T) {
 	sprint := &domain.Sprint{
 		Tickets: []domain.Ticket{
-			{
-				Name: "ticket-1",
-				Tasks: []domain.Task{
-					{Description: "task-0"},
-					{Description: "task-1"},
-				},
-			},
+			{Name: "ticket-1", Tasks: []domain.Task{{Description: "task-0"}}},
 		},
 	}
-	state := &domain.State{CurrentTicket: 0, CurrentTask: 0, FailureCount: 2}
+	state := &domain.State{}
 
-	RecordPass(state, sprint)
+	Advance(state, &sprint.Tickets[0])
+	Advance(state, &sprint.Tickets[0])
 
-	if state.FailureCount != 0 {
-		t.Errorf("FailureCount = %d, want 0", state.FailureCount)
+	if got := Progress(state, &sprint.Tickets[0]).CurrentTask; got != 1 {
+		t.Errorf("CurrentTask = %d, want 1", got)
 	}
-	if state.CurrentTicket != 0 {
-		t.Errorf("CurrentTicket = %d, want 0", state.CurrentTicket)
+	if info := NextTask(state, sprint); info != nil {
+		t.Error("expected NextTask to return nil after advancing past end")
 	}
-	if state.CurrentTask != 1 {
-		t.Errorf("CurrentTask = %d, want 1", state.CurrentTask)
+	if !SprintComplete(state, sprint) {
+		t.Error("SprintComplete = false, want true")
 	}
 }
 
-func TestRecordPass_AdvancesAcrossTicketBoundary(t *testing.T) {
-	sprint := &domain.Sprint{
-		Tickets: []domain.Ticket{
-			{
-				Name: "ticket-1",
-				Tasks: []domain.Task{
-					{Description: "task-0"},
-					{Description: "task-1"},
-					{Description: "task-2"},
-				},
+func TestRecordPass_ResetsFailureCountAndAdvances(t *testing.T) {
+	sprint := twoTicketSprint()
+	state := stateWith(map[string]domain.TicketState{"ticket-1": {FailingTask: "task-0", FailureCount: 2}})
+
+	RecordPass(state, &sprint.Tickets[0])
+
+	got := Progress(state, &sprint.Tickets[0])
+	if got.FailureCount != 0 {
+		t.Errorf("FailureCount = %d, want 0", got.FailureCount)
+	}
+	if got.CurrentTask != 1 {
+		t.Errorf("CurrentTask = %d, want 1", got.CurrentTask)
+	}
+}
+
+func TestRecordPass_CompletesTicket(t *testing.T) {
+	sprint := twoTicketSprint()
+	state := stateWith(map[string]domain.TicketState{"ticket-1": {Done: []string{"task-0"}, FailingTask: "task-1", FailureCount: 1}})
+
+	RecordPass(state, &sprint.Tickets[0])
+
+	if !TicketComplete(state, &sprint.Tickets[0]) {
+		t.Error("TicketComplete = false, want true")
+	}
+	info := NextTask(state, sprint)
+	if info == nil || info.TicketIndex != 1 {
+		t.Errorf("expected next ticket after completion, got %+v", info)
+	}
+}
+
+func TestRecordFail_IncrementsFailureCount(t *testing.T) {
+	sprint := twoTicketSprint()
+	state := &domain.State{}
+
+	RecordFail(state, &sprint.Tickets[0])
+
+	if got := Progress(state, &sprint.Tickets[0]).FailureCount; got != 1 {
+		t.Errorf("FailureCount = %d, want 1", got)
+	}
+}
+
+func TestRecordFail_PositionUnchanged(t *testing.T) {
+	sprint := twoTicketSprint()
+	state := stateWith(map[string]domain.TicketState{"ticket-1": {Done: []string{"task-0"}, FailingTask: "task-1", FailureCount: 2}})
+
+	RecordFail(state, &sprint.Tickets[0])
+
+	got := Progress(state, &sprint.Tickets[0])
+	if got.CurrentTask != 1 {
+		t.Errorf("CurrentTask = %d, want 1", got.CurrentTask)
+	}
+	if got.FailureCount != 3 {
+		t.Errorf("FailureCount = %d, want 3", got.FailureCount)
+	}
+}
+
+func TestProgress_FollowsTasksByKey(t *testing.T) {
+	ticket := &domain.Ticket{Name: "ticket-1", Tasks: []domain.Task{
+		{Description: "new"},
+		{Description: "task-1"},
+		{Description: "task-0"},
+	}}
+	state := stateWith(map[string]domain.TicketState{
+		"ticket-1": {Done: []string{"task-0", "task-1"}},
+	})
+
+	got := Progress(state, ticket)
+	if got.CurrentTask != 0 || got.TasksDone != 2 {
+		t.Errorf("progress = %+v, want CurrentTask 0 and TasksDone 2", got)
+	}
+
+	RecordPass(state, ticket)
+	if !TicketComplete(state, ticket) {
+		t.Error("TicketComplete = false after passing the inserted task, want true")
+	}
+}
+
+func TestProgress_UsesTicketID(t *testing.T) {
+	ticket := &domain.Ticket{ID: "T-1", Name: "renamed", Tasks: []domain.Task{{ID: "a", Description: "Reworded"}}}
+	state := stateWith(map[string]domain.TicketState{"T-1": {Done: []string{"a"}}})
+
+	if !TicketComplete(state, ticket) {
+		t.Error("TicketComplete = false, want progress found by ticket and task id")
+	}
+}
+
+func TestMigrateState(t *testing.T) {
+	ticketAt := func(i int) *int { return &i }
+	tests := []struct {
+		name   string
+		legacy domain.State
+		want   map[string]domain.TicketState
+	}{
+		{
+			name:   "fresh",
+			legacy: domain.State{LegacyTicket: ticketAt(0)},
+			want:   nil,
+		},
+		{
+			name:   "mid first ticket with failures",
+			legacy: domain.State{LegacyTicket: ticketAt(0), LegacyTask: 1, LegacyFailures: 2},
+			want: map[string]domain.TicketState{
+				"ticket-1": {Done: []string{"task-0"}, FailingTask: "task-1", FailureCount: 2},
 			},
-			{
-				Name: "ticket-2",
-				Tasks: []domain.Task{
-					{Description: "task-0"},
-				},
+		},
+		{
+			name:   "second ticket",
+			legacy: domain.State{LegacyTicket: ticketAt(1)},
+			want: map[string]domain.TicketState{
+				"ticket-1": {Done: []string{"task-0", "task-1"}},
+			},
+		},
+		{
+			name:   "complete",
+			legacy: domain.State{LegacyTicket: ticketAt(2)},
+			want: map[string]domain.TicketState{
+				"ticket-1": {Done: []string{"task-0", "task-1"}},
+				"ticket-2": {Done: []string{"task-0"}},
 			},
 		},
 	}
-	state := &domain.State{CurrentTicket: 0, CurrentTask: 2, FailureCount: 1}
 
-	RecordPass(state, sprint)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := tt.legacy
+			if !MigrateState(&state, twoTicketSprint()) {
+				t.Fatal("MigrateState = false, want true")
+			}
+			if !reflect.DeepEqual(state.Tickets, tt.want) {
+				t.Errorf("Tickets = %+v, want %+v", state.Tickets, tt.want)
+			}
+			if state.LegacyTicket != nil || state.LegacyTask != 0 || state.LegacyFailures != 0 {
+				t.Errorf("legacy cursor kept: %+v", state)
+			}
+		})
+	}
+}
 
-	if state.FailureCount != 0 {
-		t.Errorf("FailureCount = %d, want 0", state.FailureCount)
+func TestMigrateState_CurrentFormat(t *testing.T) {
+	state := stateWith(map[string]domain.TicketState{"ticket-1": {Done: []string{"task-0"}}})
+	if MigrateState(state, twoTicketSprint()) {
+		t.Error("MigrateState = true, want false for per-ticket state")
 	}
-	if state.CurrentTicket != 1 {
-		t.Errorf("CurrentTicket = %d, want 1", state.CurrentTicket)
+}
+
+func TestProgress_IgnoresFailuresOnOtherTask(t *testing.T) {
+	sprint := twoTicketSprint()
+	state := stateWith(map[string]domain.TicketState{
+		"ticket-1": {FailingTask: "task-1", FailureCount: 3},
+	})
+
+	if got := Progress(state, &sprint.Tickets[0]).FailureCount; got != 0 {
+		t.Errorf("FailureCount = %d, want 0 when failures belong to another task", got)
 	}
-	if state.CurrentTask != 0 {
-		t.Errorf("CurrentTask = %d, want 0", state.CurrentTask)
+
+	RecordFail(state, &sprint.Tickets[0])
+	if got := Progress(state, &sprint.Tickets[0]).FailureCount; got != 1 {
+		t.Errorf("FailureCount = %d, want 1 after first failure on the current task", got)
 	}
 }
 
-func TestRecordFail_IncrementsFailureCount(t *testing.T) {
-	state := &domain.State{CurrentTicket: 0, CurrentTask: 0, FailureCount: 0}
+func TestSkip_AdvancesAndClearsFailures(t *testing.T) {
+	sprint := twoTicketSprint()
+	state := stateWith(map[string]domain.TicketState{"ticket-1": {FailingTask: "task-0", FailureCount: 3}})
+
+	Skip(state, &sprint.Tickets[0])
 
-	RecordFail(state)
+	got := Progress(state, &sprint.Tickets[0])
+	if got.CurrentTask != 1 || got.FailureCount != 0 {
+		t.Errorf("progress = %+v, want CurrentTask 1 and FailureCount 0", got)
+	}
+	if got.TasksDone != 1 || got.TasksSkipped != 1 {
+		t.Errorf("progress = %+v, want TasksDone 1 and TasksSkipped 1", got)
+	}
+	if skipped := SkippedTasks(state, &sprint.Tickets[0]); len(skipped) != 1 || skipped[0].Description != "task-0" {
+		t.Errorf("SkippedTasks = %v, want [task-0]", skipped)
+	}
 
-	if state.FailureCount != 1 {
-		t.Errorf("FailureCount = %d, want 1", state.FailureCount)
+	RecordPass(state, &sprint.Tickets[0])
+	if got := Progress(state, &sprint.Tickets[0]); got.TasksDone != 2 || got.TasksSkipped != 1 {
+		t.Errorf("progress after a pass = %+v, want TasksDone 2 and TasksSkipped 1", got)
 	}
 }
 
-func TestRecordFail_IncrementsFromExistingCount(t *testing.T) {
-	state := &domain.State{CurrentTicket: 0, CurrentTask: 0, FailureCount: 2}
+func TestGoto_KeepsSkippedTasksThatStayDone(t *testing.T) {
+	sprint := twoTicketSprint()
+	state := stateWith(map[string]domain.TicketState{"ticket-1": {Done: []string{"task-0", "task-1"}, Skipped: []string{"task-0", "task-1"}}})
 
-	RecordFail(state)
+	Goto(state, &sprint.Tickets[0], 1)
 
-	if state.FailureCount != 3 {
-		t.Errorf("FailureCount = %d, want 3", state.FailureCount)
+	want := domain.TicketState{Done: []string{"task-0"}, Skipped: []string{"task-0"}}
+	if got := state.Tickets["ticket-1"]; !reflect.DeepEqual(got, want) {
+		t.Errorf("ticket-1 = %+v, want %+v", got, want)
 	}
 }
 
-func TestRecordFail_PositionUnchanged(t *testing.T) {
-	state := &domain.State{CurrentTicket: 1, CurrentTask: 2, FailureCount: 0}
+func TestResetFailures_KeepsPosition(t *testing.T) {
+	sprint := twoTicketSprint()
+	state := stateWith(map[string]domain.TicketState{"ticket-1": {Done: []string{"task-0"}, FailingTask: "task-1", FailureCount: 3}})
+
+	ResetFailures(state, &sprint.Tickets[0])
+
+	got := Progress(state, &sprint.Tickets[0])
+	if got.CurrentTask != 1 || got.FailureCount != 0 {
+		t.Errorf("progress = %+v, want CurrentTask 1 and FailureCount 0", got)
+	}
+	if IsStuck(state, sprint, &sprint.Tickets[0]) {
+		t.Error("IsStuck = true after ResetFailures, want false")
+	}
+}
+
+func TestGoto(t *testing.T) {
+	tests := []struct {
+		name      string
+		taskIndex int
+		want      int
+	}{
+		{"rewinds", 0, 0},
+		{"moves forward", 1, 1},
+		{"completes at end", 2, 2},
+		{"clamps past end", 9, 2},
+		{"clamps negative", -1, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sprint := twoTicketSprint()
+			state := stateWith(map[string]domain.TicketState{"ticket-1": {Done: []string{"task-0"}, FailingTask: "task-1", FailureCount: 2}})
+
+			Goto(state, &sprint.Tickets[0], tt.taskIndex)
+
+			got := Progress(state, &sprint.Tickets[0])
+			if got.CurrentTask != tt.want {
+				t.Errorf("CurrentTask = %d, want %d", got.CurrentTask, tt.want)
+			}
+			if got.FailureCount != 0 {
+				t.Errorf("FailureCount = %d, want 0", got.FailureCount)
+			}
+		})
+	}
+}
+
+func TestReset_ForgetsProgress(t *testing.T) {
+	sprint := twoTicketSprint()
+	state := stateWith(map[string]domain.TicketState{
+		"ticket-1": {Done: []string{"task-0", "task-1"}},
+		"ticket-2": {FailingTask: "task-0", FailureCount: 1},
+	})
+
+	Reset(state, &sprint.Tickets[0])
+
+	if _, ok := state.Tickets["ticket-1"]; ok {
+		t.Error("ticket-1 progress kept, want removed")
+	}
+	if state.Tickets["ticket-2"].FailureCount != 1 {
+		t.Error("Reset changed another ticket's progress")
+	}
+}
+
+func TestIsStuck(t *testing.T) {
+	sprint := twoTicketSprint()
+	ticket := &sprint.Tickets[0]
+	tests := []struct {
+		failures int
+		want     bool
+	}{
+		{0, false},
+		{2, false},
+		{3, true},
+		{5, true},
+	}
+
+	for _, tt := range tests {
+		state := stateWith(map[string]domain.TicketState{"ticket-1": {FailingTask: "task-0", FailureCount: tt.failures}})
+		if got := IsStuck(state, sprint, ticket); got != tt.want {
+			t.Errorf("IsStuck(failures=%d) = %v, want %v", tt.failures, got, tt.want)
+		}
+	}
+}
+
+func TestIsStuck_HonoursMaxAttempts(t *testing.T) {
+	sprint := twoTicketSprint()
+	sprint.MaxAttempts = 5
+	sprint.Tickets[0].Tasks[1].MaxAttempts = 1
+	ticket := &sprint.Tickets[0]
+
+	state := stateWith(map[string]domain.TicketState{"ticket-1": {FailingTask: "task-0", FailureCount: 4}})
+	if IsStuck(state, sprint, ticket) {
+		t.Error("IsStuck = true, want false below sprint max_attempts of 5")
+	}
 
-	RecordFail(state)
+	state.Tickets["ticket-1"] = domain.TicketState{FailingTask: "task-0", FailureCount: 5}
+	if !IsStuck(state, sprint, ticket) {
+		t.Error("IsStuck = false, want true at sprint max_attempts of 5")
+	}
 
-	if state.CurrentTicket != 1 {
-		t.Errorf("CurrentTicket = %d, want 1", state.CurrentTicket)
+	state.Tickets["ticket-1"] = domain.TicketState{Done: []string{"task-0"}, FailingTask: "task-1", FailureCount: 1}
+	if !IsStuck(state, sprint, ticket) {
+		t.Error("IsStuck = false, want true at task max_attempts of 1")
 	}
-	if state.CurrentTask != 2 {
-		t.Errorf("CurrentTask = %d, want 2", state.CurrentTask)
+
+	state.Tickets["ticket-1"] = domain.TicketState{Done: []string{"task-0", "task-1"}, FailingTask: "task-1", FailureCount: 9}
+	if IsStuck(state, sprint, ticket) {
+		t.Error("IsStuck = true, want false for complete ticket")
 	}
 }
 
-func TestIsStuck_FalseWhenZeroFailures(t *testing.T) {
-	state := &domain.State{FailureCount: 0}
+func TestMaxAttempts_DefaultsToStuckThreshold(t *testing.T) {
+	sprint := twoTicketSprint()
+	info := TicketTask(&domain.State{}, sprint, 0)
+
+	if got := MaxAttempts(sprint, info); got != StuckThreshold {
+		t.Errorf("MaxAttempts = %d, want %d", got, StuckThreshold)
+	}
 
-	if IsStuck(state) {
-		t.Error("IsStuck = true, want false")
+	sprint.Tickets[0].MaxAttempts = 2
+	if got := MaxAttempts(sprint, info); got != 2 {
+		t.Errorf("MaxAttempts = %d, want 2", got)
 	}
 }
 
-func TestIsStuck_FalseWhenBelowThreshold(t *testing.T) {
-	state := &domain.State{FailureCount: 2}
+func TestRetryDelay(t *testing.T) {
+	sprint := twoTicketSprint()
+	info := TicketTask(&domain.State{}, sprint, 0)
+
+	if got := RetryDelay(sprint, info, 1); got != 0 {
+		t.Errorf("RetryDelay without retry_delay = %v, want 0", got)
+	}
+
+	sprint.RetryDelay = domain.Duration(10 * time.Second)
+	tests := []struct {
+		backoff  float64
+		failures int
+		want     time.Duration
+	}{
+		{0, 1, 10 * time.Second},
+		{0, 3, 10 * time.Second},
+		{2, 1, 10 * time.Second},
+		{2, 3, 40 * time.Second},
+		{1000, 5, MaxRetryDelay},
+	}
 
-	if IsStuck(state) {
-		t.Error("IsStuck = true, want false")
+	for _, tt := range tests {
+		sprint.Tickets[0].RetryBackoff = tt.backoff
+		if got := RetryDelay(sprint, info, tt.failures); got != tt.want {
+			t.Errorf("RetryDelay(backoff=%v, failures=%d) = %v, want %v", tt.backoff, tt.failures, got, tt.want)
+		}
 	}
 }
 
-func TestIsStuck_TrueAtThreshold(t *testing.T) {
-	state := &domain.State{FailureCount: 3}
+func TestTimeouts_MostSpecificWins(t *testing.T) {
+	sprint := &domain.Sprint{
+		Timeout:     domain.Duration(time.Hour),
+		IdleTimeout: domain.Duration(10 * time.Minute),
+		Tickets: []domain.Ticket{{
+			Name:    "ticket-1",
+			Timeout: domain.Duration(30 * time.Minute),
+			Tasks: []domain.Task{
+				{Description: "task-0"},
+				{Description: "task-1", Timeout: domain.Duration(5 * time.Minute), IdleTimeout: domain.Duration(time.Minute)},
+			},
+		}},
+	}
+
+	timeout, idle := Timeouts(sprint, TicketTask(&domain.State{}, sprint, 0))
+	if timeout != 30*time.Minute || idle != 10*time.Minute {
+		t.Errorf("Timeouts(task-0) = (%v, %v), want (30m0s, 10m0s)", timeout, idle)
+	}
+
+	state := stateWith(map[string]domain.TicketState{"ticket-1": {Done: []string{"task-0"}}})
+	timeout, idle = Timeouts(sprint, TicketTask(state, sprint, 0))
+	if timeout != 5*time.Minute || idle != time.Minute {
+		t.Errorf("Timeouts(task-1) = (%v, %v), want (5m0s, 1m0s)", timeout, idle)
+	}
 
-	if !IsStuck(state) {
-		t.Error("IsStuck = false, want true")
+	timeout, idle = Timeouts(&domain.Sprint{}, TicketTask(state, sprint, 0))
+	if timeout != 5*time.Minute || idle != time.Minute {
+		t.Errorf("Timeouts(no sprint defaults) = (%v, %v), want (5m0s, 1m0s)", timeout, idle)
 	}
 }
 
-func TestIsStuck_TrueAboveThreshold(t *testing.T) {
-	state := &domain.State{FailureCount: 5}
+func TestVerifyTimeout_FallsBackToSessionTimeout(t *testing.T) {
+	sprint := &domain.Sprint{
+		Tickets: []domain.Ticket{{
+			Name: "ticket-1",
+			Tasks: []domain.Task{
+				{Description: "task-0", VerifyTimeout: domain.Duration(time.Minute), Timeout: domain.Duration(time.Hour)},
+				{Description: "task-1"},
+			},
+		}},
+	}
 
-	if !IsStuck(state) {
-		t.Error("IsStuck = false, want true")
+	info := TicketTask(&domain.State{}, sprint, 0)
+	if got := VerifyTimeout(sprint, info); got != time.Minute {
+		t.Errorf("VerifyTimeout(verify_timeout set) = %v, want 1m0s", got)
+	}
+
+	state := stateWith(map[string]domain.TicketState{"ticket-1": {Done: []string{"task-0"}}})
+	info = TicketTask(state, sprint, 0)
+	if got := VerifyTimeout(sprint, info); got != DefaultVerifyTimeout {
+		t.Errorf("VerifyTimeout(nothing set) = %v, want %v", got, DefaultVerifyTimeout)
+	}
+	sprint.Timeout = domain.Duration(45 * time.Minute)
+	if got := VerifyTimeout(sprint, info); got != 45*time.Minute {
+		t.Errorf("VerifyTimeout(sprint timeout) = %v, want 45m0s", got)
+	}
+}
+
+func TestQuestionTimeout_DefaultsToTenMinutes(t *testing.T) {
+	if got := QuestionTimeout(&domain.Sprint{}); got != DefaultQuestionTimeout {
+		t.Errorf("QuestionTimeout(unset) = %v, want %v", got, DefaultQuestionTimeout)
+	}
+	if got := QuestionTimeout(&domain.Sprint{QuestionTimeout: domain.Duration(time.Minute)}); got != time.Minute {
+		t.Errorf("QuestionTimeout(1m) = %v, want 1m0s", got)
+	}
+}
+
+func TestModel_EscalatesByAttempt(t *testing.T) {
+	sprint := twoTicketSprint()
+	info := TicketTask(&domain.State{}, sprint, 0)
+
+	if got := Model(sprint, info, 0); got != "" {
+		t.Errorf("Model without config = %q, want empty", got)
+	}
+
+	sprint.Model = "sonnet"
+	sprint.Tickets[0].Escalation = []string{"haiku", "sonnet", "opus"}
+	tests := []struct {
+		failures int
+		want     string
+	}{
+		{0, "haiku"},
+		{1, "sonnet"},
+		{2, "opus"},
+		{5, "opus"},
+	}
+	for _, tt := range tests {
+		if got := Model(sprint, info, tt.failures); got != tt.want {
+			t.Errorf("Model(failures=%d) = %q, want %q", tt.failures, got, tt.want)
+		}
+	}
+
+	info.Task.Model = "opus"
+	if got := Model(sprint, info, 0); got != "opus" {
+		t.Errorf("Model with task model = %q, want %q", got, "opus")
+	}
+}
+
+func TestEffort_MostSpecificWins(t *testing.T) {
+	sprint := twoTicketSprint()
+	sprint.Effort = domain.EffortLow
+	info := TicketTask(&domain.State{}, sprint, 0)
+
+	if got := Effort(sprint, info); got != domain.EffortLow {
+		t.Errorf("Effort = %q, want %q", got, domain.EffortLow)
+	}
+	info.Task.Effort = domain.EffortHigh
+	if got := Effort(sprint, info); got != domain.EffortHigh {
+		t.Errorf("Effort = %q, want %q", got, domain.EffortHigh)
+	}
+}
+
+func TestPlan_MostSpecificWins(t *testing.T) {
+	sprint := twoTicketSprint()
+	info := TicketTask(&domain.State{}, sprint, 0)
+	on, off := true, false
+
+	if Plan(sprint, info) {
+		t.Error("Plan without config = true, want false")
+	}
+	sprint.Plan = &on
+	if !Plan(sprint, info) {
+		t.Error("Plan with sprint plan = false, want true")
+	}
+	info.Task.Plan = &off
+	if Plan(sprint, info) {
+		t.Error("Plan with task opt-out = true, want false")
+	}
+}
+
+func TestReview_MostSpecificWins(t *testing.T) {
+	sprint := twoTicketSprint()
+	info := TicketTask(&domain.State{}, sprint, 0)
+	on := true
+
+	if Review(sprint, info) {
+		t.Error("Review without config = true, want false")
+	}
+	info.Ticket.Review = &on
+	if !Review(sprint, info) {
+		t.Error("Review with ticket review = false, want true")
 	}
 }
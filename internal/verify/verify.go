@@ -0,0 +1,103 @@
+package verify
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"runtime"
+	"time"
+
+	"github.com/sqve/kamaji/internal/domain"
+	"github.com/sqve/kamaji/internal/process"
+)
+
+// Result describes the outcome of a verification command.
+type Result struct {
+	Passed   bool
+	ExitCode int
+	Output   string // combined stdout and stderr
+	Reason   string // why the check failed, empty when passed
+}
+
+// Run executes command in workDir through the platform shell and evaluates it
+// against done. A nil done requires exit code 0. A command still running after
+// timeout is stopped and fails the check; zero means no limit. Returns an error
+// only when the command could not be run at all or ctx is cancelled; a failing
+// check is reported via Result.
+func Run(ctx context.Context, workDir, command string, done *domain.Done, timeout time.Duration) (Result, error) {
+	if workDir == "" {
+		return Result{}, errors.New("workDir required")
+	}
+	if command == "" {
+		return Result{}, errors.New("command required")
+	}
+
+	var re *regexp.Regexp
+	if done != nil && done.Output != "" {
+		compiled, err := regexp.Compile(done.Output)
+		if err != nil {
+			return Result{}, fmt.Errorf("compile done.output: %w", err)
+		}
+		re = compiled
+	}
+
+	runCtx := ctx
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		runCtx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	cmd := shellCommand(runCtx, command)
+	cmd.Dir = workDir
+	process.KillGroupOnCancel(cmd)
+
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+
+	runErr := cmd.Run()
+	if ctx.Err() != nil {
+		return Result{}, ctx.Err()
+	}
+	if runCtx.Err() != nil {
+		return Result{ExitCode: -1, Output: out.String(), Reason: fmt.Sprintf("timed out after %s", timeout)}, nil
+	}
+
+	exitCode := 0
+	if runErr != nil {
+		var exitErr *exec.ExitError
+		if !errors.As(runErr, &exitErr) {
+			return Result{}, fmt.Errorf("run verify command: %w", runErr)
+		}
+		exitCode = exitErr.ExitCode()
+	}
+
+	result := Result{ExitCode: exitCode, Output: out.String()}
+
+	wantCode := 0
+	if done != nil && done.ExitCode != nil {
+		wantCode = *done.ExitCode
+	}
+
+	switch {
+	case exitCode != wantCode:
+		result.Reason = fmt.Sprintf("exit code %d, want %d", exitCode, wantCode)
+	case re != nil && !re.MatchString(result.Output):
+		result.Reason = fmt.Sprintf("output does not match %q", done.Output)
+	default:
+		result.Passed = true
+	}
+
+	return result, nil
+}
+
+func shellCommand(ctx context.Context, command string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.CommandContext(ctx, "cmd", "/C", command) // #nosec G204 -- command comes from user sprint config
+	}
+	return exec.CommandContext(ctx, "sh", "-c", command) // #nosec G204 -- command comes from user sprint config
+}
@@ -0,0 +1,145 @@
+package verify
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sqve/kamaji/internal/domain"
+	"github.com/sqve/kamaji/internal/testutil"
+)
+
+func TestRun_ExitZeroPasses(t *testing.T) {
+	testutil.SkipOnWindows(t, "uses POSIX shell commands")
+	dir := t.TempDir()
+
+	result, err := Run(context.Background(), dir, "echo ok", nil, 0)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if !result.Passed {
+		t.Errorf("Passed = false, want true (reason: %s)", result.Reason)
+	}
+	if strings.TrimSpace(result.Output) != "ok" {
+		t.Errorf("Output = %q, want %q", result.Output, "ok")
+	}
+}
+
+func TestRun_NonZeroExitFails(t *testing.T) {
+	testutil.SkipOnWindows(t, "uses POSIX shell commands")
+	dir := t.TempDir()
+
+	result, err := Run(context.Background(), dir, "echo broken >&2; exit 3", nil, 0)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if result.Passed {
+		t.Error("Passed = true, want false for non-zero exit")
+	}
+	if result.ExitCode != 3 {
+		t.Errorf("ExitCode = %d, want 3", result.ExitCode)
+	}
+	testutil.AssertContains(t, result.Output, "broken")
+	testutil.AssertContains(t, result.Reason, "exit code 3")
+}
+
+func TestRun_ExpectedExitCode(t *testing.T) {
+	testutil.SkipOnWindows(t, "uses POSIX shell commands")
+	dir := t.TempDir()
+	code := 1
+
+	result, err := Run(context.Background(), dir, "exit 1", &domain.Done{ExitCode: &code}, 0)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if !result.Passed {
+		t.Errorf("Passed = false, want true (reason: %s)", result.Reason)
+	}
+}
+
+func TestRun_OutputRegex(t *testing.T) {
+	testutil.SkipOnWindows(t, "uses POSIX shell commands")
+	dir := t.TempDir()
+	done := &domain.Done{Output: `\d+ passed, 0 failed`}
+
+	result, err := Run(context.Background(), dir, "echo '12 passed, 0 failed'", done, 0)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if !result.Passed {
+		t.Errorf("Passed = false, want true (reason: %s)", result.Reason)
+	}
+
+	result, err = Run(context.Background(), dir, "echo '11 passed, 1 failed'", done, 0)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if result.Passed {
+		t.Error("Passed = true, want false when output does not match")
+	}
+	testutil.AssertContains(t, result.Reason, "does not match")
+}
+
+func TestRun_RunsInWorkDir(t *testing.T) {
+	testutil.SkipOnWindows(t, "uses POSIX shell commands")
+	dir := t.TempDir()
+
+	result, err := Run(context.Background(), dir, "pwd", nil, 0)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	testutil.AssertPathEqual(t, strings.TrimSpace(result.Output), dir)
+}
+
+func TestRun_InvalidRegex(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := Run(context.Background(), dir, "true", &domain.Done{Output: "("}, 0)
+	if err == nil {
+		t.Error("Run() error = nil, want error for invalid regex")
+	}
+}
+
+func TestRun_RequiresArguments(t *testing.T) {
+	if _, err := Run(context.Background(), "", "true", nil, 0); err == nil {
+		t.Error("Run() error = nil, want error for empty workDir")
+	}
+	if _, err := Run(context.Background(), t.TempDir(), "", nil, 0); err == nil {
+		t.Error("Run() error = nil, want error for empty command")
+	}
+}
+
+func TestRun_TimeoutFailsCheck(t *testing.T) {
+	testutil.SkipOnWindows(t, "uses POSIX shell commands")
+	dir := t.TempDir()
+
+	start := time.Now()
+	result, err := Run(context.Background(), dir, "echo started; sleep 5", nil, 100*time.Millisecond)
+	if err != nil {
+		t.Fatalf("Run() error = %v, want a failed check", err)
+	}
+	if result.Passed {
+		t.Error("Passed = true, want false")
+	}
+	if result.Reason != "timed out after 100ms" {
+		t.Errorf("Reason = %q, want %q", result.Reason, "timed out after 100ms")
+	}
+	if !strings.Contains(result.Output, "started") {
+		t.Errorf("Output = %q, want the output before the timeout", result.Output)
+	}
+	if elapsed := time.Since(start); elapsed > 3*time.Second {
+		t.Errorf("Run() took %v, want it stopped at the timeout", elapsed)
+	}
+}
+
+func TestRun_ContextCancelled(t *testing.T) {
+	testutil.SkipOnWindows(t, "uses POSIX shell commands")
+	dir := t.TempDir()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := Run(ctx, dir, "sleep 5", nil, 0); err == nil {
+		t.Error("Run() error = nil, want error for cancelled context")
+	}
+}